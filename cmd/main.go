@@ -2,17 +2,27 @@ package main
 
 import (
 	"log"
+	"os"
 
+	"nearbyassist/internal/activity"
 	"nearbyassist/internal/authenticator"
+	"nearbyassist/internal/autocomplete"
 	"nearbyassist/internal/config"
 	"nearbyassist/internal/db"
+	"nearbyassist/internal/db/migrations"
 	"nearbyassist/internal/encryption"
+	"nearbyassist/internal/events"
 	"nearbyassist/internal/hash"
+	"nearbyassist/internal/jobs"
+	"nearbyassist/internal/notification"
+	"nearbyassist/internal/payment"
+	"nearbyassist/internal/receipt"
 	"nearbyassist/internal/routes"
 	"nearbyassist/internal/routing_engine"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/storage"
 	"nearbyassist/internal/suggestion_engine"
+	"nearbyassist/internal/webhook"
 	"nearbyassist/internal/websocket"
 )
 
@@ -20,6 +30,14 @@ func main() {
 	// Load configuration file
 	config := config.LoadConfig()
 
+	// Run migration subcommand instead of the server, ex: main migrate up
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrations.RunCommand(config, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Load file store
 	store := storage.NewStorage(config)
 	store.Initialize()
@@ -27,30 +45,78 @@ func main() {
 	// Load database configuration
 	db := db.NewDatabase(config)
 
+	// Apply pending migrations before serving requests
+	if config.AutoMigrate {
+		if err := migrations.AutoMigrate(config); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Load authenticator configuration
 	auth := authenticator.NewJWTAuthenticator(config)
 
 	// Load websocket configuration
 	ws := websocket.NewWebsocket(db)
 
-	// Load Routing Engine configuration
-	engine := routing_engine.NewOSRM(config)
+	// Load Routing Engine configuration, straight line estimates are used while OSRM is down
+	breaker := routing_engine.NewBreaker(routing_engine.NewOSRM(config), routing_engine.NewFallback(), routing_engine.BREAKER_FAILURE_THRESHOLD, routing_engine.BREAKER_COOLDOWN)
+	engine := routing_engine.NewCache(breaker, routing_engine.ROUTE_CACHE_TTL, routing_engine.ROUTE_CACHE_MAX_ENTRIES)
 
 	// Load Suggestion Engine configuration
 	courtier := suggestion_engine.NewCourtier()
 
+	// Load tag autocomplete, rebuilt in the background when tags change
+	suggestions := autocomplete.NewIndex(db)
+
 	// Load encryption configuration
 	crypto := encryption.NewAes(config)
 
 	// Load hashing algorithm
 	hash := hash.NewSha()
 
+	// Load notification delivery, push is only enabled when an endpoint is configured
+	notifiers := []notification.Notifier{notification.NewWebsocketNotifier(ws)}
+	if config.PushUrl != "" {
+		notifiers = append(notifiers, notification.NewPushNotifier(config, db))
+	}
+	notifications := notification.NewCenter(db, notifiers...)
+
+	// Load payment provider, money is held in escrow until the job is done.
+	// Payment routes are left out while no provider is configured
+	payments, err := payment.NewProvider(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Load receipt generation, stored encrypted like other documents
+	receipts := receipt.NewGenerator(db, store, crypto)
+
+	// Load view and impression counting for vendor stats, written in batches
+	recorder := activity.NewRecorder(db)
+
+	// Load domain event subscribers, events are read from the outbox
+	bus := events.NewBus()
+	events.SubscribeRating(bus, db)
+	notification.Subscribe(bus, notifications, ws)
+	webhook.Subscribe(bus, db)
+	receipt.Subscribe(bus, db, receipts)
+	dispatcher := events.NewDispatcher(db, bus)
+
+	// Load partner webhook delivery
+	webhooks := webhook.NewDeliverer(db, crypto)
+
 	// Create and start the server
-	server := server.NewServer(config, ws, db, store, auth, engine, courtier, crypto, hash)
+	server := server.NewServer(config, ws, db, store, auth, engine, courtier, suggestions, crypto, hash, notifications, payments, receipts, recorder)
 	routes.RegisterRoutes(server)
 
 	go server.Websocket.SaveMessages()
 	go server.Websocket.ForwardMessages()
+	go jobs.RunRestrictionExpiry(db, jobs.RESTRICTION_EXPIRY_INTERVAL)
+	go jobs.RunAnalyticsRefresh(db, jobs.ANALYTICS_REFRESH_INTERVAL)
+	go dispatcher.Run()
+	go webhooks.Run()
+	go suggestions.Run(autocomplete.AUTOCOMPLETE_REFRESH_INTERVAL)
+	go recorder.Run(activity.ACTIVITY_FLUSH_INTERVAL)
 
 	if err := server.Start(); err != nil {
 		log.Fatal(err)
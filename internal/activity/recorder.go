@@ -0,0 +1,108 @@
+package activity
+
+import (
+	"log"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+	"sync"
+	"time"
+)
+
+const ACTIVITY_FLUSH_INTERVAL = 30 * time.Second
+
+type counterKey struct {
+	subject models.ActivitySubject
+	id      int
+	day     string
+}
+
+// Counts profile views, service views and search impressions in memory and
+// writes them in batches, so browsing and searching never wait on an insert.
+// Counts that were not flushed yet are lost when the process stops, which is
+// acceptable for statistics
+type Recorder struct {
+	db       db.Database
+	mu       sync.Mutex
+	counters map[counterKey]*models.ActivityCounterModel
+	now      func() time.Time
+}
+
+func NewRecorder(db db.Database) *Recorder {
+	return &Recorder{
+		db:       db,
+		counters: make(map[counterKey]*models.ActivityCounterModel),
+		now:      time.Now,
+	}
+}
+
+func (r *Recorder) RecordVendorView(vendorId int) {
+	r.add(r.key(models.ACTIVITY_SUBJECT_VENDOR, vendorId), 1, 0)
+}
+
+func (r *Recorder) RecordServiceView(serviceId int) {
+	r.add(r.key(models.ACTIVITY_SUBJECT_SERVICE, serviceId), 1, 0)
+}
+
+// Every service shown in a search result counts once
+func (r *Recorder) RecordImpressions(serviceIds []int) {
+	for _, id := range serviceIds {
+		r.add(r.key(models.ACTIVITY_SUBJECT_SERVICE, id), 0, 1)
+	}
+}
+
+func (r *Recorder) key(subject models.ActivitySubject, id int) counterKey {
+	return counterKey{subject: subject, id: id, day: r.now().Format(time.DateOnly)}
+}
+
+func (r *Recorder) add(key counterKey, views, impressions int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.counters[key]
+	if !ok {
+		counter = &models.ActivityCounterModel{Subject: key.subject, SubjectId: key.id, Day: key.day}
+		r.counters[key] = counter
+	}
+
+	counter.Views += views
+	counter.Impressions += impressions
+}
+
+// Writes the pending counts, they are kept for the next flush when the write
+// fails
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	pending := r.counters
+	r.counters = make(map[counterKey]*models.ActivityCounterModel)
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	counters := make([]models.ActivityCounterModel, 0, len(pending))
+	for _, counter := range pending {
+		counters = append(counters, *counter)
+	}
+
+	if err := r.db.RecordActivity(counters); err != nil {
+		for key, counter := range pending {
+			r.add(key, counter.Views, counter.Impressions)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Recorder) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := r.Flush(); err != nil {
+			log.Printf("Failed to record activity: %s\n", err.Error())
+		}
+	}
+}
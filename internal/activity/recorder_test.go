@@ -0,0 +1,69 @@
+package activity
+
+import (
+	"errors"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/models"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRecorder(fake *dbtest.Fake, day *time.Time) *Recorder {
+	recorder := NewRecorder(fake)
+	recorder.now = func() time.Time { return *day }
+
+	return recorder
+}
+
+func TestFlushMergesCounts(t *testing.T) {
+	fake := dbtest.NewFake()
+	day := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	recorder := newTestRecorder(fake, &day)
+
+	recorder.RecordServiceView(4)
+	recorder.RecordServiceView(4)
+	recorder.RecordImpressions([]int{4, 5})
+	recorder.RecordVendorView(2)
+
+	assert.NoError(t, recorder.Flush())
+
+	sort.Slice(fake.Activity, func(a, b int) bool {
+		return fake.Activity[a].Subject > fake.Activity[b].Subject || fake.Activity[a].SubjectId < fake.Activity[b].SubjectId
+	})
+
+	assert.Equal(t, []models.ActivityCounterModel{
+		{Subject: models.ACTIVITY_SUBJECT_VENDOR, SubjectId: 2, Day: "2026-10-18", Views: 1},
+		{Subject: models.ACTIVITY_SUBJECT_SERVICE, SubjectId: 4, Day: "2026-10-18", Views: 2, Impressions: 1},
+		{Subject: models.ACTIVITY_SUBJECT_SERVICE, SubjectId: 5, Day: "2026-10-18", Impressions: 1},
+	}, fake.Activity)
+
+	fake.Activity = nil
+	assert.NoError(t, recorder.Flush())
+	assert.Empty(t, fake.Activity)
+}
+
+func TestFailedFlushKeepsCountsOnTheirDay(t *testing.T) {
+	fake := dbtest.NewFake()
+	fake.Err = errors.New("database is down")
+	day := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+	recorder := newTestRecorder(fake, &day)
+
+	recorder.RecordServiceView(4)
+	assert.Error(t, recorder.Flush())
+
+	day = day.Add(time.Hour)
+	recorder.RecordServiceView(4)
+
+	fake.Err = nil
+	assert.NoError(t, recorder.Flush())
+
+	sort.Slice(fake.Activity, func(a, b int) bool { return fake.Activity[a].Day < fake.Activity[b].Day })
+
+	assert.Len(t, fake.Activity, 2)
+	assert.Equal(t, "2026-10-18", fake.Activity[0].Day)
+	assert.Equal(t, 1, fake.Activity[0].Views)
+	assert.Equal(t, "2026-10-19", fake.Activity[1].Day)
+}
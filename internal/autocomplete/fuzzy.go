@@ -0,0 +1,51 @@
+package autocomplete
+
+// Smallest edit distance between the query and any prefix of the term.
+// Adjacent transpositions count as a single edit so "plmuber" still finds
+// "plumber"
+func prefixDistance(query, term []rune) int {
+	rows, cols := len(query)+1, len(term)+1
+
+	d := make([][]int, rows)
+	for i := range d {
+		d[i] = make([]int, cols)
+		d[i][0] = i
+	}
+	for j := 0; j < cols; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i < rows; i++ {
+		for j := 1; j < cols; j++ {
+			cost := 1
+			if query[i-1] == term[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && query[i-1] == term[j-2] && query[i-2] == term[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	best := d[rows-1][0]
+	for _, distance := range d[rows-1] {
+		best = min(best, distance)
+	}
+
+	return best
+}
+
+// Short prefixes match too much when typos are allowed
+func maxEdits(query []rune) int {
+	switch {
+	case len(query) <= 2:
+		return 0
+	case len(query) <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,33 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixDistance(t *testing.T) {
+	cases := []struct {
+		query    string
+		term     string
+		expected int
+	}{
+		{"plu", "plumbing", 0},
+		{"plumbing", "plumbing", 0},
+		{"plmu", "plumbing", 1},
+		{"plumbr", "plumber", 1},
+		{"plimbing", "plumbing", 1},
+		{"xyz", "plumbing", 3},
+		{"plumbings", "plumbing", 1},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, prefixDistance([]rune(c.query), []rune(c.term)), c.query)
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	assert.Equal(t, 0, maxEdits([]rune("pl")))
+	assert.Equal(t, 1, maxEdits([]rune("plumb")))
+	assert.Equal(t, 2, maxEdits([]rune("plumbing")))
+}
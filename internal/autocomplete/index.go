@@ -0,0 +1,266 @@
+package autocomplete
+
+import (
+	"log"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/routing_engine"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Backstop for changes made by other instances
+	AUTOCOMPLETE_REFRESH_INTERVAL = 5 * time.Minute
+)
+
+const (
+	SUGGESTION_TAG      = "tag"
+	SUGGESTION_CATEGORY = "category"
+)
+
+type Suggestion struct {
+	Type    string `json:"type"`
+	Id      int    `json:"id"`
+	Title   string `json:"title"`
+	Matched string `json:"matched,omitempty"`
+	Nearby  int    `json:"nearby"`
+	edits   int
+}
+
+type term struct {
+	text    []rune
+	synonym string
+}
+
+type entry struct {
+	kind      string
+	id        int
+	title     string
+	terms     []term
+	locations []models.Location
+}
+
+// In-memory typeahead over tags and categories. Suggest never touches the
+// database, the entries are rebuilt in the background after Invalidate
+type Index struct {
+	db      db.Database
+	mu      sync.RWMutex
+	entries []entry
+	refresh chan struct{}
+}
+
+func NewIndex(db db.Database) *Index {
+	return &Index{
+		db:      db,
+		entries: make([]entry, 0),
+		refresh: make(chan struct{}, 1),
+	}
+}
+
+// Schedules a rebuild, calls made while one is pending are merged
+func (i *Index) Invalidate() {
+	select {
+	case i.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (i *Index) Run(interval time.Duration) {
+	if err := i.Refresh(); err != nil {
+		log.Printf("Failed to build autocomplete index: %s\n", err.Error())
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-i.refresh:
+		}
+
+		if err := i.Refresh(); err != nil {
+			log.Printf("Failed to refresh autocomplete index: %s\n", err.Error())
+		}
+	}
+}
+
+func (i *Index) Refresh() error {
+	tags, err := i.db.FindAllTags(false)
+	if err != nil {
+		return err
+	}
+
+	synonyms, err := i.db.FindAllTagSynonyms()
+	if err != nil {
+		return err
+	}
+
+	categories, err := i.db.FindAllTagCategories()
+	if err != nil {
+		return err
+	}
+
+	serviceTags, err := i.db.FindServiceTagLocations()
+	if err != nil {
+		return err
+	}
+
+	entries := buildEntries(tags, synonyms, categories, serviceTags)
+
+	i.mu.Lock()
+	i.entries = entries
+	i.mu.Unlock()
+
+	return nil
+}
+
+// Origin is optional, without it every service carrying the tag counts
+func (i *Index) Suggest(query string, origin *models.Location, radius float64, limit int) []Suggestion {
+	needle := []rune(strings.ToLower(strings.TrimSpace(query)))
+	if len(needle) == 0 {
+		return []Suggestion{}
+	}
+
+	allowed := maxEdits(needle)
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	suggestions := make([]Suggestion, 0)
+	for _, e := range i.entries {
+		edits, matched, ok := e.match(needle, allowed)
+		if !ok {
+			continue
+		}
+
+		suggestions = append(suggestions, Suggestion{
+			Type:    e.kind,
+			Id:      e.id,
+			Title:   e.title,
+			Matched: matched,
+			Nearby:  e.nearby(origin, radius),
+			edits:   edits,
+		})
+	}
+
+	// Closer matches first, then the ones with more services around the user
+	sort.SliceStable(suggestions, func(a, b int) bool {
+		if suggestions[a].edits != suggestions[b].edits {
+			return suggestions[a].edits < suggestions[b].edits
+		}
+
+		if suggestions[a].Nearby != suggestions[b].Nearby {
+			return suggestions[a].Nearby > suggestions[b].Nearby
+		}
+
+		return suggestions[a].Title < suggestions[b].Title
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions
+}
+
+// Each word of a title or synonym is a place the query can start matching
+func (e *entry) match(needle []rune, allowed int) (int, string, bool) {
+	best, matched, found := allowed+1, "", false
+
+	for _, t := range e.terms {
+		for start := 0; start < len(t.text); start++ {
+			if start > 0 && t.text[start-1] != ' ' {
+				continue
+			}
+
+			if edits := prefixDistance(needle, t.text[start:]); edits < best {
+				best, matched, found = edits, t.synonym, true
+			}
+		}
+	}
+
+	return best, matched, found
+}
+
+func (e *entry) nearby(origin *models.Location, radius float64) int {
+	if origin == nil {
+		return len(e.locations)
+	}
+
+	count := 0
+	for idx := range e.locations {
+		if routing_engine.Haversine(origin, &e.locations[idx]) <= radius {
+			count++
+		}
+	}
+
+	return count
+}
+
+func buildEntries(tags []models.TagModel, synonyms []models.TagSynonymModel, categories []models.TagCategoryModel, serviceTags []models.ServiceTagLocationModel) []entry {
+	services := make(map[int]models.Location)
+	servicesByTag := make(map[int][]int)
+	for _, st := range serviceTags {
+		services[st.ServiceId] = models.Location{Latitude: st.Latitude, Longitude: st.Longitude}
+		servicesByTag[st.TagId] = append(servicesByTag[st.TagId], st.ServiceId)
+	}
+
+	synonymsByTag := make(map[int][]models.TagSynonymModel)
+	for _, synonym := range synonyms {
+		synonymsByTag[synonym.TagId] = append(synonymsByTag[synonym.TagId], synonym)
+	}
+
+	parents := make(map[int]*int, len(categories))
+	for _, category := range categories {
+		parents[category.Id] = category.ParentId
+	}
+
+	// A category covers the services of its own tags and of its subcategories
+	servicesByCategory := make(map[int]map[int]bool, len(categories))
+	for _, tag := range tags {
+		visited := make(map[int]bool)
+		for category := tag.CategoryId; category != nil && !visited[*category]; category = parents[*category] {
+			visited[*category] = true
+
+			if servicesByCategory[*category] == nil {
+				servicesByCategory[*category] = make(map[int]bool)
+			}
+
+			for _, serviceId := range servicesByTag[tag.Id] {
+				servicesByCategory[*category][serviceId] = true
+			}
+		}
+	}
+
+	entries := make([]entry, 0, len(tags)+len(categories))
+	for _, tag := range tags {
+		e := entry{kind: SUGGESTION_TAG, id: tag.Id, title: tag.Title}
+		e.terms = append(e.terms, term{text: []rune(strings.ToLower(tag.Title))})
+		for _, synonym := range synonymsByTag[tag.Id] {
+			e.terms = append(e.terms, term{text: []rune(strings.ToLower(synonym.Title)), synonym: synonym.Title})
+		}
+
+		for _, serviceId := range servicesByTag[tag.Id] {
+			e.locations = append(e.locations, services[serviceId])
+		}
+
+		entries = append(entries, e)
+	}
+
+	for _, category := range categories {
+		e := entry{kind: SUGGESTION_CATEGORY, id: category.Id, title: category.Title}
+		e.terms = append(e.terms, term{text: []rune(strings.ToLower(category.Title))})
+
+		for serviceId := range servicesByCategory[category.Id] {
+			e.locations = append(e.locations, services[serviceId])
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries
+}
@@ -0,0 +1,94 @@
+package autocomplete
+
+import (
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Plumbing has one service nearby and one far away, pest control has two
+// nearby services
+func newTaxonomy() *dbtest.Fake {
+	home := 1
+
+	fake := dbtest.NewFake()
+	fake.Tags = []models.TagModel{
+		{Model: models.Model{Id: 1}, Title: "plumbing", CategoryId: &home},
+		{Model: models.Model{Id: 2}, Title: "pest control", CategoryId: &home},
+		{Model: models.Model{Id: 3}, Title: "aircon repair"},
+	}
+	fake.TagSynonyms = []models.TagSynonymModel{{TagId: 1, Title: "plumber"}}
+	fake.TagCategories = []models.TagCategoryModel{{Model: models.Model{Id: 1}, Title: "Home"}}
+	fake.ServiceTagLocations = []models.ServiceTagLocationModel{
+		{TagId: 1, ServiceId: 1, Latitude: 7.07, Longitude: 125.61},
+		{TagId: 1, ServiceId: 2, Latitude: 8.07, Longitude: 125.61},
+		{TagId: 2, ServiceId: 3, Latitude: 7.071, Longitude: 125.61},
+		{TagId: 2, ServiceId: 1, Latitude: 7.07, Longitude: 125.61},
+		{TagId: 3, ServiceId: 4, Latitude: 7.07, Longitude: 125.611},
+	}
+
+	return fake
+}
+
+func newTestIndex(t *testing.T) *Index {
+	index := NewIndex(newTaxonomy())
+	if err := index.Refresh(); err != nil {
+		t.Fatalf("Failed to refresh index: %s", err.Error())
+	}
+
+	return index
+}
+
+func TestSuggestRanksByNearbyServices(t *testing.T) {
+	index := newTestIndex(t)
+	origin := &models.Location{Latitude: 7.07, Longitude: 125.61}
+
+	suggestions := index.Suggest("p", origin, 5000, 10)
+
+	assert.Len(t, suggestions, 2)
+	assert.Equal(t, "pest control", suggestions[0].Title)
+	assert.Equal(t, 2, suggestions[0].Nearby)
+	assert.Equal(t, "plumbing", suggestions[1].Title)
+	assert.Equal(t, 1, suggestions[1].Nearby)
+}
+
+func TestSuggestToleratesTypos(t *testing.T) {
+	index := newTestIndex(t)
+
+	suggestions := index.Suggest("plmuber", nil, 5000, 10)
+
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "plumbing", suggestions[0].Title)
+	assert.Equal(t, "plumber", suggestions[0].Matched)
+	assert.Equal(t, 2, suggestions[0].Nearby)
+}
+
+func TestSuggestMatchesWordsAndCategories(t *testing.T) {
+	index := newTestIndex(t)
+
+	suggestions := index.Suggest("repa", nil, 5000, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "aircon repair", suggestions[0].Title)
+
+	// Home covers both plumbing and pest control, the shared service counts once
+	suggestions = index.Suggest("hom", nil, 5000, 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, SUGGESTION_CATEGORY, suggestions[0].Type)
+	assert.Equal(t, 3, suggestions[0].Nearby)
+}
+
+func TestInvalidateTriggersRefresh(t *testing.T) {
+	index := NewIndex(newTaxonomy())
+	go index.Run(time.Hour)
+
+	assert.Eventually(t, func() bool {
+		return len(index.Suggest("plu", nil, 5000, 10)) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Pending requests are merged instead of blocking the caller
+	index.Invalidate()
+	index.Invalidate()
+}
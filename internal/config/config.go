@@ -10,6 +10,8 @@ import (
 
 type StorageType string
 type DatabaseType string
+type PaymentProviderType string
+type Environment string
 
 const (
 	STORAGE_DISK  StorageType = "disk"
@@ -17,9 +19,14 @@ const (
 
 	DATABASE_MYSQL DatabaseType = "mysql"
 	DATABASE_DUMMY DatabaseType = "dummy"
+
+	PAYMENT_PROVIDER_FAKE PaymentProviderType = "fake"
+
+	ENVIRONMENT_DEVELOPMENT Environment = "development"
 )
 
 type Config struct {
+	Environment              Environment
 	DB_User                  string
 	DB_Password              string
 	DB_Name                  string
@@ -38,7 +45,14 @@ type Config struct {
 	FrontIdLocation          string
 	BackIdLocation           string
 	FaceLocation             string
+	VendorComplaintLocation  string
+	ReceiptLocation          string
 	RouteEngineUrl           string
+	AutoMigrate              bool
+	PushUrl                  string
+	PushServerKey            string
+	PaymentProvider          PaymentProviderType
+	PaymentWebhookSecret     string
 }
 
 func LoadConfig() *Config {
@@ -51,6 +65,7 @@ func LoadConfig() *Config {
 	}
 
 	return &Config{
+		Environment:              Environment(os.Getenv("GO_ENV")),
 		DB_User:                  os.Getenv("DB_USER"),
 		DB_Password:              os.Getenv("DB_PASSWORD"),
 		DB_Name:                  os.Getenv("DB_NAME"),
@@ -69,6 +84,13 @@ func LoadConfig() *Config {
 		FrontIdLocation:          os.Getenv("VERIFICATION_FRONT_ID"),
 		BackIdLocation:           os.Getenv("VERIFICATION_BACK_ID"),
 		FaceLocation:             os.Getenv("VERIFICATION_FACE"),
+		VendorComplaintLocation:  os.Getenv("VENDOR_COMPLAINT_LOCATION"),
+		ReceiptLocation:          os.Getenv("RECEIPT_LOCATION"),
 		RouteEngineUrl:           os.Getenv("ROUTE_ENGINE_URL"),
+		AutoMigrate:              os.Getenv("AUTO_MIGRATE") == "true",
+		PushUrl:                  os.Getenv("PUSH_URL"),
+		PushServerKey:            os.Getenv("PUSH_SERVER_KEY"),
+		PaymentProvider:          PaymentProviderType(os.Getenv("PAYMENT_PROVIDER")),
+		PaymentWebhookSecret:     os.Getenv("PAYMENT_WEBHOOK_SECRET"),
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"nearbyassist/internal/request"
 	"nearbyassist/internal/response"
 	"nearbyassist/internal/types"
+	"time"
 )
 
 type Database interface {
@@ -34,12 +35,40 @@ type Database interface {
 	CountVendor(filter models.VendorStatus) (int, error)
 	FindVendorById(id int) (*models.VendorModel, error)
 	FindVendorByService(id int) (*response.ServiceVendorDetails, error)
-	RestrictVendor(id int) error
-	UnrestrictVendor(id int) error
+
+	// Vendor Restriction Queries
+	RestrictVendor(restriction *request.RestrictVendor) (int, error)
+	UnrestrictVendor(id, adminId int) error
+	IsVendorRestricted(vendorId int) (bool, error)
+	IsAnyVendorRestricted(vendorIds ...int) (bool, error)
+	FindActiveRestriction(vendorId int) (*models.VendorRestrictionModel, error)
+	FindRestrictionById(id int) (*models.VendorRestrictionModel, error)
+	FindVendorRestrictions(vendorId int) ([]models.VendorRestrictionModel, error)
+	LiftExpiredRestrictions() (int, error)
+	NewRestrictionAppeal(appeal *request.NewAppeal) (int, error)
+	HasPendingAppeal(restrictionId int) (bool, error)
+	FindAllRestrictionAppeals(status models.AppealStatus) ([]models.VendorRestrictionAppealModel, error)
+	FindRestrictionAppealsByVendor(vendorId int) ([]models.VendorRestrictionAppealModel, error)
+	FindRestrictionAppealById(id int) (*models.VendorRestrictionAppealModel, error)
+	ReviewRestrictionAppeal(review *request.ReviewAppeal) error
 
 	// Tag Queries
-	FindAllTags() ([]models.TagModel, error)
+	FindAllTags(includeRetired bool) ([]models.TagModel, error)
+	FindTagById(id int) (*models.TagModel, error)
 	FindAllTagByServiceId(serviceId int) ([]string, error)
+	FindServiceTagLocations() ([]models.ServiceTagLocationModel, error)
+	NewTag(tag *request.NewTag) (int, error)
+	UpdateTag(tag *request.UpdateTag) error
+	MergeTag(sourceId, targetId int) error
+	RetireTag(id int, replacementId *int) error
+	ResolveTags(titles []string) ([]int, []string, error)
+	FindAllTagCategories() ([]models.TagCategoryModel, error)
+	NewTagCategory(category *request.NewTagCategory) (int, error)
+	UpdateTagCategory(category *request.UpdateTagCategory) error
+	DeleteTagCategory(id int) error
+	FindAllTagSynonyms() ([]models.TagSynonymModel, error)
+	NewTagSynonym(synonym *request.NewTagSynonym) (int, error)
+	DeleteTagSynonym(id int) error
 
 	//  Service Queries
 	FindServiceById(id int) (*response.ServiceDetails, error)
@@ -47,61 +76,159 @@ type Database interface {
 	FindAllService() ([]*models.ServiceModel, error)
 	RegisterService(service *request.NewService) (int, error)
 	UpdateService(service *request.UpdateService) error
+	FindServiceAddOns(serviceId int) ([]models.ServiceAddOnModel, error)
 	DeleteService(id int) error
 	GeoSpatialSearch(params *types.SearchParams) ([]*models.ServiceSearchResult, error)
+	FindServicesInBounds(params *types.MapParams) ([]*models.ServiceSearchResult, bool, error)
 	FindServiceOwner(id int) (*response.ServiceOwner, error)
 	CountServices() (int, error)
 
 	// Complaint Queries
-	CountSystemComplaint() (int, error)
-	FindAllSystemComplaints() ([]*response.SystemComplaint, error)
+	CountSystemComplaintByStatus() ([]response.ComplaintStatusCount, error)
+	FindAllSystemComplaints(filter *request.SystemComplaintFilter) ([]*response.SystemComplaint, error)
+	FindSystemComplaintsByReporter(userId int) ([]*response.SystemComplaint, error)
 	FindSystemComplaintById(id int) (*models.SystemComplaintModel, error)
-	FileVendorComplaint(complaint *request.NewComplaint) (int, error)
+	TriageSystemComplaint(triage *request.TriageSystemComplaint) error
+	NewSystemComplaintReply(model *models.SystemComplaintReplyModel) (int, error)
+	FindSystemComplaintReplies(complaintId int) ([]models.SystemComplaintReplyModel, error)
 	FileSystemComplaint(complaint *request.SystemComplaint) (int, error)
 	NewSystemComplaintImage(model *models.SystemComplaintImageModel) (int, error)
 	FindSystemComplaintImagesByComplaintId(id int) ([]models.SystemComplaintImageModel, error)
 
+	// Vendor Complaint Queries
+	CountVendorComplaint(status models.VendorComplaintStatus) (int, error)
+	FileVendorComplaint(complaint *request.NewComplaint, evidence []string) (int, error)
+	FindAllVendorComplaints(status models.VendorComplaintStatus) ([]*response.VendorComplaint, error)
+	FindVendorComplaintsByComplainant(userId int) ([]*response.VendorComplaint, error)
+	FindVendorComplaintById(id int) (*models.VendorComplaintModel, error)
+	FindVendorComplaintEvidence(id int) ([]models.VendorComplaintEvidenceModel, error)
+	AssignVendorComplaint(id, staffId int) error
+	NewVendorComplaintComment(model *models.VendorComplaintCommentModel) (int, error)
+	FindVendorComplaintComments(id int) ([]models.VendorComplaintCommentModel, error)
+	ResolveVendorComplaint(resolution *request.ResolveComplaint) error
+
 	// Transaction Queries
 	CountTransaction(status models.TransactionStatus) (int, error)
 	CreateTransaction(transaction *request.NewTransaction) (int, error)
 	CompleteTransaction(id int) error
+	CancelTransaction(cancellation *models.TransactionCancellationModel) error
+	SettleCancelledPayment(transactionId int, penalty float64) error
+	FindTransactionCancellation(transactionId int) (*models.TransactionCancellationModel, error)
+	FindReliability(userId int, role models.CancellationRole) (*models.ReliabilityModel, error)
 	FindAllOngoingTransaction(id int, filter models.TransactionFilter) ([]models.DetailedTransactionModel, error)
     FindUserTransactions(id int) ([]*models.DetailedTransactionModel, error)
 	FindTransactionById(id int) (*models.TransactionModel, error)
 	GetTransactionHistory(id int, filter models.TransactionFilter) ([]models.DetailedTransactionModel, error)
 
+	// Payment Queries
+	ReservePayment(payment *models.PaymentModel) (bool, error)
+	ConfirmPayment(payment *models.PaymentModel) error
+	DeletePendingPayment(id int) error
+	FindPaymentByTransaction(transactionId int) (*models.PaymentModel, error)
+	FindPaymentEntries(paymentId int) ([]models.LedgerEntryModel, error)
+	ApplyPaymentWebhook(event *models.PaymentWebhookEventModel) (bool, error)
+	FindLedgerBalances() ([]models.LedgerBalanceModel, error)
+	FindPaymentLedgerTotals(provider string) ([]models.PaymentLedgerTotalModel, error)
+	FindUnbalancedJournals() ([]int, error)
+
+	// Receipt Queries
+	FindReceiptSource(transactionId int) (*models.ReceiptSourceModel, error)
+	ReserveReceipt(receipt *models.ReceiptModel) error
+	StoreReceipt(id int, path string) error
+	FindReceipts(transactionId int) ([]models.ReceiptModel, error)
+
 	// Application Queries
 	CountApplication(status models.ApplicationStatus) (int, error)
-	CreateApplication(application *request.NewApplication) (int, error)
+	CreateApplication(application *request.NewApplication) (int, bool, error)
 	FindApplicationById(id int) (*models.ApplicationModel, error)
 	FindAllApplication(status models.ApplicationStatus) ([]response.Application, error)
-	ApproveApplication(id int) error
-	RejectApplication(id int) error
+	FindApplicationsByApplicant(applicantId int) ([]models.ApplicationModel, error)
+	ApproveApplication(review *request.ApplicationReview) error
+	RejectApplication(review *request.ApplicationReview) error
 
 	// Review Queries
 	CreateReview(review *request.NewReview) (int, error)
 	FindReviewById(id int) (*models.ReviewModel, error)
 	FindAllReviewByService(id int) ([]models.ReviewModel, error)
+	RecalculateVendorRating(serviceId int) error
 
 	// Message Queries
 	GetMessages(senderId, receiverId int) ([]models.MessageModel, error)
 	GetAllUserConversations(userId int) ([]models.UserModel, error)
 	NewMessage(message models.MessageModel) (int, error)
 
+	// Quote Queries
+	NewQuote(quote *request.NewQuote) (*models.MessageModel, error)
+	AcceptQuote(response *request.QuoteResponse) (*models.MessageModel, int, error)
+	DeclineQuote(response *request.QuoteResponse) (*models.MessageModel, error)
+	FindQuoteById(id int) (*models.QuoteModel, error)
+
 	// Service Photo Queries
 	NewServicePhoto(data *models.ServicePhotoModel) (int, error)
 	FindAllPhotosByServiceId(serviceId int) ([]response.ServiceImages, error)
 
 	// Application Proof Queries
 	NewApplicationProof(data *models.ApplicationProofModel) (int, error)
+	FindApplicationProofs(applicationId int) ([]models.ApplicationProofModel, error)
+
+	// Outbox Queries
+	FindPendingOutboxEvents(limit, maxAttempts int, lease time.Duration) ([]models.OutboxEventModel, error)
+	FindOutboxDeliveries(outboxId int) ([]string, error)
+	MarkOutboxDelivered(outboxId int, subscriber string) error
+	MarkOutboxEventDispatched(id int) error
+	MarkOutboxEventFailed(id int, reason string, retryIn time.Duration) error
+
+	// Webhook Queries
+	NewWebhook(webhook *models.WebhookModel) (int, error)
+	FindAllWebhooks() ([]models.WebhookModel, error)
+	FindActiveWebhooks() ([]models.WebhookModel, error)
+	FindWebhookById(id int) (*models.WebhookModel, error)
+	UpdateWebhook(webhook *models.WebhookModel) error
+	DeleteWebhook(id int) error
+	NewWebhookDelivery(delivery *models.WebhookDeliveryModel) error
+	FindPendingWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryModel, error)
+	FindWebhookDeliveries(webhookId int) ([]models.WebhookDeliveryModel, error)
+	FindWebhookDeliveryById(id int) (*models.WebhookDeliveryModel, error)
+	FindWebhookDeliveryAttempts(deliveryId int) ([]models.WebhookDeliveryAttemptModel, error)
+	MarkWebhookDeliverySucceeded(attempt *models.WebhookDeliveryAttemptModel) error
+	MarkWebhookDeliveryFailed(attempt *models.WebhookDeliveryAttemptModel, retryIn time.Duration, maxAttempts int) error
+	RedeliverWebhookDelivery(id int) error
+
+	// Notification Queries
+	NewNotification(notification *models.NotificationModel) (int, error)
+	FindNotificationById(id int) (*models.NotificationModel, error)
+	FindNotificationsByUser(userId int, unreadOnly bool) ([]models.NotificationModel, error)
+	CountUnreadNotifications(userId int) (int, error)
+	MarkNotificationRead(id int) error
+	MarkAllNotificationsRead(userId int) (int, error)
+	SaveDeviceToken(token *request.DeviceToken) error
+	DeleteDeviceToken(userId int, token string) error
+	FindDeviceTokens(userId int) ([]models.DeviceTokenModel, error)
+
+	// Analytics Queries
+	RefreshAnalytics(from string) error
+	FindAnalyticsWatermark() (string, error)
+	FindAnalytics(filter *request.AnalyticsFilter) ([]models.AnalyticsPointModel, error)
+
+	// Vendor Stats Queries
+	RecordActivity(counters []models.ActivityCounterModel) error
+	FindVendorServiceStats(vendorId int, from, until string) ([]models.ServiceStatsModel, error)
+	FindVendorInquiryStats(vendorId int, from, until string) (*models.InquiryStatsModel, error)
+	FindVendorStatsTrend(vendorId int, from, to time.Time, bucket models.AnalyticsBucket) ([]models.VendorStatsPointModel, error)
+
+	// Export Queries
+	StreamExport(filter *request.ExportFilter, each func(record []string) error) error
+	NewExportAudit(audit *models.ExportAuditModel) (int, error)
+	FinishExportAudit(audit *models.ExportAuditModel) error
+	FindExportAudits() ([]models.ExportAuditModel, error)
 
 	// Verification Queries
-	FindAllIdentityVerification() ([]response.AllVerification, error)
-	NewIdentityVerification(model *models.IdentityVerificationModel) (int, error)
+	FindAllIdentityVerification(status models.VerificationStatus) ([]response.AllVerification, error)
+	NewIdentityVerification(model *models.IdentityVerificationModel, frontId *models.FrontIdModel, backId *models.BackIdModel, face *models.FaceModel) (int, error)
 	FindIdentityVerificationById(id int) (*models.IdentityVerificationModel, error)
-	NewFrontId(model *models.FrontIdModel) (int, error)
-	NewBackId(model *models.BackIdModel) (int, error)
-	NewFace(model *models.FaceModel) (int, error)
+	FindLatestIdentityVerificationByUser(userId int) (*response.VerificationStatus, error)
+	DecideIdentityVerification(decision *request.VerificationDecision) error
+	IsUserVerified(userId int) (bool, error)
 }
 
 func NewDatabase(conf *config.Config) Database {
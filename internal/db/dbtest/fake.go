@@ -0,0 +1,255 @@
+package dbtest
+
+import (
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory database for unit tests. Reads return what the test
+// put in the fields and writes are recorded in them, everything else falls
+// through to the dummy database
+type Fake struct {
+	db.DummyDatabase
+	mu sync.Mutex
+
+	// Returned by the writes below when set
+	Err error
+
+	OutboxEvents     []models.OutboxEventModel
+	OutboxDeliveries map[int][]string
+	DispatchedEvents []int
+	FailedEvents     map[int]time.Duration
+
+	WebhookDeliveries []models.WebhookDeliveryModel
+	SucceededAttempts []*models.WebhookDeliveryAttemptModel
+	FailedAttempts    []*models.WebhookDeliveryAttemptModel
+
+	DeviceTokens  []models.DeviceTokenModel
+	Notifications []*models.NotificationModel
+	LedgerTotals  []models.PaymentLedgerTotalModel
+	Activity      []models.ActivityCounterModel
+
+	Tags                []models.TagModel
+	TagSynonyms         []models.TagSynonymModel
+	TagCategories       []models.TagCategoryModel
+	ServiceTagLocations []models.ServiceTagLocationModel
+
+	ReceiptSource *models.ReceiptSourceModel
+	Quote         *models.QuoteModel
+	InvoiceNumber int
+	// Returned by StoreReceipt when set, after the receipt was reserved
+	StoreErr      error
+	StoredReceipt map[int]string
+
+	// Every export streams these rows, whatever its list
+	ExportRows   [][]string
+	ExportAudits []*models.ExportAuditModel
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		OutboxDeliveries: make(map[int][]string),
+		FailedEvents:     make(map[int]time.Duration),
+		StoredReceipt:    make(map[int]string),
+	}
+}
+
+func (f *Fake) FindPendingOutboxEvents(limit, maxAttempts int, lease time.Duration) ([]models.OutboxEventModel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.OutboxEvents, nil
+}
+
+func (f *Fake) FindOutboxDeliveries(outboxId int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.OutboxDeliveries[outboxId], nil
+}
+
+func (f *Fake) MarkOutboxDelivered(outboxId int, subscriber string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.OutboxDeliveries[outboxId] = append(f.OutboxDeliveries[outboxId], subscriber)
+	return nil
+}
+
+func (f *Fake) MarkOutboxEventDispatched(id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.DispatchedEvents = append(f.DispatchedEvents, id)
+	return nil
+}
+
+func (f *Fake) MarkOutboxEventFailed(id int, reason string, retryIn time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.FailedEvents[id] = retryIn
+	return nil
+}
+
+func (f *Fake) FindPendingWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryModel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.WebhookDeliveries, nil
+}
+
+func (f *Fake) MarkWebhookDeliverySucceeded(attempt *models.WebhookDeliveryAttemptModel) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.SucceededAttempts = append(f.SucceededAttempts, attempt)
+	return nil
+}
+
+func (f *Fake) MarkWebhookDeliveryFailed(attempt *models.WebhookDeliveryAttemptModel, retryIn time.Duration, maxAttempts int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.FailedAttempts = append(f.FailedAttempts, attempt)
+	return nil
+}
+
+func (f *Fake) FindDeviceTokens(userId int) ([]models.DeviceTokenModel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.DeviceTokens, nil
+}
+
+func (f *Fake) NewNotification(notification *models.NotificationModel) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return 0, f.Err
+	}
+
+	f.Notifications = append(f.Notifications, notification)
+	return len(f.Notifications), nil
+}
+
+func (f *Fake) FindPaymentLedgerTotals(provider string) ([]models.PaymentLedgerTotalModel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.LedgerTotals, nil
+}
+
+func (f *Fake) RecordActivity(counters []models.ActivityCounterModel) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.Activity = append(f.Activity, counters...)
+	return nil
+}
+
+func (f *Fake) FindAllTags(includeRetired bool) ([]models.TagModel, error) {
+	return f.Tags, nil
+}
+
+func (f *Fake) FindAllTagSynonyms() ([]models.TagSynonymModel, error) {
+	return f.TagSynonyms, nil
+}
+
+func (f *Fake) FindAllTagCategories() ([]models.TagCategoryModel, error) {
+	return f.TagCategories, nil
+}
+
+func (f *Fake) FindServiceTagLocations() ([]models.ServiceTagLocationModel, error) {
+	return f.ServiceTagLocations, nil
+}
+
+func (f *Fake) FindReceiptSource(transactionId int) (*models.ReceiptSourceModel, error) {
+	return f.ReceiptSource, nil
+}
+
+func (f *Fake) FindQuoteById(id int) (*models.QuoteModel, error) {
+	return f.Quote, nil
+}
+
+// Numbers the receipt with InvoiceNumber as its first revision
+func (f *Fake) ReserveReceipt(receipt *models.ReceiptModel) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	receipt.Id = len(f.StoredReceipt) + 1
+	receipt.InvoiceNumber = f.InvoiceNumber
+	receipt.Revision = 1
+
+	return nil
+}
+
+func (f *Fake) StoreReceipt(id int, path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.StoreErr != nil {
+		return f.StoreErr
+	}
+
+	f.StoredReceipt[id] = path
+	return nil
+}
+
+func (f *Fake) StreamExport(filter *request.ExportFilter, each func(record []string) error) error {
+	for _, row := range f.ExportRows {
+		if err := each(append([]string(nil), row...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Fake) NewExportAudit(audit *models.ExportAuditModel) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return 0, f.Err
+	}
+
+	f.ExportAudits = append(f.ExportAudits, audit)
+	return len(f.ExportAudits), nil
+}
+
+func (f *Fake) FinishExportAudit(audit *models.ExportAuditModel) error {
+	return f.Err
+}
@@ -5,6 +5,7 @@ import (
 	"nearbyassist/internal/request"
 	"nearbyassist/internal/response"
 	"nearbyassist/internal/types"
+	"time"
 )
 
 type DummyDatabase struct{}
@@ -81,15 +82,67 @@ func (d *DummyDatabase) FindVendorByService(id int) (*response.ServiceVendorDeta
 	return nil, nil
 }
 
-func (d *DummyDatabase) RestrictVendor(id int) error {
+func (d *DummyDatabase) RestrictVendor(restriction *request.RestrictVendor) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) UnrestrictVendor(id, adminId int) error {
 	return nil
 }
 
-func (d *DummyDatabase) UnrestrictVendor(id int) error {
+func (d *DummyDatabase) IsVendorRestricted(vendorId int) (bool, error) {
+	return false, nil
+}
+
+func (d *DummyDatabase) IsAnyVendorRestricted(vendorIds ...int) (bool, error) {
+	return false, nil
+}
+
+func (d *DummyDatabase) FindActiveRestriction(vendorId int) (*models.VendorRestrictionModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindRestrictionById(id int) (*models.VendorRestrictionModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindVendorRestrictions(vendorId int) ([]models.VendorRestrictionModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) LiftExpiredRestrictions() (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) NewRestrictionAppeal(appeal *request.NewAppeal) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) HasPendingAppeal(restrictionId int) (bool, error) {
+	return false, nil
+}
+
+func (d *DummyDatabase) FindAllRestrictionAppeals(status models.AppealStatus) ([]models.VendorRestrictionAppealModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindRestrictionAppealsByVendor(vendorId int) ([]models.VendorRestrictionAppealModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindRestrictionAppealById(id int) (*models.VendorRestrictionAppealModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) ReviewRestrictionAppeal(review *request.ReviewAppeal) error {
 	return nil
 }
 
-func (d *DummyDatabase) FindAllTags() ([]models.TagModel, error) {
+func (d *DummyDatabase) FindAllTags(includeRetired bool) ([]models.TagModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindTagById(id int) (*models.TagModel, error) {
 	return nil, nil
 }
 
@@ -97,6 +150,58 @@ func (d *DummyDatabase) FindAllTagByServiceId(serviceId int) ([]string, error) {
 	return nil, nil
 }
 
+func (d *DummyDatabase) FindServiceTagLocations() ([]models.ServiceTagLocationModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) NewTag(tag *request.NewTag) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) UpdateTag(tag *request.UpdateTag) error {
+	return nil
+}
+
+func (d *DummyDatabase) MergeTag(sourceId, targetId int) error {
+	return nil
+}
+
+func (d *DummyDatabase) RetireTag(id int, replacementId *int) error {
+	return nil
+}
+
+func (d *DummyDatabase) ResolveTags(titles []string) ([]int, []string, error) {
+	return nil, nil, nil
+}
+
+func (d *DummyDatabase) FindAllTagCategories() ([]models.TagCategoryModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) NewTagCategory(category *request.NewTagCategory) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) UpdateTagCategory(category *request.UpdateTagCategory) error {
+	return nil
+}
+
+func (d *DummyDatabase) DeleteTagCategory(id int) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindAllTagSynonyms() ([]models.TagSynonymModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) NewTagSynonym(synonym *request.NewTagSynonym) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) DeleteTagSynonym(id int) error {
+	return nil
+}
+
 func (d *DummyDatabase) CountServices() (int, error) {
 	return 0, nil
 }
@@ -121,6 +226,10 @@ func (d *DummyDatabase) UpdateService(service *request.UpdateService) error {
 	return nil
 }
 
+func (d *DummyDatabase) FindServiceAddOns(serviceId int) ([]models.ServiceAddOnModel, error) {
+	return nil, nil
+}
+
 func (d *DummyDatabase) DeleteService(id int) error {
 	return nil
 }
@@ -133,11 +242,15 @@ func (d *DummyDatabase) FindServiceOwner(id int) (*response.ServiceOwner, error)
 	return nil, nil
 }
 
-func (d *DummyDatabase) CountSystemComplaint() (int, error) {
-	return 0, nil
+func (d *DummyDatabase) CountSystemComplaintByStatus() ([]response.ComplaintStatusCount, error) {
+	return nil, nil
+}
+
+func (m *DummyDatabase) FindAllSystemComplaints(filter *request.SystemComplaintFilter) ([]*response.SystemComplaint, error) {
+	return nil, nil
 }
 
-func (m *DummyDatabase) FindAllSystemComplaints() ([]*response.SystemComplaint, error) {
+func (d *DummyDatabase) FindSystemComplaintsByReporter(userId int) ([]*response.SystemComplaint, error) {
 	return nil, nil
 }
 
@@ -145,10 +258,18 @@ func (m *DummyDatabase) FindSystemComplaintById(id int) (*models.SystemComplaint
 	return nil, nil
 }
 
-func (d *DummyDatabase) FileVendorComplaint(complaint *request.NewComplaint) (int, error) {
+func (d *DummyDatabase) TriageSystemComplaint(triage *request.TriageSystemComplaint) error {
+	return nil
+}
+
+func (d *DummyDatabase) NewSystemComplaintReply(model *models.SystemComplaintReplyModel) (int, error) {
 	return 0, nil
 }
 
+func (d *DummyDatabase) FindSystemComplaintReplies(complaintId int) ([]models.SystemComplaintReplyModel, error) {
+	return nil, nil
+}
+
 func (d *DummyDatabase) FileSystemComplaint(complaint *request.SystemComplaint) (int, error) {
 	return 0, nil
 }
@@ -161,6 +282,46 @@ func (d *DummyDatabase) FindSystemComplaintImagesByComplaintId(id int) ([]models
 	return nil, nil
 }
 
+func (d *DummyDatabase) CountVendorComplaint(status models.VendorComplaintStatus) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) FileVendorComplaint(complaint *request.NewComplaint, evidence []string) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) FindAllVendorComplaints(status models.VendorComplaintStatus) ([]*response.VendorComplaint, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindVendorComplaintsByComplainant(userId int) ([]*response.VendorComplaint, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindVendorComplaintById(id int) (*models.VendorComplaintModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindVendorComplaintEvidence(id int) ([]models.VendorComplaintEvidenceModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) AssignVendorComplaint(id, staffId int) error {
+	return nil
+}
+
+func (d *DummyDatabase) NewVendorComplaintComment(model *models.VendorComplaintCommentModel) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) FindVendorComplaintComments(id int) ([]models.VendorComplaintCommentModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) ResolveVendorComplaint(resolution *request.ResolveComplaint) error {
+	return nil
+}
+
 func (d *DummyDatabase) CountTransaction(status models.TransactionStatus) (int, error) {
 	return 0, nil
 }
@@ -173,6 +334,74 @@ func (d *DummyDatabase) CompleteTransaction(id int) error {
 	return nil
 }
 
+func (d *DummyDatabase) CancelTransaction(cancellation *models.TransactionCancellationModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) SettleCancelledPayment(transactionId int, penalty float64) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindTransactionCancellation(transactionId int) (*models.TransactionCancellationModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindReliability(userId int, role models.CancellationRole) (*models.ReliabilityModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) ReservePayment(payment *models.PaymentModel) (bool, error) {
+	return true, nil
+}
+
+func (d *DummyDatabase) ConfirmPayment(payment *models.PaymentModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) DeletePendingPayment(id int) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindPaymentByTransaction(transactionId int) (*models.PaymentModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindPaymentEntries(paymentId int) ([]models.LedgerEntryModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) ApplyPaymentWebhook(event *models.PaymentWebhookEventModel) (bool, error) {
+	return false, nil
+}
+
+func (d *DummyDatabase) FindLedgerBalances() ([]models.LedgerBalanceModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindPaymentLedgerTotals(provider string) ([]models.PaymentLedgerTotalModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindUnbalancedJournals() ([]int, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindReceiptSource(transactionId int) (*models.ReceiptSourceModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) ReserveReceipt(receipt *models.ReceiptModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) StoreReceipt(id int, path string) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindReceipts(transactionId int) ([]models.ReceiptModel, error) {
+	return nil, nil
+}
+
 func (d *DummyDatabase) FindAllOngoingTransaction(id int, filter models.TransactionFilter) ([]models.DetailedTransactionModel, error) {
 	return nil, nil
 }
@@ -193,8 +422,8 @@ func (d *DummyDatabase) CountApplication(status models.ApplicationStatus) (int,
 	return 0, nil
 }
 
-func (d *DummyDatabase) CreateApplication(application *request.NewApplication) (int, error) {
-	return 0, nil
+func (d *DummyDatabase) CreateApplication(application *request.NewApplication) (int, bool, error) {
+	return 0, true, nil
 }
 
 func (d *DummyDatabase) FindApplicationById(id int) (*models.ApplicationModel, error) {
@@ -205,11 +434,15 @@ func (d *DummyDatabase) FindAllApplication(status models.ApplicationStatus) ([]r
 	return nil, nil
 }
 
-func (d *DummyDatabase) ApproveApplication(id int) error {
+func (d *DummyDatabase) FindApplicationsByApplicant(applicantId int) ([]models.ApplicationModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) ApproveApplication(review *request.ApplicationReview) error {
 	return nil
 }
 
-func (d *DummyDatabase) RejectApplication(id int) error {
+func (d *DummyDatabase) RejectApplication(review *request.ApplicationReview) error {
 	return nil
 }
 
@@ -237,6 +470,22 @@ func (d *DummyDatabase) NewMessage(message models.MessageModel) (int, error) {
 	return 0, nil
 }
 
+func (d *DummyDatabase) NewQuote(quote *request.NewQuote) (*models.MessageModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) AcceptQuote(response *request.QuoteResponse) (*models.MessageModel, int, error) {
+	return nil, 0, nil
+}
+
+func (d *DummyDatabase) DeclineQuote(response *request.QuoteResponse) (*models.MessageModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindQuoteById(id int) (*models.QuoteModel, error) {
+	return nil, nil
+}
+
 func (d *DummyDatabase) NewServicePhoto(data *models.ServicePhotoModel) (int, error) {
 	return 0, nil
 }
@@ -249,11 +498,15 @@ func (d *DummyDatabase) NewApplicationProof(data *models.ApplicationProofModel)
 	return 0, nil
 }
 
-func (m *DummyDatabase) FindAllIdentityVerification() ([]response.AllVerification, error) {
+func (d *DummyDatabase) FindApplicationProofs(applicationId int) ([]models.ApplicationProofModel, error) {
 	return nil, nil
 }
 
-func (m *DummyDatabase) NewIdentityVerification(model *models.IdentityVerificationModel) (int, error) {
+func (m *DummyDatabase) FindAllIdentityVerification(status models.VerificationStatus) ([]response.AllVerification, error) {
+	return nil, nil
+}
+
+func (m *DummyDatabase) NewIdentityVerification(model *models.IdentityVerificationModel, frontId *models.FrontIdModel, backId *models.BackIdModel, face *models.FaceModel) (int, error) {
 	return 0, nil
 }
 
@@ -261,14 +514,178 @@ func (m *DummyDatabase) FindIdentityVerificationById(id int) (*models.IdentityVe
 	return nil, nil
 }
 
-func (m *DummyDatabase) NewFrontId(model *models.FrontIdModel) (int, error) {
+func (m *DummyDatabase) FindLatestIdentityVerificationByUser(userId int) (*response.VerificationStatus, error) {
+	return nil, nil
+}
+
+func (m *DummyDatabase) DecideIdentityVerification(decision *request.VerificationDecision) error {
+	return nil
+}
+
+func (m *DummyDatabase) IsUserVerified(userId int) (bool, error) {
+	return false, nil
+}
+
+func (d *DummyDatabase) NewNotification(notification *models.NotificationModel) (int, error) {
 	return 0, nil
 }
 
-func (m *DummyDatabase) NewBackId(model *models.BackIdModel) (int, error) {
+func (d *DummyDatabase) FindNotificationById(id int) (*models.NotificationModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindNotificationsByUser(userId int, unreadOnly bool) ([]models.NotificationModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) CountUnreadNotifications(userId int) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) MarkNotificationRead(id int) error {
+	return nil
+}
+
+func (d *DummyDatabase) MarkAllNotificationsRead(userId int) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyDatabase) SaveDeviceToken(token *request.DeviceToken) error {
+	return nil
+}
+
+func (d *DummyDatabase) DeleteDeviceToken(userId int, token string) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindDeviceTokens(userId int) ([]models.DeviceTokenModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindPendingOutboxEvents(limit, maxAttempts int, lease time.Duration) ([]models.OutboxEventModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindOutboxDeliveries(outboxId int) ([]string, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) MarkOutboxDelivered(outboxId int, subscriber string) error {
+	return nil
+}
+
+func (d *DummyDatabase) MarkOutboxEventDispatched(id int) error {
+	return nil
+}
+
+func (d *DummyDatabase) MarkOutboxEventFailed(id int, reason string, retryIn time.Duration) error {
+	return nil
+}
+
+func (d *DummyDatabase) RecalculateVendorRating(serviceId int) error {
+	return nil
+}
+
+func (d *DummyDatabase) NewWebhook(webhook *models.WebhookModel) (int, error) {
 	return 0, nil
 }
 
-func (m *DummyDatabase) NewFace(model *models.FaceModel) (int, error) {
+func (d *DummyDatabase) FindAllWebhooks() ([]models.WebhookModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindActiveWebhooks() ([]models.WebhookModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindWebhookById(id int) (*models.WebhookModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) UpdateWebhook(webhook *models.WebhookModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) DeleteWebhook(id int) error {
+	return nil
+}
+
+func (d *DummyDatabase) NewWebhookDelivery(delivery *models.WebhookDeliveryModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindPendingWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindWebhookDeliveries(webhookId int) ([]models.WebhookDeliveryModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindWebhookDeliveryById(id int) (*models.WebhookDeliveryModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) FindWebhookDeliveryAttempts(deliveryId int) ([]models.WebhookDeliveryAttemptModel, error) {
+	return nil, nil
+}
+
+func (d *DummyDatabase) MarkWebhookDeliverySucceeded(attempt *models.WebhookDeliveryAttemptModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) MarkWebhookDeliveryFailed(attempt *models.WebhookDeliveryAttemptModel, retryIn time.Duration, maxAttempts int) error {
+	return nil
+}
+
+func (d *DummyDatabase) RedeliverWebhookDelivery(id int) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindServicesInBounds(params *types.MapParams) ([]*models.ServiceSearchResult, bool, error) {
+	return nil, false, nil
+}
+
+func (d *DummyDatabase) RefreshAnalytics(from string) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindAnalyticsWatermark() (string, error) {
+	return "", nil
+}
+
+func (d *DummyDatabase) FindAnalytics(filter *request.AnalyticsFilter) ([]models.AnalyticsPointModel, error) {
+	return []models.AnalyticsPointModel{}, nil
+}
+
+func (d *DummyDatabase) RecordActivity(counters []models.ActivityCounterModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindVendorServiceStats(vendorId int, from, until string) ([]models.ServiceStatsModel, error) {
+	return []models.ServiceStatsModel{}, nil
+}
+
+func (d *DummyDatabase) FindVendorInquiryStats(vendorId int, from, until string) (*models.InquiryStatsModel, error) {
+	return &models.InquiryStatsModel{}, nil
+}
+
+func (d *DummyDatabase) FindVendorStatsTrend(vendorId int, from, to time.Time, bucket models.AnalyticsBucket) ([]models.VendorStatsPointModel, error) {
+	return []models.VendorStatsPointModel{}, nil
+}
+
+func (d *DummyDatabase) StreamExport(filter *request.ExportFilter, each func(record []string) error) error {
+	return nil
+}
+
+func (d *DummyDatabase) NewExportAudit(audit *models.ExportAuditModel) (int, error) {
 	return 0, nil
 }
+
+func (d *DummyDatabase) FinishExportAudit(audit *models.ExportAuditModel) error {
+	return nil
+}
+
+func (d *DummyDatabase) FindExportAudits() ([]models.ExportAuditModel, error) {
+	return []models.ExportAuditModel{}, nil
+}
@@ -1,21 +1,10 @@
-DROP TABLE IF EXISTS IdentityVerification;
-DROP TABLE IF EXISTS FrontId;
-DROP TABLE IF EXISTS BackId;
-DROP TABLE IF EXISTS Face;
-DROP TABLE IF EXISTS Transaction;
 DROP TABLE IF EXISTS ServicePhoto;
-DROP TABLE IF EXISTS Review;
+DROP TABLE IF EXISTS Message;
 DROP TABLE IF EXISTS ServiceTag;
 DROP TABLE IF EXISTS Service;
 DROP TABLE IF EXISTS Tag;
-DROP TABLE IF EXISTS Message;
-DROP TABLE IF EXISTS VendorComplaint;
-DROP TABLE IF EXISTS SystemComplaintImage;
-DROP TABLE IF EXISTS SystemComplaint;
-DROP TABLE IF EXISTS Vendor;
 DROP TABLE IF EXISTS Blacklist;
 DROP TABLE IF EXISTS Session;
-DROP TABLE IF EXISTS ApplicationProof;
-DROP TABLE IF EXISTS Application;
-DROP TABLE IF EXISTS User;
 DROP TABLE IF EXISTS Admin;
+DROP TABLE IF EXISTS Vendor;
+DROP TABLE IF EXISTS User;
@@ -115,156 +115,3 @@ CREATE TABLE IF NOT EXISTS ServicePhoto (
     FOREIGN KEY(vendorId) REFERENCES User(id) ON DELETE CASCADE,
     INDEX(id, serviceId, vendorId)
 );
-
-CREATE TABLE IF NOT EXISTS FrontId (
-    id Int NOT NULL AUTO_INCREMENT,
-    url Varchar(255) NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    INDEX(id)
-);
-
-CREATE TABLE IF NOT EXISTS BackId (
-    id Int NOT NULL AUTO_INCREMENT,
-    url Varchar(255) NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    INDEX(id)
-);
-
-CREATE TABLE IF NOT EXISTS Face (
-    id Int NOT NULL AUTO_INCREMENT,
-    url Varchar(255) NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    INDEX(id)
-);
-
-
-CREATE TABLE IF NOT EXISTS IdentityVerification (
-    id Int NOT NULL AUTO_INCREMENT,
-    user Int NOT NULL,
-    name Varchar(255) NOT NULL,
-    address Varchar(255) NOT NULL,
-    idType Varchar(255) NOT NULL,
-    idNumber Varchar(255) NOT NULL,
-    frontId Int NOT NULL,
-    backId Int NOT NULL,
-    face Int NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id, user, idType),
-    FOREIGN KEY(user) REFERENCES User(id),
-    FOREIGN KEY(frontId) REFERENCES FrontId(id),
-    FOREIGN KEY(backId) REFERENCES BackId(id),
-    FOREIGN KEY(face) REFERENCES Face(id),
-    INDEX(id)
-);
-
-CREATE TABLE IF NOT EXISTS VendorComplaint (
-    id Int NOT NULL AUTO_INCREMENT,
-    vendorId Int NOT NULL,
-    title Varchar(255) NOT NULL,
-    content Text NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    FOREIGN KEY(vendorId) REFERENCES Vendor(id) ON DELETE CASCADE,
-    INDEX(id, vendorId)
-);
-
-create table if not exists SystemComplaint (
-    id Int NOT NULL AUTO_INCREMENT,
-    title Varchar(255) NOT NULL,
-    detail Text NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    INDEX(id, title)
-);
-
-create table if not exists SystemComplaintImage (
-    id Int NOT NULL AUTO_INCREMENT,
-    complaintId Int NOT NULL,
-    url Varchar(255) NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    FOREIGN KEY(complaintId) REFERENCES SystemComplaint(id),
-    INDEX(id, complaintId)
-);
-
-CREATE TABLE IF NOT EXISTS Review (
-    id Int NOT NULL AUTO_INCREMENT,
-    serviceId Int NOT NULL,
-    rating Int NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    FOREIGN KEY(serviceId) REFERENCES Service(id) ON DELETE CASCADE,
-    INDEX(id, serviceId)
-);
-
-CREATE TABLE IF NOT EXISTS Transaction (
-    id INT NOT NULL AUTO_INCREMENT,
-    vendorId INT NOT NULL,
-    clientId INT NOT NULL,
-    serviceId INT NOT NULL,
-    status Enum('ongoing', 'done', 'cancelled') NOT NULL DEFAULT 'ongoing',
-    start TIMESTAMP NOT NULL,
-    end TIMESTAMP NOT NULL,
-    isReviewed TINYINT(1) NOT NULL DEFAULT 0 COMMENT '0: not reviewed, 1: reviewed',
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    FOREIGN KEY(vendorId) REFERENCES Vendor(id) ON DELETE CASCADE,
-    FOREIGN KEY(serviceId) REFERENCES Service(id) ON DELETE CASCADE,
-    FOREIGN KEY(clientId) REFERENCES User(id)
-);
-
-CREATE TABLE IF NOT EXISTS Application (
-    id INT NOT NULL AUTO_INCREMENT,
-    applicantId INT NOT NULL UNIQUE,
-    job Varchar(255) NOT NULL,
-    latitude Double NOT NULL,
-    longitude Double NOT NULL,
-    status Enum('pending', 'rejected', 'approved') NOT NULL DEFAULT 'pending',
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    FOREIGN KEY(applicantId) REFERENCES User(id)
-);
-
-CREATE TABLE IF NOT EXISTS ApplicationProof (
-    id INT NOT NULL AUTO_INCREMENT,
-    applicationId INT NOT NULL,
-    applicantId INT NOT NULL,
-    url Varchar(255) NOT NULL,
-    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    PRIMARY KEY(id),
-    FOREIGN KEY(applicationId) REFERENCES Application(id),
-    FOREIGN KEY(applicantId) REFERENCES Application(applicantId)
-);
-
-DELIMITER //
-
-CREATE TRIGGER update_vendor_rating
-AFTER INSERT ON Review
-FOR EACH ROW
-BEGIN
-    DECLARE avg_rating DECIMAL(5,1);
-    
-    SELECT ROUND(AVG(rating), 1) INTO avg_rating
-    FROM Review
-    WHERE serviceId = NEW.serviceId;
-
-    UPDATE Vendor
-    SET rating = avg_rating
-    WHERE vendorId = (SELECT vendorId FROM Service WHERE id = NEW.serviceId);
-END //
-
-DELIMITER ;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS IdentityVerification;
+DROP TABLE IF EXISTS Face;
+DROP TABLE IF EXISTS BackId;
+DROP TABLE IF EXISTS FrontId;
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS FrontId (
+    id Int NOT NULL AUTO_INCREMENT,
+    url Varchar(255) NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    INDEX(id)
+);
+
+CREATE TABLE IF NOT EXISTS BackId (
+    id Int NOT NULL AUTO_INCREMENT,
+    url Varchar(255) NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    INDEX(id)
+);
+
+CREATE TABLE IF NOT EXISTS Face (
+    id Int NOT NULL AUTO_INCREMENT,
+    url Varchar(255) NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    INDEX(id)
+);
+
+CREATE TABLE IF NOT EXISTS IdentityVerification (
+    id Int NOT NULL AUTO_INCREMENT,
+    user Int NOT NULL,
+    name Varchar(255) NOT NULL,
+    address Varchar(255) NOT NULL,
+    idType Varchar(255) NOT NULL,
+    idNumber Varchar(255) NOT NULL,
+    frontId Int NOT NULL,
+    backId Int NOT NULL,
+    face Int NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id, user, idType),
+    FOREIGN KEY(user) REFERENCES User(id),
+    FOREIGN KEY(frontId) REFERENCES FrontId(id),
+    FOREIGN KEY(backId) REFERENCES BackId(id),
+    FOREIGN KEY(face) REFERENCES Face(id),
+    INDEX(id)
+);
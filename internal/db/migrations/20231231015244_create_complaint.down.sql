@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS SystemComplaintImage;
+DROP TABLE IF EXISTS SystemComplaint;
+DROP TABLE IF EXISTS VendorComplaint;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS Review (
+    id Int NOT NULL AUTO_INCREMENT,
+    serviceId Int NOT NULL,
+    rating Int NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(serviceId) REFERENCES Service(id) ON DELETE CASCADE,
+    INDEX(id, serviceId)
+);
+
+CREATE TABLE IF NOT EXISTS Transaction (
+    id INT NOT NULL AUTO_INCREMENT,
+    vendorId INT NOT NULL,
+    clientId INT NOT NULL,
+    serviceId INT NOT NULL,
+    status Enum('ongoing', 'done', 'cancelled') NOT NULL DEFAULT 'ongoing',
+    start TIMESTAMP NOT NULL,
+    end TIMESTAMP NOT NULL,
+    isReviewed TINYINT(1) NOT NULL DEFAULT 0 COMMENT '0: not reviewed, 1: reviewed',
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(vendorId) REFERENCES Vendor(id) ON DELETE CASCADE,
+    FOREIGN KEY(serviceId) REFERENCES Service(id) ON DELETE CASCADE,
+    FOREIGN KEY(clientId) REFERENCES User(id)
+);
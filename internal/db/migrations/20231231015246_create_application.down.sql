@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ApplicationProof;
+DROP TABLE IF EXISTS Application;
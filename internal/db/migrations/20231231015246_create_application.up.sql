@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS Application (
+    id INT NOT NULL AUTO_INCREMENT,
+    applicantId INT NOT NULL UNIQUE,
+    job Varchar(255) NOT NULL,
+    latitude Double NOT NULL,
+    longitude Double NOT NULL,
+    status Enum('pending', 'rejected', 'approved') NOT NULL DEFAULT 'pending',
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(applicantId) REFERENCES User(id)
+);
+
+CREATE TABLE IF NOT EXISTS ApplicationProof (
+    id INT NOT NULL AUTO_INCREMENT,
+    applicationId INT NOT NULL,
+    applicantId INT NOT NULL,
+    url Varchar(255) NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(applicationId) REFERENCES Application(id),
+    FOREIGN KEY(applicantId) REFERENCES Application(applicantId)
+);
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS update_vendor_rating;
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS VendorComplaintComment;
+DROP TABLE IF EXISTS VendorComplaintEvidence;
+
+ALTER TABLE VendorComplaint
+    DROP FOREIGN KEY fk_vendor_complaint_complainant,
+    DROP FOREIGN KEY fk_vendor_complaint_vendor,
+    DROP FOREIGN KEY fk_vendor_complaint_transaction,
+    DROP FOREIGN KEY fk_vendor_complaint_assignee,
+    DROP INDEX idx_vendor_complaint_status,
+    DROP INDEX idx_vendor_complaint_complainant,
+    DROP COLUMN resolvedAt,
+    DROP COLUMN resolutionNote,
+    DROP COLUMN resolution,
+    DROP COLUMN assignedTo,
+    DROP COLUMN status,
+    DROP COLUMN transactionId,
+    DROP COLUMN complainantId;
+
+UPDATE VendorComplaint c JOIN Vendor v ON v.vendorId = c.vendorId SET c.vendorId = v.id;
+
+ALTER TABLE VendorComplaint
+    ADD CONSTRAINT VendorComplaint_ibfk_1 FOREIGN KEY(vendorId) REFERENCES Vendor(id) ON DELETE CASCADE;
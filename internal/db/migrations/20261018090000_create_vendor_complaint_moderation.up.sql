@@ -0,0 +1,45 @@
+-- Complaints used to point at the vendor row, they now point at the vendor's
+-- user like every other table
+ALTER TABLE VendorComplaint DROP FOREIGN KEY VendorComplaint_ibfk_1;
+
+UPDATE VendorComplaint c JOIN Vendor v ON v.id = c.vendorId SET c.vendorId = v.vendorId;
+
+-- Complaints filed before moderation have no complainant or transaction
+ALTER TABLE VendorComplaint
+    ADD COLUMN complainantId Int AFTER id,
+    ADD COLUMN transactionId Int AFTER vendorId,
+    ADD COLUMN status Enum('open', 'under_review', 'resolved', 'dismissed') NOT NULL DEFAULT 'open' AFTER content,
+    ADD COLUMN assignedTo Int AFTER status,
+    ADD COLUMN resolution Enum('none', 'warning', 'restriction') NOT NULL DEFAULT 'none' AFTER assignedTo,
+    ADD COLUMN resolutionNote Text AFTER resolution,
+    ADD COLUMN resolvedAt TIMESTAMP NULL AFTER resolutionNote,
+    ADD CONSTRAINT fk_vendor_complaint_complainant FOREIGN KEY(complainantId) REFERENCES User(id),
+    ADD CONSTRAINT fk_vendor_complaint_vendor FOREIGN KEY(vendorId) REFERENCES User(id),
+    ADD CONSTRAINT fk_vendor_complaint_transaction FOREIGN KEY(transactionId) REFERENCES Transaction(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_vendor_complaint_assignee FOREIGN KEY(assignedTo) REFERENCES Admin(id) ON DELETE SET NULL,
+    ADD INDEX idx_vendor_complaint_status (vendorId, status),
+    ADD INDEX idx_vendor_complaint_complainant (complainantId);
+
+CREATE TABLE IF NOT EXISTS VendorComplaintEvidence (
+    id Int NOT NULL AUTO_INCREMENT,
+    complaintId Int NOT NULL,
+    url Varchar(255) NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(complaintId) REFERENCES VendorComplaint(id) ON DELETE CASCADE,
+    INDEX(id, complaintId)
+);
+
+CREATE TABLE IF NOT EXISTS VendorComplaintComment (
+    id Int NOT NULL AUTO_INCREMENT,
+    complaintId Int NOT NULL,
+    adminId Int NOT NULL,
+    comment Text NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(complaintId) REFERENCES VendorComplaint(id) ON DELETE CASCADE,
+    FOREIGN KEY(adminId) REFERENCES Admin(id),
+    INDEX(id, complaintId)
+);
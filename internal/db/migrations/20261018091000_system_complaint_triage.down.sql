@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS SystemComplaintReply;
+
+ALTER TABLE SystemComplaint
+    DROP FOREIGN KEY fk_system_complaint_reporter,
+    DROP FOREIGN KEY fk_system_complaint_assignee,
+    DROP INDEX idx_system_complaint_status,
+    DROP COLUMN assignedTo,
+    DROP COLUMN priority,
+    DROP COLUMN status,
+    DROP COLUMN reporterId;
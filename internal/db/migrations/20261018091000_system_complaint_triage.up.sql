@@ -0,0 +1,23 @@
+ALTER TABLE SystemComplaint
+    ADD COLUMN reporterId Int AFTER id,
+    ADD COLUMN status Enum('open', 'in_progress', 'resolved', 'closed') NOT NULL DEFAULT 'open' AFTER detail,
+    ADD COLUMN priority Enum('low', 'medium', 'high', 'urgent') NOT NULL DEFAULT 'medium' AFTER status,
+    ADD COLUMN assignedTo Int AFTER priority,
+    ADD CONSTRAINT fk_system_complaint_reporter FOREIGN KEY(reporterId) REFERENCES User(id),
+    ADD CONSTRAINT fk_system_complaint_assignee FOREIGN KEY(assignedTo) REFERENCES Admin(id) ON DELETE SET NULL,
+    ADD INDEX idx_system_complaint_status (status, priority);
+
+CREATE TABLE IF NOT EXISTS SystemComplaintReply (
+    id Int NOT NULL AUTO_INCREMENT,
+    complaintId Int NOT NULL,
+    parentId Int,
+    authorId Int NOT NULL,
+    authorRole Enum('reporter', 'staff') NOT NULL,
+    content Text NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(complaintId) REFERENCES SystemComplaint(id) ON DELETE CASCADE,
+    FOREIGN KEY(parentId) REFERENCES SystemComplaintReply(id) ON DELETE CASCADE,
+    INDEX(id, complaintId)
+);
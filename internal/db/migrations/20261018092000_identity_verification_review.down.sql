@@ -0,0 +1,7 @@
+ALTER TABLE IdentityVerification
+    DROP FOREIGN KEY fk_identity_verification_reviewer,
+    DROP INDEX idx_identity_verification_user_status,
+    DROP COLUMN reviewedAt,
+    DROP COLUMN reviewedBy,
+    DROP COLUMN reason,
+    DROP COLUMN status;
@@ -0,0 +1,7 @@
+ALTER TABLE IdentityVerification
+    ADD COLUMN status ENUM('pending', 'approved', 'rejected', 'resubmit') NOT NULL DEFAULT 'pending' AFTER face,
+    ADD COLUMN reason Text AFTER status,
+    ADD COLUMN reviewedBy Int AFTER reason,
+    ADD COLUMN reviewedAt TIMESTAMP NULL AFTER reviewedBy,
+    ADD CONSTRAINT fk_identity_verification_reviewer FOREIGN KEY(reviewedBy) REFERENCES Admin(id),
+    ADD INDEX idx_identity_verification_user_status (user, status);
@@ -0,0 +1,24 @@
+-- Only one application per applicant fits the unique index, the latest one
+-- is kept and the review history before it is lost
+DELETE p FROM ApplicationProof p
+    JOIN Application a ON a.id = p.applicationId
+    JOIN Application newer ON newer.applicantId = a.applicantId AND newer.id > a.id;
+
+DELETE a FROM Application a
+    JOIN Application newer ON newer.applicantId = a.applicantId AND newer.id > a.id;
+
+ALTER TABLE Application
+    DROP FOREIGN KEY fk_application_reviewer,
+    DROP COLUMN reviewedAt,
+    DROP COLUMN reviewedBy,
+    DROP COLUMN rejectionReason,
+    ADD UNIQUE INDEX applicantId (applicantId);
+
+ALTER TABLE Application
+    DROP INDEX idx_application_applicant;
+
+ALTER TABLE ApplicationProof
+    DROP FOREIGN KEY fk_application_proof_applicant;
+
+ALTER TABLE ApplicationProof
+    ADD CONSTRAINT ApplicationProof_ibfk_2 FOREIGN KEY(applicantId) REFERENCES Application(applicantId);
@@ -0,0 +1,17 @@
+-- Proofs referenced Application(applicantId), which forced the column to be unique
+ALTER TABLE ApplicationProof
+    DROP FOREIGN KEY ApplicationProof_ibfk_2;
+
+ALTER TABLE ApplicationProof
+    ADD CONSTRAINT fk_application_proof_applicant FOREIGN KEY(applicantId) REFERENCES User(id);
+
+-- Keep an index for the User foreign key before dropping the unique constraint
+ALTER TABLE Application
+    ADD INDEX idx_application_applicant (applicantId);
+
+ALTER TABLE Application
+    DROP INDEX applicantId,
+    ADD COLUMN rejectionReason Text AFTER status,
+    ADD COLUMN reviewedBy Int AFTER rejectionReason,
+    ADD COLUMN reviewedAt TIMESTAMP NULL AFTER reviewedBy,
+    ADD CONSTRAINT fk_application_reviewer FOREIGN KEY(reviewedBy) REFERENCES Admin(id);
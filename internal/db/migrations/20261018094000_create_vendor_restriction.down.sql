@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS VendorRestrictionAppeal;
+DROP TABLE IF EXISTS VendorRestriction;
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS VendorRestriction (
+    id Int NOT NULL AUTO_INCREMENT,
+    vendorId Int NOT NULL,
+    reason Text NULL,
+    issuedBy Int NOT NULL,
+    complaintId Int,
+    startsAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expiresAt TIMESTAMP NULL,
+    liftedAt TIMESTAMP NULL,
+    liftedBy Int,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(vendorId) REFERENCES User(id),
+    FOREIGN KEY(issuedBy) REFERENCES Admin(id),
+    FOREIGN KEY(complaintId) REFERENCES VendorComplaint(id),
+    FOREIGN KEY(liftedBy) REFERENCES Admin(id),
+    INDEX idx_vendor_restriction_active (vendorId, liftedAt),
+    INDEX idx_vendor_restriction_expiry (liftedAt, expiresAt)
+);
+
+CREATE TABLE IF NOT EXISTS VendorRestrictionAppeal (
+    id Int NOT NULL AUTO_INCREMENT,
+    restrictionId Int NOT NULL,
+    vendorId Int NOT NULL,
+    content Text NOT NULL,
+    status Enum('pending', 'accepted', 'rejected') NOT NULL DEFAULT 'pending',
+    reviewedBy Int,
+    reviewNote Text,
+    reviewedAt TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(restrictionId) REFERENCES VendorRestriction(id),
+    FOREIGN KEY(vendorId) REFERENCES User(id),
+    FOREIGN KEY(reviewedBy) REFERENCES Admin(id),
+    INDEX idx_vendor_restriction_appeal_status (status)
+);
+
+-- Record a restriction for vendors that were flagged before restrictions were
+-- tracked. Reasons are stored encrypted, which cannot be done from here, so
+-- these have none
+INSERT INTO VendorRestriction (vendorId, reason, issuedBy)
+SELECT v.vendorId, NULL, (SELECT MIN(id) FROM Admin)
+FROM Vendor v
+WHERE v.restricted = 1 AND EXISTS (SELECT 1 FROM Admin);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS DeviceToken;
+DROP TABLE IF EXISTS Notification;
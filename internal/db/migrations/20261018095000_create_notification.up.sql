@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS Notification (
+    id Int NOT NULL AUTO_INCREMENT,
+    userId Int NOT NULL,
+    type Varchar(64) NOT NULL,
+    payload JSON NOT NULL,
+    readAt TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(userId) REFERENCES User(id),
+    INDEX idx_notification_user_read (userId, readAt)
+);
+
+CREATE TABLE IF NOT EXISTS DeviceToken (
+    id Int NOT NULL AUTO_INCREMENT,
+    userId Int NOT NULL,
+    token Varchar(255) NOT NULL UNIQUE,
+    platform Enum('android', 'ios', 'web') NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(userId) REFERENCES User(id),
+    INDEX idx_device_token_user (userId)
+);
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS Outbox;
+
+DROP TRIGGER IF EXISTS update_vendor_rating;
+CREATE TRIGGER update_vendor_rating
+AFTER INSERT ON Review
+FOR EACH ROW
+BEGIN
+    DECLARE avg_rating DECIMAL(5,1);
+
+    SELECT ROUND(AVG(rating), 1) INTO avg_rating
+    FROM Review
+    WHERE serviceId = NEW.serviceId;
+
+    UPDATE Vendor
+    SET rating = avg_rating
+    WHERE vendorId = (SELECT vendorId FROM Service WHERE id = NEW.serviceId);
+END;
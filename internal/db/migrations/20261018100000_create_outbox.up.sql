@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS Outbox (
+    id Int NOT NULL AUTO_INCREMENT,
+    eventType Varchar(64) NOT NULL,
+    aggregateId Int NOT NULL,
+    payload JSON NOT NULL,
+    attempts Int NOT NULL DEFAULT 0,
+    lastError Text,
+    availableAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatchedAt TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    INDEX idx_outbox_pending (dispatchedAt, availableAt)
+);
+
+-- Vendor ratings are now recalculated by the ReviewPosted subscriber
+DROP TRIGGER IF EXISTS update_vendor_rating;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS Webhook (
+    id Int NOT NULL AUTO_INCREMENT,
+    url Varchar(2048) NOT NULL,
+    secret Text NOT NULL,
+    events Varchar(512) NOT NULL,
+    active TinyInt NOT NULL DEFAULT 1,
+    createdBy Int NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(createdBy) REFERENCES Admin(id)
+);
+
+CREATE TABLE IF NOT EXISTS WebhookDelivery (
+    id Int NOT NULL AUTO_INCREMENT,
+    webhookId Int NOT NULL,
+    outboxId Int NOT NULL,
+    eventType Varchar(64) NOT NULL,
+    payload JSON NOT NULL,
+    status Enum('pending', 'succeeded', 'failed') NOT NULL DEFAULT 'pending',
+    attempts Int NOT NULL DEFAULT 0,
+    responseCode Int NULL,
+    lastError Text,
+    nextAttemptAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deliveredAt TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(webhookId) REFERENCES Webhook(id) ON DELETE CASCADE,
+    FOREIGN KEY(outboxId) REFERENCES Outbox(id),
+    UNIQUE INDEX idx_webhook_delivery_event (webhookId, outboxId),
+    INDEX idx_webhook_delivery_pending (status, nextAttemptAt)
+);
+
+CREATE TABLE IF NOT EXISTS WebhookDeliveryAttempt (
+    id Int NOT NULL AUTO_INCREMENT,
+    deliveryId Int NOT NULL,
+    responseCode Int NULL,
+    error Text,
+    durationMs Int NOT NULL DEFAULT 0,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(deliveryId) REFERENCES WebhookDelivery(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+ALTER TABLE Service DROP INDEX idx_service_location;
+
+ALTER TABLE Service DROP COLUMN location;
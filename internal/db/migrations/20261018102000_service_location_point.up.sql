@@ -0,0 +1,8 @@
+ALTER TABLE Service ADD COLUMN location POINT SRID 4326 NULL;
+
+UPDATE Service SET location = ST_GeomFromText(CONCAT('POINT(', longitude, ' ', latitude, ')'), 4326, 'axis-order=long-lat');
+
+-- Spatial indexes require a NOT NULL column restricted to a single SRID
+ALTER TABLE Service MODIFY COLUMN location POINT SRID 4326 NOT NULL;
+
+ALTER TABLE Service ADD SPATIAL INDEX idx_service_location (location);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS TagSynonym;
+
+ALTER TABLE Tag
+    DROP FOREIGN KEY fk_tag_merged_into,
+    DROP FOREIGN KEY fk_tag_category,
+    DROP INDEX idx_tag_title,
+    DROP COLUMN retiredAt,
+    DROP COLUMN mergedInto,
+    DROP COLUMN categoryId;
+
+ALTER TABLE ServiceTag DROP INDEX idx_service_tag_unique;
+
+DROP TABLE IF EXISTS TagCategory;
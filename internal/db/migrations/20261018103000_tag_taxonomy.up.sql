@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS TagCategory (
+    id Int NOT NULL AUTO_INCREMENT,
+    title Varchar(255) NOT NULL UNIQUE,
+    parentId Int NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(parentId) REFERENCES TagCategory(id)
+);
+
+-- Titles become unique so point services at the oldest duplicate first
+UPDATE ServiceTag st
+    JOIN Tag t ON t.id = st.tagId
+    JOIN (SELECT title, MIN(id) AS keepId FROM Tag GROUP BY title) k ON k.title = t.title
+SET st.tagId = k.keepId
+WHERE t.id <> k.keepId;
+
+DELETE t FROM Tag t
+    JOIN (SELECT title, MIN(id) AS keepId FROM Tag GROUP BY title) k ON k.title = t.title
+WHERE t.id <> k.keepId;
+
+DELETE st FROM ServiceTag st
+    JOIN ServiceTag keep ON keep.serviceId = st.serviceId AND keep.tagId = st.tagId AND keep.id < st.id;
+
+ALTER TABLE ServiceTag ADD UNIQUE INDEX idx_service_tag_unique (serviceId, tagId);
+
+ALTER TABLE Tag
+    ADD COLUMN categoryId Int NULL,
+    ADD COLUMN mergedInto Int NULL,
+    ADD COLUMN retiredAt TIMESTAMP NULL,
+    ADD UNIQUE INDEX idx_tag_title (title),
+    ADD CONSTRAINT fk_tag_category FOREIGN KEY(categoryId) REFERENCES TagCategory(id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_tag_merged_into FOREIGN KEY(mergedInto) REFERENCES Tag(id);
+
+CREATE TABLE IF NOT EXISTS TagSynonym (
+    id Int NOT NULL AUTO_INCREMENT,
+    tagId Int NOT NULL,
+    title Varchar(255) NOT NULL UNIQUE,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(tagId) REFERENCES Tag(id) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS ServiceAddOn;
+
+ALTER TABLE Service
+    DROP COLUMN currency,
+    DROP COLUMN minimumCharge,
+    DROP COLUMN pricingUnit,
+    DROP COLUMN title;
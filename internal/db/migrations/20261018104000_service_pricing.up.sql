@@ -0,0 +1,24 @@
+ALTER TABLE Service
+    ADD COLUMN title Varchar(120) NOT NULL DEFAULT '' AFTER vendorId,
+    ADD COLUMN pricingUnit ENUM('hour', 'visit', 'fixed') NOT NULL DEFAULT 'fixed' AFTER rate,
+    ADD COLUMN minimumCharge Double NOT NULL DEFAULT 0 AFTER pricingUnit,
+    ADD COLUMN currency Char(3) NOT NULL DEFAULT 'PHP' AFTER minimumCharge;
+
+-- Descriptions are encrypted so existing services are titled after their first tag
+UPDATE Service s
+SET s.title = COALESCE(
+    (SELECT t.title FROM ServiceTag st JOIN Tag t ON t.id = st.tagId WHERE st.serviceId = s.id ORDER BY st.id LIMIT 1),
+    'Service'
+)
+WHERE s.title = '';
+
+CREATE TABLE IF NOT EXISTS ServiceAddOn (
+    id Int NOT NULL AUTO_INCREMENT,
+    serviceId Int NOT NULL,
+    title Varchar(120) NOT NULL,
+    price Double NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(serviceId) REFERENCES Service(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+ALTER TABLE Message
+    DROP FOREIGN KEY fk_message_quote,
+    DROP COLUMN quoteId,
+    DROP COLUMN type;
+
+ALTER TABLE Transaction
+    DROP FOREIGN KEY fk_transaction_quote,
+    DROP COLUMN quoteId,
+    DROP COLUMN currency,
+    DROP COLUMN price;
+
+DROP TABLE IF EXISTS QuoteLineItem;
+DROP TABLE IF EXISTS QuoteVersion;
+DROP TABLE IF EXISTS Quote;
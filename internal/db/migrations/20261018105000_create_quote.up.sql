@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS Quote (
+    id Int NOT NULL AUTO_INCREMENT,
+    vendorId Int NOT NULL,
+    clientId Int NOT NULL,
+    serviceId Int NOT NULL,
+    status Enum('pending', 'accepted', 'declined') NOT NULL DEFAULT 'pending',
+    version Int NOT NULL DEFAULT 1,
+    transactionId Int NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(vendorId) REFERENCES User(id),
+    FOREIGN KEY(clientId) REFERENCES User(id),
+    FOREIGN KEY(serviceId) REFERENCES Service(id) ON DELETE CASCADE,
+    FOREIGN KEY(transactionId) REFERENCES Transaction(id) ON DELETE SET NULL
+);
+
+-- Every revision is kept so both parties can see what changed
+CREATE TABLE IF NOT EXISTS QuoteVersion (
+    id Int NOT NULL AUTO_INCREMENT,
+    quoteId Int NOT NULL,
+    version Int NOT NULL,
+    total Double NOT NULL,
+    currency Char(3) NOT NULL,
+    start TIMESTAMP NOT NULL,
+    end TIMESTAMP NOT NULL,
+    validUntil DATE NOT NULL,
+    note Varchar(255) NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    UNIQUE(quoteId, version),
+    FOREIGN KEY(quoteId) REFERENCES Quote(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS QuoteLineItem (
+    id Int NOT NULL AUTO_INCREMENT,
+    quoteVersionId Int NOT NULL,
+    title Varchar(120) NOT NULL,
+    quantity Double NOT NULL,
+    unitPrice Double NOT NULL,
+    PRIMARY KEY(id),
+    FOREIGN KEY(quoteVersionId) REFERENCES QuoteVersion(id) ON DELETE CASCADE
+);
+
+ALTER TABLE Transaction
+    ADD COLUMN price Double NULL,
+    ADD COLUMN currency Char(3) NULL,
+    ADD COLUMN quoteId Int NULL,
+    ADD CONSTRAINT fk_transaction_quote FOREIGN KEY(quoteId) REFERENCES Quote(id) ON DELETE SET NULL;
+
+ALTER TABLE Message
+    ADD COLUMN type Enum('text', 'quote', 'quote_accepted', 'quote_declined') NOT NULL DEFAULT 'text',
+    ADD COLUMN quoteId Int NULL,
+    ADD CONSTRAINT fk_message_quote FOREIGN KEY(quoteId) REFERENCES Quote(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS PaymentWebhookEvent;
+DROP TABLE IF EXISTS LedgerEntry;
+DROP TABLE IF EXISTS LedgerJournal;
+DROP TABLE IF EXISTS Payment;
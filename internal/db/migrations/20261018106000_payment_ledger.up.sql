@@ -0,0 +1,61 @@
+-- One payment per booking, reference is the charge id given by the provider
+CREATE TABLE IF NOT EXISTS Payment (
+    id Int NOT NULL AUTO_INCREMENT,
+    transactionId Int NOT NULL,
+    clientId Int NOT NULL,
+    vendorId Int NOT NULL,
+    provider Varchar(32) NOT NULL,
+    reference Varchar(128) NOT NULL,
+    amount Decimal(12, 2) NOT NULL,
+    currency Char(3) NOT NULL,
+    status Enum('authorized', 'captured', 'released', 'refunded', 'failed') NOT NULL DEFAULT 'authorized',
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    UNIQUE(transactionId),
+    UNIQUE(provider, reference),
+    FOREIGN KEY(transactionId) REFERENCES Transaction(id) ON DELETE CASCADE,
+    FOREIGN KEY(clientId) REFERENCES User(id),
+    FOREIGN KEY(vendorId) REFERENCES User(id)
+);
+
+-- Each movement of money happens at most once per payment, the entries of a
+-- journal always balance
+CREATE TABLE IF NOT EXISTS LedgerJournal (
+    id Int NOT NULL AUTO_INCREMENT,
+    paymentId Int NOT NULL,
+    type Enum('charge', 'release', 'refund') NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    UNIQUE(paymentId, type),
+    FOREIGN KEY(paymentId) REFERENCES Payment(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS LedgerEntry (
+    id Int NOT NULL AUTO_INCREMENT,
+    journalId Int NOT NULL,
+    account Enum('provider_clearing', 'escrow', 'vendor_payable', 'platform_fees') NOT NULL,
+    userId Int NULL,
+    debit Decimal(12, 2) NOT NULL DEFAULT 0,
+    credit Decimal(12, 2) NOT NULL DEFAULT 0,
+    currency Char(3) NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    INDEX idx_ledger_entry_account(account, currency),
+    FOREIGN KEY(journalId) REFERENCES LedgerJournal(id) ON DELETE CASCADE,
+    FOREIGN KEY(userId) REFERENCES User(id) ON DELETE SET NULL
+);
+
+-- Provider webhooks are retried, events already applied are skipped
+CREATE TABLE IF NOT EXISTS PaymentWebhookEvent (
+    id Int NOT NULL AUTO_INCREMENT,
+    provider Varchar(32) NOT NULL,
+    eventId Varchar(128) NOT NULL,
+    type Varchar(64) NOT NULL,
+    reference Varchar(128) NOT NULL,
+    amount Decimal(12, 2) NOT NULL DEFAULT 0,
+    payload JSON NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    UNIQUE(provider, eventId)
+);
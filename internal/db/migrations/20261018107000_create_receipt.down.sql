@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS Receipt;
+DROP TABLE IF EXISTS InvoiceSequence;
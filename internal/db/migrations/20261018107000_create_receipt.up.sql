@@ -0,0 +1,30 @@
+-- Invoice numbers run per vendor without gaps
+CREATE TABLE IF NOT EXISTS InvoiceSequence (
+    vendorId Int NOT NULL,
+    lastNumber Int NOT NULL DEFAULT 0,
+    PRIMARY KEY(vendorId),
+    FOREIGN KEY(vendorId) REFERENCES User(id) ON DELETE CASCADE
+);
+
+-- A corrected receipt keeps its invoice number and gets the next revision
+CREATE TABLE IF NOT EXISTS Receipt (
+    id Int NOT NULL AUTO_INCREMENT,
+    transactionId Int NOT NULL,
+    vendorId Int NOT NULL,
+    clientId Int NOT NULL,
+    invoiceNumber Int NOT NULL,
+    revision Int NOT NULL DEFAULT 1,
+    subtotal Decimal(12, 2) NOT NULL,
+    tax Decimal(12, 2) NOT NULL,
+    total Decimal(12, 2) NOT NULL,
+    currency Char(3) NOT NULL,
+    path Varchar(255) NOT NULL,
+    reason Varchar(255) NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    UNIQUE(transactionId, revision),
+    UNIQUE(vendorId, invoiceNumber, revision),
+    FOREIGN KEY(transactionId) REFERENCES Transaction(id) ON DELETE CASCADE,
+    FOREIGN KEY(vendorId) REFERENCES User(id),
+    FOREIGN KEY(clientId) REFERENCES User(id)
+);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS TransactionCancellation;
+
+ALTER TABLE Transaction
+    DROP COLUMN cancellationWindows,
+    DROP COLUMN cancellationPolicy;
+
+ALTER TABLE Service
+    DROP COLUMN cancellationWindows,
+    DROP COLUMN cancellationPolicy;
@@ -0,0 +1,26 @@
+-- Windows are only stored for custom policies, presets are defined in code
+ALTER TABLE Service
+    ADD COLUMN cancellationPolicy Enum('flexible', 'moderate', 'strict', 'custom') NOT NULL DEFAULT 'flexible',
+    ADD COLUMN cancellationWindows JSON NULL;
+
+-- Snapshot of the policy when the booking was made, later edits to the
+-- service do not apply to it
+ALTER TABLE Transaction
+    ADD COLUMN cancellationPolicy Enum('flexible', 'moderate', 'strict', 'custom') NULL,
+    ADD COLUMN cancellationWindows JSON NULL;
+
+CREATE TABLE IF NOT EXISTS TransactionCancellation (
+    transactionId Int NOT NULL,
+    cancelledBy Int NOT NULL,
+    role Enum('client', 'vendor') NOT NULL,
+    hoursBefore Double NOT NULL,
+    penaltyPercent Double NOT NULL DEFAULT 0,
+    penalty Decimal(12, 2) NOT NULL DEFAULT 0,
+    refund Decimal(12, 2) NOT NULL DEFAULT 0,
+    reason Varchar(255) NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(transactionId),
+    INDEX idx_cancellation_user(cancelledBy, role),
+    FOREIGN KEY(transactionId) REFERENCES Transaction(id) ON DELETE CASCADE,
+    FOREIGN KEY(cancelledBy) REFERENCES User(id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS AnalyticsDaily;
+
+ALTER TABLE Transaction DROP COLUMN completedAt;
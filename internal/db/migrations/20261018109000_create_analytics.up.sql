@@ -0,0 +1,24 @@
+-- updatedAt also moves when a booking is reviewed, completions need their own
+-- timestamp to land on the right day
+ALTER TABLE Transaction ADD COLUMN completedAt TIMESTAMP NULL;
+
+UPDATE Transaction SET completedAt = updatedAt, updatedAt = updatedAt WHERE status = 'done';
+
+-- Daily totals per dimension, rebuilt by the analytics job. Rates and averages
+-- are derived when queried so weeks and months roll up correctly
+CREATE TABLE IF NOT EXISTS AnalyticsDaily (
+    day DATE NOT NULL,
+    dimension Enum('all', 'tag', 'area') NOT NULL,
+    dimensionKey Varchar(32) NOT NULL DEFAULT '',
+    signups Int NOT NULL DEFAULT 0,
+    newVendors Int NOT NULL DEFAULT 0,
+    bookingsCreated Int NOT NULL DEFAULT 0,
+    bookingsCompleted Int NOT NULL DEFAULT 0,
+    bookingsCancelled Int NOT NULL DEFAULT 0,
+    ratingSum Int NOT NULL DEFAULT 0,
+    ratingCount Int NOT NULL DEFAULT 0,
+    complaints Int NOT NULL DEFAULT 0,
+    refreshedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(day, dimension, dimensionKey),
+    INDEX idx_analytics_dimension(dimension, dimensionKey, day)
+);
@@ -0,0 +1,3 @@
+DROP INDEX idx_message_conversation ON Message;
+
+DROP TABLE IF EXISTS ActivityDaily;
@@ -0,0 +1,12 @@
+-- Daily view and search impression counts of vendor profiles and services
+CREATE TABLE IF NOT EXISTS ActivityDaily (
+    subject Enum('vendor', 'service') NOT NULL,
+    subjectId Int NOT NULL,
+    day DATE NOT NULL,
+    views Int NOT NULL DEFAULT 0,
+    impressions Int NOT NULL DEFAULT 0,
+    PRIMARY KEY(subject, subjectId, day)
+);
+
+-- Conversations are looked up by who received the first message
+CREATE INDEX idx_message_conversation ON Message(receiver, sender, createdAt);
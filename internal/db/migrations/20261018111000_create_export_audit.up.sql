@@ -0,0 +1,18 @@
+-- Every export of an admin list, kept to audit who took data out and whether
+-- it included decrypted personal data
+CREATE TABLE IF NOT EXISTS ExportAudit (
+    id Int NOT NULL AUTO_INCREMENT,
+    adminId Int NOT NULL,
+    list Enum('users', 'vendors', 'applications', 'transactions', 'system_complaints', 'vendor_complaints', 'verifications') NOT NULL,
+    format Enum('csv', 'xlsx') NOT NULL,
+    filters Varchar(512) NOT NULL DEFAULT '',
+    decrypted TINYINT(1) NOT NULL DEFAULT 0,
+    status Enum('started', 'completed', 'failed') NOT NULL DEFAULT 'started',
+    rowCount Int NOT NULL DEFAULT 0,
+    error Text,
+    completedAt TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(id),
+    FOREIGN KEY(adminId) REFERENCES Admin(id),
+    INDEX(adminId, createdAt)
+);
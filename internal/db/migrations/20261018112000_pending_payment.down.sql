@@ -0,0 +1,5 @@
+DELETE FROM Payment WHERE status = 'pending';
+
+ALTER TABLE Payment
+    MODIFY reference Varchar(128) NOT NULL,
+    MODIFY status Enum('authorized', 'captured', 'released', 'refunded', 'failed') NOT NULL DEFAULT 'authorized';
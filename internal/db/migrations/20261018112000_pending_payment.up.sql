@@ -0,0 +1,5 @@
+-- Payments are recorded as pending before the provider is called, the charge
+-- reference is only known once it answered
+ALTER TABLE Payment
+    MODIFY reference Varchar(128) NULL,
+    MODIFY status Enum('pending', 'authorized', 'captured', 'released', 'refunded', 'failed') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,8 @@
+-- Subscribers that already handled an event, a retry only runs the others
+CREATE TABLE IF NOT EXISTS OutboxDelivery (
+    outboxId Int NOT NULL,
+    subscriber Varchar(64) NOT NULL,
+    deliveredAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(outboxId, subscriber),
+    FOREIGN KEY(outboxId) REFERENCES Outbox(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DELETE FROM Receipt WHERE path IS NULL;
+ALTER TABLE Receipt MODIFY path Varchar(255) NOT NULL;
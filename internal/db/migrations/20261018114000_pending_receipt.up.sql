@@ -0,0 +1,2 @@
+-- A receipt without a path has its number reserved but is still being rendered
+ALTER TABLE Receipt MODIFY path Varchar(255) NULL;
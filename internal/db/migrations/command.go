@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"nearbyassist/internal/config"
+	"strconv"
+)
+
+const MIGRATE_USAGE = `Usage: migrate <command> [argument]
+
+Commands:
+  up            Apply all pending migrations
+  down [N]      Roll back N migrations, or all migrations when N is omitted
+  goto V        Migrate up or down to version V
+  version       Print the current migration version
+  force V       Set the version to V without running migrations (fixes a dirty state)
+  status        List all migrations and whether they are applied`
+
+// Runs the migrate subcommand, args excludes the "migrate" keyword itself
+func RunCommand(conf *config.Config, args []string) error {
+	if len(args) == 0 {
+		fmt.Println(MIGRATE_USAGE)
+		return nil
+	}
+
+	m, err := NewMigrator(conf)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		if err := m.Up(); err != nil {
+			return err
+		}
+
+	case "down":
+		steps := 0
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return errors.New("N must be a positive number")
+			}
+			steps = n
+		}
+
+		if err := m.Down(steps); err != nil {
+			return err
+		}
+
+	case "goto":
+		if len(args) < 2 {
+			return errors.New("goto requires a version")
+		}
+
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return errors.New("version must be a number")
+		}
+
+		if err := m.Goto(uint(version)); err != nil {
+			return err
+		}
+
+	case "force":
+		if len(args) < 2 {
+			return errors.New("force requires a version")
+		}
+
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return errors.New("version must be a number")
+		}
+
+		if err := m.Force(version); err != nil {
+			return err
+		}
+
+	case "version":
+
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+
+			fmt.Printf("%-8s %d %s\n", state, status.Version, status.Name)
+		}
+
+		return nil
+
+	default:
+		fmt.Println(MIGRATE_USAGE)
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d, dirty: %v\n", version, dirty)
+	return nil
+}
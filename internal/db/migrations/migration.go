@@ -1,52 +1,204 @@
-package main
+package migrations
 
 import (
-	"flag"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
 	"fmt"
+	"log"
+	"nearbyassist/internal/config"
 	"os"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/joho/godotenv"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
-func main() {
-	migrateDown := flag.Bool("down", false, "Specify true to run down migration")
-	migrateUp := flag.Bool("up", false, "Specify true to run up migration")
-	flag.Parse()
+const (
+	MIGRATION_LOCK_NAME    = "nearbyassist_migration"
+	MIGRATION_LOCK_TIMEOUT = 60
+)
+
+//go:embed *.sql
+var migrationFiles embed.FS
+
+type MigrationStatus struct {
+	Version uint   `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+type Migrator struct {
+	db      *sql.DB
+	source  source.Driver
+	migrate *migrate.Migrate
+}
+
+func NewMigrator(conf *config.Config) (*Migrator, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?multiStatements=true", conf.DB_User, conf.DB_Password, conf.DB_Host, conf.DB_Port, conf.DB_Name)
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(migrationFiles, ".")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	driver, err := migratemysql.WithInstance(db, &migratemysql.Config{DatabaseName: conf.DB_Name})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, conf.DB_Name, driver)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Migrator{
+		db:      db,
+		source:  src,
+		migrate: m,
+	}, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.migrate.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+
+	return dbErr
+}
+
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// Rolls back the given number of migrations, a non-positive value rolls back everything
+func (m *Migrator) Down(steps int) error {
+	var err error
+	if steps > 0 {
+		err = m.migrate.Steps(-steps)
+	} else {
+		err = m.migrate.Down()
+	}
+
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Migrator) Goto(version uint) error {
+	if err := m.migrate.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
 
-	godotenv.Load()
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
 
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	name := os.Getenv("DB_NAME")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
 
-	dsn := fmt.Sprintf("mysql://%s:%s@tcp(%s:%s)/%s", user, password, host, port, name)
+	return version, dirty, err
+}
 
-	m, err := migrate.New("file:///home/johnloydmulit/go/personal/nearbyassist/internal/db/migrations/", dsn)
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	current, _, err := m.Version()
 	if err != nil {
-		panic("Error occurred while creating migration: " + err.Error())
+		return nil, err
 	}
 
-	if *migrateDown {
-		err := m.Down()
-		if err != nil {
-			panic("Error occurred while migrating down: " + err.Error())
+	statuses := make([]MigrationStatus, 0)
+
+	version, err := m.source.First()
+	for err == nil {
+		name := ""
+		if reader, identifier, readErr := m.source.ReadUp(version); readErr == nil {
+			reader.Close()
+			name = identifier
 		}
-		return
+
+		statuses = append(statuses, MigrationStatus{
+			Version: version,
+			Name:    name,
+			Applied: current != 0 && version <= current,
+		})
+
+		version, err = m.source.Next(version)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// Runs all pending migrations while holding a database advisory lock so
+// that multiple server instances starting at the same time do not race
+func AutoMigrate(conf *config.Config) error {
+	m, err := NewMigrator(conf)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), (MIGRATION_LOCK_TIMEOUT+5)*time.Second)
+	defer cancel()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
 	}
+	defer conn.Close()
 
-	if *migrateUp {
-		err := m.Up()
-		if err != nil {
-			panic("Error occurred while migrating up: " + err.Error())
+	acquired := 0
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", MIGRATION_LOCK_NAME, MIGRATION_LOCK_TIMEOUT).Scan(&acquired); err != nil {
+		return err
+	}
+
+	if acquired != 1 {
+		return errors.New("Could not acquire migration lock")
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", MIGRATION_LOCK_NAME); err != nil {
+			log.Printf("error releasing migration lock: %v\n", err)
 		}
-		return
+	}()
+
+	if err := m.Up(); err != nil {
+		return err
+	}
+
+	version, _, err := m.Version()
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("Please specify down or up migration by setting -down / -up flag to true")
+	log.Printf("Database migrated to version %d\n", version)
+	return nil
 }
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+var createTrigger = regexp.MustCompile(`CREATE TRIGGER (\w+)`)
+
+func TestEmbeddedMigrationsAreExecutable(t *testing.T) {
+	src, err := iofs.New(migrationFiles, ".")
+	if err != nil {
+		t.Fatalf("Failed to load embedded migrations: %s", err.Error())
+	}
+	defer src.Close()
+
+	count := 0
+	version, err := src.First()
+	for err == nil {
+		up, _, upErr := src.ReadUp(version)
+		if upErr != nil {
+			t.Fatalf("Missing up migration for version %d", version)
+		}
+
+		body, _ := io.ReadAll(up)
+		up.Close()
+
+		// DELIMITER is a mysql client command and cannot be executed by the driver
+		if strings.Contains(strings.ToUpper(string(body)), "DELIMITER") {
+			t.Errorf("Migration %d uses DELIMITER", version)
+		}
+
+		if trigger := unguardedTrigger(string(body)); trigger != "" {
+			t.Errorf("Migration %d creates trigger %s without dropping it first", version, trigger)
+		}
+
+		if down, _, downErr := src.ReadDown(version); downErr != nil {
+			t.Errorf("Missing down migration for version %d", version)
+		} else {
+			body, _ := io.ReadAll(down)
+			down.Close()
+
+			if trigger := unguardedTrigger(string(body)); trigger != "" {
+				t.Errorf("Down migration %d creates trigger %s without dropping it first", version, trigger)
+			}
+		}
+
+		count++
+		version, err = src.Next(version)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	if count == 0 {
+		t.Fatal("Expected embedded migrations")
+	}
+}
+
+// MySQL has no CREATE OR REPLACE for triggers, so a rerun needs the drop
+func unguardedTrigger(body string) string {
+	for _, match := range createTrigger.FindAllStringSubmatch(body, -1) {
+		if !strings.Contains(body, "DROP TRIGGER IF EXISTS "+match[1]+";") {
+			return match[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,254 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"strings"
+	"time"
+)
+
+// Where one or more daily metrics come from. Sources with a service alias can
+// be split by the tags and area of that service
+type analyticsSource struct {
+	columns   []string
+	values    []string
+	from      string
+	condition string
+	date      string
+	service   string
+}
+
+var analyticsSources = []analyticsSource{
+	{
+		columns: []string{"signups"},
+		values:  []string{"COUNT(*)"},
+		from:    "User u",
+		date:    "u.createdAt",
+	},
+	{
+		columns: []string{"newVendors"},
+		values:  []string{"COUNT(*)"},
+		from:    "Vendor v",
+		date:    "v.createdAt",
+	},
+	{
+		columns: []string{"bookingsCreated"},
+		values:  []string{"COUNT(*)"},
+		from:    "Transaction t JOIN Service s ON s.id = t.serviceId",
+		date:    "t.createdAt",
+		service: "s",
+	},
+	{
+		columns:   []string{"bookingsCompleted"},
+		values:    []string{"COUNT(*)"},
+		from:      "Transaction t JOIN Service s ON s.id = t.serviceId",
+		condition: "t.status = 'done'",
+		date:      "COALESCE(t.completedAt, t.updatedAt)",
+		service:   "s",
+	},
+	{
+		columns:   []string{"bookingsCancelled"},
+		values:    []string{"COUNT(*)"},
+		from:      "Transaction t JOIN Service s ON s.id = t.serviceId LEFT JOIN TransactionCancellation tc ON tc.transactionId = t.id",
+		condition: "t.status = 'cancelled'",
+		date:      "COALESCE(tc.createdAt, t.updatedAt)",
+		service:   "s",
+	},
+	{
+		columns: []string{"ratingSum", "ratingCount"},
+		values:  []string{"SUM(r.rating)", "COUNT(*)"},
+		from:    "Review r JOIN Service s ON s.id = r.serviceId",
+		date:    "r.createdAt",
+		service: "s",
+	},
+	{
+		columns: []string{"complaints"},
+		values:  []string{"COUNT(*)"},
+		from:    "VendorComplaint vc JOIN Transaction t ON t.id = vc.transactionId JOIN Service s ON s.id = t.serviceId",
+		date:    "vc.createdAt",
+		service: "s",
+	},
+	{
+		columns: []string{"complaints"},
+		values:  []string{"COUNT(*)"},
+		from:    "SystemComplaint sc",
+		date:    "sc.createdAt",
+	},
+}
+
+// Builds the statement adding one source to the daily totals of a dimension.
+// Rows are added to rather than replaced since several sources can feed the
+// same column
+func analyticsRefreshQuery(source analyticsSource, split models.AnalyticsSplit) string {
+	key, join := "''", ""
+	switch split {
+	case models.ANALYTICS_SPLIT_TAG:
+		key = "CAST(st.tagId AS CHAR)"
+		join = fmt.Sprintf(" JOIN ServiceTag st ON st.serviceId = %s.id", source.service)
+	case models.ANALYTICS_SPLIT_AREA:
+		key = fmt.Sprintf("CONCAT(ROUND(%[1]s.latitude, %[2]d), ',', ROUND(%[1]s.longitude, %[2]d))", source.service, models.ANALYTICS_AREA_PRECISION)
+	}
+
+	selected := make([]string, len(source.columns))
+	updates := make([]string, len(source.columns))
+	for i, column := range source.columns {
+		selected[i] = fmt.Sprintf("%s AS %s", source.values[i], column)
+		updates[i] = fmt.Sprintf("%[1]s = AnalyticsDaily.%[1]s + source.%[1]s", column)
+	}
+
+	condition := ""
+	if source.condition != "" {
+		condition = " AND " + source.condition
+	}
+
+	columns := strings.Join(source.columns, ", ")
+
+	return fmt.Sprintf(`
+        INSERT INTO
+            AnalyticsDaily (day, dimension, dimensionKey, %[1]s)
+        SELECT
+            day, dimension, dimensionKey, %[1]s
+        FROM (
+            SELECT
+                DATE(%[2]s) AS day, '%[3]s' AS dimension, %[4]s AS dimensionKey, %[5]s
+            FROM
+                %[6]s%[7]s
+            WHERE
+                %[2]s >= ?%[8]s
+            GROUP BY
+                day, dimensionKey
+        ) AS source
+        ON DUPLICATE KEY UPDATE
+            %[9]s
+    `, columns, source.date, split, key, strings.Join(selected, ", "), source.from, join, condition, strings.Join(updates, ", "))
+}
+
+// The first day of the bucket a date falls in, matching models.AnalyticsBucket
+func analyticsBucketExpression(bucket models.AnalyticsBucket, day string) string {
+	switch bucket {
+	case models.ANALYTICS_BUCKET_WEEK:
+		return fmt.Sprintf("DATE_FORMAT(DATE_SUB(%[1]s, INTERVAL WEEKDAY(%[1]s) DAY), '%%Y-%%m-%%d')", day)
+	case models.ANALYTICS_BUCKET_MONTH:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", day)
+	}
+
+	return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", day)
+}
+
+// Rebuilds every daily total from the given day on. The old rows are replaced
+// in one transaction so the dashboard never reads a half built day
+func (m *Mysql) RefreshAnalytics(from string) error {
+	// Backfilling the whole history takes longer than a regular query
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM AnalyticsDaily WHERE day >= ?", from); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	for _, source := range analyticsSources {
+		splits := []models.AnalyticsSplit{models.ANALYTICS_SPLIT_ALL}
+		if source.service != "" {
+			splits = append(splits, models.ANALYTICS_SPLIT_TAG, models.ANALYTICS_SPLIT_AREA)
+		}
+
+		for _, split := range splits {
+			if _, err := tx.ExecContext(ctx, analyticsRefreshQuery(source, split), from); err != nil {
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Latest day with totals, empty before the first refresh
+func (m *Mysql) FindAnalyticsWatermark() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT COALESCE(DATE_FORMAT(MAX(day), '%Y-%m-%d'), '') FROM AnalyticsDaily"
+
+	watermark := ""
+	if err := m.Conn.GetContext(ctx, &watermark, query); err != nil {
+		return "", err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", context.DeadlineExceeded
+	}
+
+	return watermark, nil
+}
+
+func (m *Mysql) FindAnalytics(filter *request.AnalyticsFilter) ([]models.AnalyticsPointModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            ` + analyticsBucketExpression(filter.Bucket, "a.day") + ` AS bucket,
+            a.dimensionKey,
+            COALESCE(tg.title, '') AS label,
+            SUM(a.signups) AS signups,
+            SUM(a.newVendors) AS newVendors,
+            SUM(a.bookingsCreated) AS bookingsCreated,
+            SUM(a.bookingsCompleted) AS bookingsCompleted,
+            SUM(a.bookingsCancelled) AS bookingsCancelled,
+            SUM(a.ratingSum) AS ratingSum,
+            SUM(a.ratingCount) AS ratingCount,
+            SUM(a.complaints) AS complaints
+        FROM
+            AnalyticsDaily a
+            LEFT JOIN Tag tg ON a.dimension = 'tag' AND tg.id = a.dimensionKey
+        WHERE
+            a.dimension = ?
+            AND a.day BETWEEN ? AND ?
+    `
+
+	args := []interface{}{filter.Split, filter.From, filter.To}
+	if filter.Key != "" {
+		query += " AND a.dimensionKey = ?"
+		args = append(args, filter.Key)
+	}
+
+	query += " GROUP BY bucket, a.dimensionKey, label ORDER BY bucket, a.dimensionKey"
+
+	points := make([]models.AnalyticsPointModel, 0)
+	if err := m.Conn.SelectContext(ctx, &points, query, args...); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	for i := range points {
+		points[i].Calculate()
+	}
+
+	return points, nil
+}
@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshAnalytics(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM AnalyticsDaily WHERE day >= \\?").WithArgs("2026-10-16").WillReturnResult(sqlmock.NewResult(0, 12))
+	for _, source := range analyticsSources {
+		mock.ExpectExec("INSERT INTO(.+)AnalyticsDaily(.+)'all' AS dimension").WithArgs("2026-10-16").WillReturnResult(sqlmock.NewResult(0, 1))
+		if source.service != "" {
+			mock.ExpectExec("'tag' AS dimension(.+)JOIN ServiceTag st").WithArgs("2026-10-16").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("'area' AS dimension").WithArgs("2026-10-16").WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+	mock.ExpectCommit()
+
+	err := db.RefreshAnalytics("2026-10-16")
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestAnalyticsRefreshQueryAddsToExisting(t *testing.T) {
+	query := analyticsRefreshQuery(analyticsSources[5], models.ANALYTICS_SPLIT_AREA)
+
+	assert.Contains(t, query, "CONCAT(ROUND(s.latitude, 1), ',', ROUND(s.longitude, 1)) AS dimensionKey")
+	assert.Contains(t, query, "ratingSum = AnalyticsDaily.ratingSum + source.ratingSum, ratingCount = AnalyticsDaily.ratingCount + source.ratingCount")
+}
+
+func TestFindAnalyticsByWeek(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	columns := []string{"bucket", "dimensionKey", "label", "signups", "newVendors", "bookingsCreated", "bookingsCompleted", "bookingsCancelled", "ratingSum", "ratingCount", "complaints"}
+	rows := sqlmock.NewRows(columns).AddRow("2026-10-12", "3", "plumber", 0, 0, 9, 3, 1, 9, 2, 1)
+
+	mock.ExpectQuery("WEEKDAY(.+)FROM(.+)AnalyticsDaily(.+)AND a.dimensionKey = \\?").
+		WithArgs(models.ANALYTICS_SPLIT_TAG, "2026-10-01", "2026-10-31", "3").
+		WillReturnRows(rows)
+
+	points, err := db.FindAnalytics(&request.AnalyticsFilter{
+		From:   "2026-10-01",
+		To:     "2026-10-31",
+		Bucket: models.ANALYTICS_BUCKET_WEEK,
+		Split:  models.ANALYTICS_SPLIT_TAG,
+		Key:    "3",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 1)
+	assert.Equal(t, "plumber", points[0].Label)
+	assert.Equal(t, 0.25, points[0].CancellationRate)
+	assert.Equal(t, 4.5, *points[0].AverageRating)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -37,10 +37,37 @@ func (m *Mysql) CountApplication(status models.ApplicationStatus) (int, error) {
 	return count, nil
 }
 
-func (m *Mysql) CreateApplication(application *request.NewApplication) (int, error) {
+// Rejected applicants may apply again, but only one application can be
+// active. The applicant is locked so concurrent submissions are checked one
+// at a time. Returns false when an application is already pending or approved
+func (m *Mysql) CreateApplication(application *request.NewApplication) (int, bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return -1, false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT id FROM User WHERE id = ? FOR UPDATE", application.ApplicantId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return -1, false, err
+		}
+
+		return -1, false, err
+	}
+
+	active := false
+	findActive := "SELECT EXISTS(SELECT 1 FROM Application WHERE applicantId = ? AND status IN ('pending', 'approved'))"
+
+	if err := tx.GetContext(ctx, &active, findActive, application.ApplicantId); err != nil || active {
+		if err := tx.Rollback(); err != nil {
+			return -1, false, err
+		}
+
+		return -1, false, err
+	}
+
 	query := `
         INSERT INTO
             Application (applicantId, job, latitude, longitude)
@@ -48,21 +75,33 @@ func (m *Mysql) CreateApplication(application *request.NewApplication) (int, err
             (:applicantId, :job, :latitude, :longitude)
     `
 
-	res, err := m.Conn.NamedExecContext(ctx, query, application)
+	res, err := tx.NamedExecContext(ctx, query, application)
 	if err != nil {
-		return -1, err
+		if err := tx.Rollback(); err != nil {
+			return -1, false, err
+		}
+
+		return -1, false, err
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
-		return -1, err
+		if err := tx.Rollback(); err != nil {
+			return -1, false, err
+		}
+
+		return -1, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, false, err
 	}
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return -1, context.DeadlineExceeded
+		return -1, false, context.DeadlineExceeded
 	}
 
-	return int(id), nil
+	return int(id), true, nil
 }
 
 func (m *Mysql) FindApplicationById(id int) (*models.ApplicationModel, error) {
@@ -71,7 +110,9 @@ func (m *Mysql) FindApplicationById(id int) (*models.ApplicationModel, error) {
 
 	query := `
         SELECT
-            id, applicantId, job, status, latitude, longitude
+            id, applicantId, job, status, latitude, longitude,
+            COALESCE(rejectionReason, '') AS rejectionReason,
+            reviewedBy, reviewedAt, createdAt
         FROM
             Application
         WHERE
@@ -95,7 +136,7 @@ func (m *Mysql) FindAllApplication(status models.ApplicationStatus) ([]response.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT id, applicantId, status, createdAt FROM Application"
+	query := "SELECT id, applicantId, job, status, createdAt FROM Application"
 
 	switch status {
 	case models.APPLICATION_STATUS_PENDING:
@@ -119,7 +160,36 @@ func (m *Mysql) FindAllApplication(status models.ApplicationStatus) ([]response.
 	return applications, nil
 }
 
-func (m *Mysql) ApproveApplication(id int) error {
+func (m *Mysql) FindApplicationsByApplicant(applicantId int) ([]models.ApplicationModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, applicantId, job, status, latitude, longitude,
+            COALESCE(rejectionReason, '') AS rejectionReason,
+            reviewedAt, createdAt
+        FROM
+            Application
+        WHERE
+            applicantId = ?
+        ORDER BY
+            createdAt DESC, id DESC
+    `
+
+	applications := make([]models.ApplicationModel, 0)
+	if err := m.Conn.SelectContext(ctx, &applications, query, applicantId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return applications, nil
+}
+
+func (m *Mysql) ApproveApplication(review *request.ApplicationReview) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
@@ -128,9 +198,19 @@ func (m *Mysql) ApproveApplication(id int) error {
 		return err
 	}
 
-	updateStatus := "UPDATE Application SET status = 'approved' WHERE id = ?"
+	updateStatus := `
+        UPDATE
+            Application
+        SET
+            status = 'approved',
+            reviewedBy = ?,
+            reviewedAt = CURRENT_TIMESTAMP
+        WHERE
+            id = ? AND status = 'pending'
+    `
 
-	if _, err := tx.ExecContext(ctx, updateStatus, id); err != nil {
+	res, err := tx.ExecContext(ctx, updateStatus, review.ReviewedBy, review.Id)
+	if err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
 			return errors.New("Failed to approve application and rollback transaction")
@@ -139,6 +219,15 @@ func (m *Mysql) ApproveApplication(id int) error {
 		return err
 	}
 
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return errors.New("Failed to approve application and rollback transaction")
+		}
+
+		return errors.New("Application not found or already reviewed")
+	}
+
 	promoteVendor := `
         INSERT INTO
             Vendor (vendorId, job)
@@ -149,7 +238,7 @@ func (m *Mysql) ApproveApplication(id int) error {
             )
     `
 
-	if _, err := tx.ExecContext(ctx, promoteVendor, id, id); err != nil {
+	if _, err := tx.ExecContext(ctx, promoteVendor, review.Id, review.Id); err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
 			return errors.New("Failed to promote applicant to vendor and rollback transaction")
@@ -158,6 +247,25 @@ func (m *Mysql) ApproveApplication(id int) error {
 		return err
 	}
 
+	event := models.ApplicationEvent{ApplicationId: review.Id}
+	if err := tx.QueryRowxContext(ctx, "SELECT applicantId FROM Application WHERE id = ?", review.Id).Scan(&event.ApplicantId); err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return errors.New("Failed to find applicant and rollback transaction")
+		}
+
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_APPLICATION_APPROVED, review.Id, event); err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return errors.New("Failed to record event and rollback transaction")
+		}
+
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		rollbackErr := tx.Rollback()
 		if rollbackErr != nil {
@@ -174,14 +282,62 @@ func (m *Mysql) ApproveApplication(id int) error {
 	return nil
 }
 
-func (m *Mysql) RejectApplication(id int) error {
+func (m *Mysql) RejectApplication(review *request.ApplicationReview) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "UPDATE Application SET status = 'rejected' WHERE id = ?"
+	query := `
+        UPDATE
+            Application
+        SET
+            status = 'rejected',
+            rejectionReason = :reason,
+            reviewedBy = :reviewedBy,
+            reviewedAt = CURRENT_TIMESTAMP
+        WHERE
+            id = :id AND status = 'pending'
+    `
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
 
-	_, err := m.Conn.ExecContext(ctx, query, id)
+	res, err := tx.NamedExecContext(ctx, query, review)
 	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return errors.New("Application not found or already reviewed")
+	}
+
+	event := models.ApplicationEvent{ApplicationId: review.Id, Reason: review.Reason}
+	if err := tx.QueryRowxContext(ctx, "SELECT applicantId FROM Application WHERE id = ?", review.Id).Scan(&event.ApplicantId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_APPLICATION_REJECTED, review.Id, event); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
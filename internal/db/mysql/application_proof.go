@@ -33,3 +33,21 @@ func (m *Mysql) NewApplicationProof(data *models.ApplicationProofModel) (int, er
 
 	return int(id), nil
 }
+
+func (m *Mysql) FindApplicationProofs(applicationId int) ([]models.ApplicationProofModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "SELECT id, applicationId, applicantId, url, createdAt FROM ApplicationProof WHERE applicationId = ?"
+
+	proofs := make([]models.ApplicationProofModel, 0)
+	if err := m.Conn.SelectContext(ctx, &proofs, query, applicationId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return proofs, nil
+}
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproveApplication(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Application").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Vendor").WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT applicantId FROM Application WHERE id = ?").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"applicantId"}).AddRow(3))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_APPLICATION_APPROVED, 1, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := db.ApproveApplication(&request.ApplicationReview{Id: 1, ReviewedBy: 2})
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestApproveApplicationAlreadyReviewed(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Application").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := db.ApproveApplication(&request.ApplicationReview{Id: 1, ReviewedBy: 2})
+
+	assert.Error(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCreateApplication(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT id FROM User WHERE id = \\? FOR UPDATE").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS(.+)FROM Application").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(false))
+	mock.ExpectExec("INSERT INTO(.+)Application").WithArgs(3, "Plumber", 7.07, 125.6).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectCommit()
+
+	id, created, err := db.CreateApplication(&request.NewApplication{ApplicantId: 3, Job: "Plumber", GeoSpatialModel: models.GeoSpatialModel{Latitude: 7.07, Longitude: 125.6}})
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 4, id)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCreateApplicationWhileOneIsActive(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT id FROM User WHERE id = \\? FOR UPDATE").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS(.+)FROM Application").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(true))
+	mock.ExpectRollback()
+
+	_, created, err := db.CreateApplication(&request.NewApplication{ApplicantId: 3, Job: "Plumber", GeoSpatialModel: models.GeoSpatialModel{Latitude: 7.07, Longitude: 125.6}})
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -8,33 +8,84 @@ import (
 	"time"
 )
 
-func (m *Mysql) CountSystemComplaint() (int, error) {
+func (m *Mysql) CountSystemComplaintByStatus() ([]response.ComplaintStatusCount, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT COUNT(*) FROM SystemComplaint"
+	query := "SELECT status, COUNT(*) AS count FROM SystemComplaint GROUP BY status"
 
-	count := 0
-	err := m.Conn.GetContext(ctx, &count, query)
-	if err != nil {
-		return 0, err
+	counts := make([]response.ComplaintStatusCount, 0)
+	if err := m.Conn.SelectContext(ctx, &counts, query); err != nil {
+		return nil, err
 	}
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return 0, context.DeadlineExceeded
+		return nil, context.DeadlineExceeded
+	}
+
+	return counts, nil
+}
+
+func (m *Mysql) FindAllSystemComplaints(filter *request.SystemComplaintFilter) ([]*response.SystemComplaint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, reporterId, title, status, priority, assignedTo, createdAt
+        FROM
+            SystemComplaint
+        WHERE
+            1 = 1
+    `
+
+	args := make([]interface{}, 0)
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+
+	if filter.Priority != "" {
+		query += " AND priority = ?"
+		args = append(args, filter.Priority)
+	}
+
+	if filter.AssignedTo != 0 {
+		query += " AND assignedTo = ?"
+		args = append(args, filter.AssignedTo)
+	}
+
+	query += " ORDER BY FIELD(priority, 'urgent', 'high', 'medium', 'low'), createdAt"
+
+	complaints := make([]*response.SystemComplaint, 0)
+	if err := m.Conn.SelectContext(ctx, &complaints, query, args...); err != nil {
+		return nil, err
 	}
 
-	return count, nil
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return complaints, nil
 }
 
-func (m *Mysql) FindAllSystemComplaints() ([]*response.SystemComplaint, error) {
+func (m *Mysql) FindSystemComplaintsByReporter(userId int) ([]*response.SystemComplaint, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT id, title FROM SystemComplaint"
+	query := `
+        SELECT
+            id, reporterId, title, status, priority, createdAt
+        FROM
+            SystemComplaint
+        WHERE
+            reporterId = ?
+        ORDER BY
+            createdAt DESC
+    `
 
 	complaints := make([]*response.SystemComplaint, 0)
-	if err := m.Conn.SelectContext(ctx, &complaints, query); err != nil {
+	if err := m.Conn.SelectContext(ctx, &complaints, query, userId); err != nil {
 		return nil, err
 	}
 
@@ -49,7 +100,14 @@ func (m *Mysql) FindSystemComplaintById(id int) (*models.SystemComplaintModel, e
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT * FROM SystemComplaint WHERE id = ?"
+	query := `
+        SELECT
+            id, reporterId, title, detail, status, priority, assignedTo, createdAt, updatedAt
+        FROM
+            SystemComplaint
+        WHERE
+            id = ?
+    `
 
 	complaint := &models.SystemComplaintModel{}
 	if err := m.Conn.GetContext(ctx, complaint, query, id); err != nil {
@@ -63,18 +121,44 @@ func (m *Mysql) FindSystemComplaintById(id int) (*models.SystemComplaintModel, e
 	return complaint, nil
 }
 
-func (m *Mysql) FileVendorComplaint(complaint *request.NewComplaint) (int, error) {
+func (m *Mysql) TriageSystemComplaint(triage *request.TriageSystemComplaint) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
 	query := `
-        INSERT INTO 
-            Complaint (vendorId, code, title, content)
+        UPDATE
+            SystemComplaint
+        SET
+            status = :status,
+            priority = :priority,
+            assignedTo = :assignedTo
+        WHERE
+            id = :id
+    `
+
+	if _, err := m.Conn.NamedExecContext(ctx, query, triage); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) NewSystemComplaintReply(model *models.SystemComplaintReplyModel) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            SystemComplaintReply (complaintId, parentId, authorId, authorRole, content)
         VALUES
-            (:vendorId, :code, :title, :content)
+            (:complaintId, :parentId, :authorId, :authorRole, :content)
     `
 
-	res, err := m.Conn.NamedExecContext(ctx, query, complaint)
+	res, err := m.Conn.NamedExecContext(ctx, query, model)
 	if err != nil {
 		return 0, err
 	}
@@ -91,16 +175,42 @@ func (m *Mysql) FileVendorComplaint(complaint *request.NewComplaint) (int, error
 	return int(id), nil
 }
 
+func (m *Mysql) FindSystemComplaintReplies(complaintId int) ([]models.SystemComplaintReplyModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, complaintId, parentId, authorId, authorRole, content, createdAt, updatedAt
+        FROM
+            SystemComplaintReply
+        WHERE
+            complaintId = ?
+        ORDER BY
+            createdAt, id
+    `
+
+	replies := make([]models.SystemComplaintReplyModel, 0)
+	if err := m.Conn.SelectContext(ctx, &replies, query, complaintId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return replies, nil
+}
+
 func (m *Mysql) FileSystemComplaint(complaint *request.SystemComplaint) (int, error) {
-	// TODO: Implement this function
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
 	query := `
         INSERT INTO 
-            SystemComplaint (title, detail)
+            SystemComplaint (reporterId, title, detail, priority)
         VALUES
-            (:title, :detail)
+            (:reporterId, :title, :detail, :priority)
     `
 
 	res, err := m.Conn.NamedExecContext(ctx, query, complaint)
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountSystemComplaintByStatus(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"status", "count"}).
+		AddRow(models.SYSTEM_COMPLAINT_STATUS_OPEN, 4).
+		AddRow(models.SYSTEM_COMPLAINT_STATUS_RESOLVED, 1)
+
+	query := "SELECT status, COUNT\\(\\*\\) AS count FROM SystemComplaint GROUP BY status"
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	counts, err := db.CountSystemComplaintByStatus()
+
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	assert.Equal(t, models.SYSTEM_COMPLAINT_STATUS_OPEN, counts[0].Status)
+	assert.Equal(t, 4, counts[0].Count)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,272 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"time"
+)
+
+// Selects the columns of models.ExportColumns in the same order. Doubles are
+// cast since they would otherwise be formatted with an exponent
+func exportQuery(filter *request.ExportFilter) (string, []interface{}, error) {
+	args := make([]interface{}, 0)
+
+	switch filter.List {
+	case models.EXPORT_LIST_USERS:
+		return "SELECT id, name, email, imageUrl, createdAt FROM User ORDER BY id", args, nil
+
+	case models.EXPORT_LIST_VENDORS:
+		query := `
+            SELECT
+                v.id, v.vendorId, u.name, v.job, v.rating,
+                IF(v.restricted, 'yes', 'no'), v.createdAt
+            FROM
+                Vendor v
+                JOIN User u ON u.id = v.vendorId
+        `
+
+		switch models.VendorStatus(filter.Status) {
+		case models.VENDOR_STATUS_RESTRICTED:
+			query += " WHERE v.restricted = 1"
+		case models.VENDOR_STATUS_UNRESTRICTED:
+			query += " WHERE v.restricted = 0"
+		}
+
+		return query + " ORDER BY v.id", args, nil
+
+	case models.EXPORT_LIST_APPLICATIONS:
+		query := `
+            SELECT
+                a.id, a.applicantId, u.name, a.job,
+                CAST(a.latitude AS CHAR), CAST(a.longitude AS CHAR),
+                a.status, a.rejectionReason, a.reviewedBy, a.reviewedAt, a.createdAt
+            FROM
+                Application a
+                JOIN User u ON u.id = a.applicantId
+        `
+
+		switch status := models.ApplicationStatus(filter.Status); status {
+		case models.APPLICATION_STATUS_PENDING, models.APPLICATION_STATUS_APPROVED, models.APPLICATION_STATUS_REJECTED:
+			query += " WHERE a.status = ?"
+			args = append(args, status)
+		}
+
+		return query + " ORDER BY a.id", args, nil
+
+	case models.EXPORT_LIST_TRANSACTIONS:
+		query := `
+            SELECT
+                t.id, t.serviceId, t.clientId, c.name, t.vendorId, v.name, t.status,
+                t.start, t.end, CAST(t.price AS CHAR), t.currency, t.createdAt, t.completedAt
+            FROM
+                Transaction t
+                LEFT JOIN User c ON c.id = t.clientId
+                LEFT JOIN User v ON v.id = t.vendorId
+        `
+
+		switch status := models.TransactionStatus(filter.Status); status {
+		case models.TRANSACTION_STATUS_ONGOING, models.TRANSACTION_STATUS_DONE, models.TRANSACTION_STATUS_CANCELLED:
+			query += " WHERE t.status = ?"
+			args = append(args, status)
+		}
+
+		return query + " ORDER BY t.id", args, nil
+
+	case models.EXPORT_LIST_SYSTEM_COMPLAINTS:
+		query := `
+            SELECT
+                id, reporterId, title, detail, status, priority, assignedTo, createdAt
+            FROM
+                SystemComplaint
+            WHERE
+                1 = 1
+        `
+
+		if filter.Status != "" {
+			query += " AND status = ?"
+			args = append(args, filter.Status)
+		}
+
+		if filter.Priority != "" {
+			query += " AND priority = ?"
+			args = append(args, filter.Priority)
+		}
+
+		if filter.AssignedTo != 0 {
+			query += " AND assignedTo = ?"
+			args = append(args, filter.AssignedTo)
+		}
+
+		return query + " ORDER BY FIELD(priority, 'urgent', 'high', 'medium', 'low'), createdAt", args, nil
+
+	case models.EXPORT_LIST_VENDOR_COMPLAINTS:
+		query := `
+            SELECT
+                id, complainantId, vendorId, transactionId, title, content, status,
+                resolution, resolutionNote, assignedTo, resolvedAt, createdAt
+            FROM
+                VendorComplaint
+        `
+
+		if status := models.VendorComplaintStatus(filter.Status); status != "" && status != models.VENDOR_COMPLAINT_STATUS_ALL {
+			query += " WHERE status = ?"
+			args = append(args, status)
+		}
+
+		return query + " ORDER BY createdAt", args, nil
+
+	case models.EXPORT_LIST_VERIFICATIONS:
+		query := `
+            SELECT
+                id, user, name, address, idType, idNumber, status,
+                reason, reviewedBy, reviewedAt, createdAt
+            FROM
+                IdentityVerification
+        `
+
+		if status := models.VerificationStatus(filter.Status); status != models.VERIFICATION_STATUS_ALL {
+			query += " WHERE status = ?"
+			args = append(args, status)
+		}
+
+		return query + " ORDER BY id", args, nil
+	}
+
+	return "", nil, errors.New("unknown export list")
+}
+
+// Hands every row to each as soon as it is read, missing values become empty
+// cells. Returning an error from each stops the export
+func (m *Mysql) StreamExport(filter *request.ExportFilter, each func(record []string) error) error {
+	// Rows are read while the file is sent, so the query stays open for as
+	// long as the download takes
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*10)
+	defer cancel()
+
+	query, args, err := exportQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns := len(models.ExportColumns[filter.List])
+	values := make([]sql.NullString, columns)
+	targets := make([]interface{}, columns)
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+
+		record := make([]string, columns)
+		for i, value := range values {
+			record[i] = value.String
+		}
+
+		if err := each(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) NewExportAudit(audit *models.ExportAuditModel) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            ExportAudit (adminId, list, format, filters, decrypted)
+        VALUES
+            (:adminId, :list, :format, :filters, :decrypted)
+    `
+
+	res, err := m.Conn.NamedExecContext(ctx, query, audit)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) FinishExportAudit(audit *models.ExportAuditModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        UPDATE
+            ExportAudit
+        SET
+            status = :status,
+            rowCount = :rowCount,
+            error = NULLIF(:error, ''),
+            completedAt = CURRENT_TIMESTAMP
+        WHERE
+            id = :id
+    `
+
+	if _, err := m.Conn.NamedExecContext(ctx, query, audit); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) FindExportAudits() ([]models.ExportAuditModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, adminId, list, format, filters, decrypted, status, rowCount,
+            COALESCE(error, '') AS error, completedAt, createdAt
+        FROM
+            ExportAudit
+        ORDER BY
+            createdAt DESC, id DESC
+    `
+
+	audits := make([]models.ExportAuditModel, 0)
+	if err := m.Conn.SelectContext(ctx, &audits, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return audits, nil
+}
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"errors"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportQueriesMatchColumns(t *testing.T) {
+	selected := regexp.MustCompile(`(?s)SELECT(.+?)FROM`)
+
+	for list, columns := range models.ExportColumns {
+		query, _, err := exportQuery(&request.ExportFilter{List: list})
+		assert.NoError(t, err)
+
+		// Selected expressions are separated by commas outside of parentheses
+		expressions, depth := 1, 0
+		for _, char := range selected.FindStringSubmatch(query)[1] {
+			switch char {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			case ',':
+				if depth == 0 {
+					expressions++
+				}
+			}
+		}
+
+		assert.Equal(t, len(columns), expressions, "columns of %s", list)
+	}
+}
+
+func TestExportQueryFilters(t *testing.T) {
+	query, args, err := exportQuery(&request.ExportFilter{List: models.EXPORT_LIST_TRANSACTIONS, Status: "done"})
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(query, "WHERE t.status = ?"))
+	assert.Equal(t, []interface{}{models.TRANSACTION_STATUS_DONE}, args)
+
+	// Unknown statuses are ignored like on the list endpoint
+	query, args, err = exportQuery(&request.ExportFilter{List: models.EXPORT_LIST_APPLICATIONS, Status: "all"})
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(query, "WHERE"))
+	assert.Empty(t, args)
+
+	_, args, err = exportQuery(&request.ExportFilter{List: models.EXPORT_LIST_SYSTEM_COMPLAINTS, Status: "open", Priority: "urgent", AssignedTo: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"open", models.ComplaintPriority("urgent"), 3}, args)
+
+	_, _, err = exportQuery(&request.ExportFilter{List: "services"})
+	assert.Error(t, err)
+}
+
+func TestStreamExport(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "imageUrl", "createdAt"}).
+		AddRow(1, "6e61", "656d", nil, "2026-10-18 09:00:00").
+		AddRow(2, "6e62", "656e", "/resource/2.png", "2026-10-18 10:00:00")
+	mock.ExpectQuery("SELECT id, name, email, imageUrl, createdAt FROM User").WillReturnRows(rows)
+
+	records := make([][]string, 0)
+	err := db.StreamExport(&request.ExportFilter{List: models.EXPORT_LIST_USERS}, func(record []string) error {
+		records = append(records, record)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"1", "6e61", "656d", "", "2026-10-18 09:00:00"},
+		{"2", "6e62", "656e", "/resource/2.png", "2026-10-18 10:00:00"},
+	}, records)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestStreamExportStopsOnError(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "imageUrl", "createdAt"}).
+		AddRow(1, "6e61", "656d", nil, "2026-10-18 09:00:00").
+		AddRow(2, "6e62", "656e", nil, "2026-10-18 10:00:00")
+	mock.ExpectQuery("FROM User").WillReturnRows(rows)
+
+	calls := 0
+	err := db.StreamExport(&request.ExportFilter{List: models.EXPORT_LIST_USERS}, func(record []string) error {
+		calls++
+		return errors.New("client went away")
+	})
+
+	assert.EqualError(t, err, "client went away")
+	assert.Equal(t, 1, calls)
+}
+
+func TestFinishExportAudit(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("UPDATE(.+)ExportAudit(.+)SET").
+		WithArgs(models.EXPORT_STATUS_FAILED, 40, "client went away", 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	audit := &models.ExportAuditModel{Status: models.EXPORT_STATUS_FAILED, Rows: 40, Error: "client went away"}
+	audit.Id = 7
+
+	assert.NoError(t, db.FinishExportAudit(audit))
+
+	err := mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -4,20 +4,53 @@ import (
 	"context"
 	"nearbyassist/internal/models"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 func (m *Mysql) NewMessage(message models.MessageModel) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := insertMessage(ctx, tx, &message)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return -1, err
+		}
+
+		return -1, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return -1, context.DeadlineExceeded
+	}
+
+	return id, nil
+}
+
+// Stores the message and its MessageSent event inside the caller's transaction
+func insertMessage(ctx context.Context, tx *sqlx.Tx, message *models.MessageModel) (int, error) {
+	if message.Type == "" {
+		message.Type = models.MESSAGE_TYPE_TEXT
+	}
+
 	query := `
         INSERT INTO
-            Message (sender, receiver, content)
+            Message (sender, receiver, content, type, quoteId)
         VALUES
-            (:sender, :receiver, :content)
+            (:sender, :receiver, :content, :type, :quoteId)
     `
 
-	res, err := m.Conn.NamedExecContext(ctx, query, message)
+	res, err := tx.NamedExecContext(ctx, query, message)
 	if err != nil {
 		return -1, err
 	}
@@ -27,8 +60,14 @@ func (m *Mysql) NewMessage(message models.MessageModel) (int, error) {
 		return -1, err
 	}
 
-	if ctx.Err() == context.DeadlineExceeded {
-		return -1, context.DeadlineExceeded
+	event := models.MessageEvent{
+		MessageId: int(id),
+		Sender:    message.Sender,
+		Receiver:  message.Receiver,
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_MESSAGE_SENT, int(id), event); err != nil {
+		return -1, err
 	}
 
 	return int(id), nil
@@ -40,7 +79,7 @@ func (m *Mysql) GetMessages(senderId, receiverId int) ([]models.MessageModel, er
 
 	query := `
         SELECT
-            id, sender, receiver, content, createdAt
+            id, sender, receiver, content, type, quoteId, createdAt
         FROM
             Message
         WHERE
@@ -1,12 +1,13 @@
 package mysql
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"nearbyassist/internal/config"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -41,3 +42,11 @@ func NewMysqlWithDb(db *sqlx.DB) *Mysql {
 		Conn: db,
 	}
 }
+
+// ER_DUP_ENTRY, raised when an insert collides with a unique key
+const mysqlDuplicateEntry = 1062
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *driver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
@@ -0,0 +1,197 @@
+package mysql
+
+import (
+	"context"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"time"
+)
+
+func (m *Mysql) NewNotification(notification *models.NotificationModel) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            Notification (userId, type, payload)
+        VALUES
+            (:userId, :type, :payload)
+    `
+
+	res, err := m.Conn.NamedExecContext(ctx, query, notification)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) FindNotificationById(id int) (*models.NotificationModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, userId, type, payload, readAt, createdAt FROM Notification WHERE id = ?"
+
+	notification := &models.NotificationModel{}
+	if err := m.Conn.GetContext(ctx, notification, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return notification, nil
+}
+
+func (m *Mysql) FindNotificationsByUser(userId int, unreadOnly bool) ([]models.NotificationModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, userId, type, payload, readAt, createdAt FROM Notification WHERE userId = ?"
+
+	if unreadOnly {
+		query += " AND readAt IS NULL"
+	}
+
+	query += " ORDER BY createdAt DESC, id DESC LIMIT 100"
+
+	notifications := make([]models.NotificationModel, 0)
+	if err := m.Conn.SelectContext(ctx, &notifications, query, userId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return notifications, nil
+}
+
+func (m *Mysql) CountUnreadNotifications(userId int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT COUNT(*) FROM Notification WHERE userId = ? AND readAt IS NULL"
+
+	count := 0
+	if err := m.Conn.GetContext(ctx, &count, query, userId); err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return count, nil
+}
+
+func (m *Mysql) MarkNotificationRead(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "UPDATE Notification SET readAt = CURRENT_TIMESTAMP WHERE id = ? AND readAt IS NULL"
+
+	if _, err := m.Conn.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) MarkAllNotificationsRead(userId int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "UPDATE Notification SET readAt = CURRENT_TIMESTAMP WHERE userId = ? AND readAt IS NULL"
+
+	res, err := m.Conn.ExecContext(ctx, query, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(affected), nil
+}
+
+// Registers the token for the user, a token that moved to another account is reassigned
+func (m *Mysql) SaveDeviceToken(token *request.DeviceToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            DeviceToken (userId, token, platform)
+        VALUES
+            (:userId, :token, :platform)
+        ON DUPLICATE KEY UPDATE
+            userId = VALUES(userId),
+            platform = VALUES(platform)
+    `
+
+	if _, err := m.Conn.NamedExecContext(ctx, query, token); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) DeleteDeviceToken(userId int, token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "DELETE FROM DeviceToken WHERE userId = ? AND token = ?"
+
+	if _, err := m.Conn.ExecContext(ctx, query, userId, token); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) FindDeviceTokens(userId int) ([]models.DeviceTokenModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, userId, token, platform, createdAt FROM DeviceToken WHERE userId = ?"
+
+	tokens := make([]models.DeviceTokenModel, 0)
+	if err := m.Conn.SelectContext(ctx, &tokens, query, userId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return tokens, nil
+}
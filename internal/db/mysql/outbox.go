@@ -0,0 +1,168 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"nearbyassist/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Writes a domain event as part of the caller's transaction so that the event
+// is only published when the state change it describes is committed
+func insertOutboxEvent(ctx context.Context, tx *sqlx.Tx, eventType models.EventType, aggregateId int, payload any) error {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO Outbox (eventType, aggregateId, payload) VALUES (?, ?, ?)"
+
+	_, err = tx.ExecContext(ctx, query, eventType, aggregateId, bytes)
+	return err
+}
+
+// Claims a batch of pending events. Rows held by another dispatcher are
+// skipped, and the claimed ones are pushed back by the lease so they are not
+// picked up again while they are being published
+func (m *Mysql) FindPendingOutboxEvents(limit, maxAttempts int, lease time.Duration) ([]models.OutboxEventModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT
+            id, eventType, aggregateId, payload, attempts, createdAt
+        FROM
+            Outbox
+        WHERE
+            dispatchedAt IS NULL AND availableAt <= CURRENT_TIMESTAMP AND attempts < ?
+        ORDER BY
+            id ASC
+        LIMIT ?
+        FOR UPDATE SKIP LOCKED
+    `
+
+	events := make([]models.OutboxEventModel, 0)
+	if err := tx.SelectContext(ctx, &events, query, maxAttempts, limit); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	if len(events) > 0 {
+		ids := make([]int, len(events))
+		for i, event := range events {
+			ids[i] = event.Id
+		}
+
+		claim, args, err := sqlx.In("UPDATE Outbox SET availableAt = DATE_ADD(CURRENT_TIMESTAMP, INTERVAL ? SECOND) WHERE id IN (?)", int(lease.Seconds()), ids)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, tx.Rebind(claim), args...)
+		}
+
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return nil, err
+			}
+
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return events, nil
+}
+
+func (m *Mysql) FindOutboxDeliveries(outboxId int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT subscriber FROM OutboxDelivery WHERE outboxId = ?"
+
+	subscribers := make([]string, 0)
+	if err := m.Conn.SelectContext(ctx, &subscribers, query, outboxId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return subscribers, nil
+}
+
+func (m *Mysql) MarkOutboxDelivered(outboxId int, subscriber string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "INSERT IGNORE INTO OutboxDelivery (outboxId, subscriber) VALUES (?, ?)"
+
+	if _, err := m.Conn.ExecContext(ctx, query, outboxId, subscriber); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) MarkOutboxEventDispatched(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "UPDATE Outbox SET dispatchedAt = CURRENT_TIMESTAMP, attempts = attempts + 1 WHERE id = ?"
+
+	if _, err := m.Conn.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Records the failure and pushes the event back by the given delay
+func (m *Mysql) MarkOutboxEventFailed(id int, reason string, retryIn time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        UPDATE
+            Outbox
+        SET
+            attempts = attempts + 1,
+            lastError = ?,
+            availableAt = DATE_ADD(CURRENT_TIMESTAMP, INTERVAL ? SECOND)
+        WHERE
+            id = ?
+    `
+
+	if _, err := m.Conn.ExecContext(ctx, query, reason, int(retryIn.Seconds()), id); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
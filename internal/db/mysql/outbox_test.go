@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPendingOutboxEvents(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "eventType", "aggregateId", "payload", "attempts", "createdAt"}).
+		AddRow(1, "ReviewPosted", 4, []byte(`{"reviewId":4}`), 0, "2026-10-18 10:00:00")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM Outbox(.+)FOR UPDATE SKIP LOCKED").WithArgs(10, 50).WillReturnRows(rows)
+	mock.ExpectExec("UPDATE Outbox SET availableAt = (.+) WHERE id IN \\(\\?\\)").WithArgs(60, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	events, err := db.FindPendingOutboxEvents(50, 10, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 4, events[0].AggregateId)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestMarkOutboxEventFailed(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("UPDATE Outbox").WithArgs("boom", 8, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := db.MarkOutboxEventFailed(1, "boom", time.Second*8)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestMarkOutboxDelivered(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("INSERT IGNORE INTO OutboxDelivery").WithArgs(1, "rating").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := db.MarkOutboxDelivered(1, "rating")
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
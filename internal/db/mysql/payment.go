@@ -0,0 +1,474 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nearbyassist/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Reserved payments have no reference until the provider created the charge
+const paymentColumns = "id, transactionId, clientId, vendorId, provider, COALESCE(reference, '') AS reference, amount, currency, status, createdAt, updatedAt"
+
+// Records the payment before the provider is called, the unique transaction
+// id makes sure a booking is only ever charged once. Returns false when the
+// transaction already has a payment
+func (m *Mysql) ReservePayment(payment *models.PaymentModel) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            Payment (transactionId, clientId, vendorId, provider, amount, currency, status)
+        VALUES
+            (:transactionId, :clientId, :vendorId, :provider, :amount, :currency, 'pending')
+    `
+
+	res, err := m.Conn.NamedExecContext(ctx, query, payment)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, context.DeadlineExceeded
+	}
+
+	payment.Id = int(id)
+
+	return true, nil
+}
+
+// Attaches the provider's charge to a reserved payment. Captured payments are
+// moved into escrow in the same transaction. Cancelling locks the same
+// transaction row, a booking cancelled while the provider was charging it
+// returns models.ErrTransactionNotOngoing and the charge has to be given back
+func (m *Mysql) ConfirmPayment(payment *models.PaymentModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var status models.TransactionStatus
+	err = tx.QueryRowxContext(ctx, "SELECT status FROM Transaction WHERE id = ? FOR UPDATE", payment.TransactionId).Scan(&status)
+	if err == nil && status != models.TRANSACTION_STATUS_ONGOING {
+		err = models.ErrTransactionNotOngoing
+	}
+
+	var res sql.Result
+	if err == nil {
+		query := "UPDATE Payment SET reference = ?, status = 'authorized' WHERE id = ? AND status = 'pending'"
+		res, err = tx.ExecContext(ctx, query, payment.Reference, payment.Id)
+	}
+
+	if err == nil {
+		if rows, rowsErr := res.RowsAffected(); rowsErr != nil {
+			err = rowsErr
+		} else if rows == 0 {
+			err = errors.New("payment is not pending")
+		}
+	}
+
+	if err == nil && payment.Status == models.PAYMENT_STATUS_CAPTURED {
+		err = capturePayment(ctx, tx, payment)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Frees the transaction for another attempt once the provider turned the
+// charge down or it was voided
+func (m *Mysql) DeletePendingPayment(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if _, err := m.Conn.ExecContext(ctx, "DELETE FROM Payment WHERE id = ? AND status = 'pending'", id); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) FindPaymentByTransaction(transactionId int) (*models.PaymentModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT " + paymentColumns + " FROM Payment WHERE transactionId = ?"
+
+	payment := &models.PaymentModel{}
+	if err := m.Conn.GetContext(ctx, payment, query, transactionId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return payment, nil
+}
+
+func (m *Mysql) FindPaymentEntries(paymentId int) ([]models.LedgerEntryModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            e.id, e.journalId, j.type AS journalType, e.account, e.userId, e.debit, e.credit, e.currency, e.createdAt
+        FROM
+            LedgerEntry e
+        JOIN
+            LedgerJournal j ON j.id = e.journalId
+        WHERE
+            j.paymentId = ?
+        ORDER BY
+            e.id
+    `
+
+	entries := make([]models.LedgerEntryModel, 0)
+	if err := m.Conn.SelectContext(ctx, &entries, query, paymentId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return entries, nil
+}
+
+// Returns false when the provider already delivered this event. The event is
+// recorded together with its effect, so a failed apply is retried in full
+func (m *Mysql) ApplyPaymentWebhook(event *models.PaymentWebhookEventModel) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+        INSERT IGNORE INTO
+            PaymentWebhookEvent (provider, eventId, type, reference, amount, payload)
+        VALUES
+            (:provider, :eventId, :type, :reference, :amount, :payload)
+    `
+
+	res, err := tx.NamedExecContext(ctx, query, event)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return false, err
+		}
+
+		return false, err
+	}
+
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		if err := tx.Rollback(); err != nil {
+			return false, err
+		}
+
+		return false, err
+	}
+
+	// The booking is locked before its payment, in the order completing and
+	// cancelling take them. The payment may not be committed yet, the
+	// provider retries on error
+	var status models.TransactionStatus
+	findStatus := `
+        SELECT
+            t.status
+        FROM
+            Transaction t
+        JOIN
+            Payment p ON p.transactionId = t.id
+        WHERE
+            p.provider = ? AND p.reference = ?
+        FOR UPDATE OF t
+    `
+
+	err = tx.GetContext(ctx, &status, findStatus, event.Provider, event.Reference)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = errors.New("payment not found")
+	}
+
+	var payment *models.PaymentModel
+	if err == nil {
+		payment, err = lockPayment(ctx, tx, "provider = ? AND reference = ?", event.Provider, event.Reference)
+	}
+
+	if err == nil && payment == nil {
+		err = errors.New("payment not found")
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return false, err
+		}
+
+		return false, err
+	}
+
+	// Events that no longer apply to the payment are recorded and ignored,
+	// reconciliation reports any difference with the provider
+	switch {
+	// A booking completed while the hold was still open never released its
+	// escrow, it is released as soon as the money arrives
+	case event.Type == models.PAYMENT_EVENT_CAPTURED && payment.Status == models.PAYMENT_STATUS_AUTHORIZED:
+		err = capturePayment(ctx, tx, payment)
+		if err == nil && status == models.TRANSACTION_STATUS_DONE {
+			err = releasePayment(ctx, tx, payment)
+		}
+
+	case event.Type == models.PAYMENT_EVENT_REFUNDED && (payment.Status == models.PAYMENT_STATUS_AUTHORIZED || payment.Status == models.PAYMENT_STATUS_CAPTURED):
+		// Whatever the provider did not give back is kept like a penalty
+		err = refundPayment(ctx, tx, payment, payment.Amount-event.Amount)
+
+	case event.Type == models.PAYMENT_EVENT_FAILED && payment.Status == models.PAYMENT_STATUS_AUTHORIZED:
+		_, err = tx.ExecContext(ctx, "UPDATE Payment SET status = 'failed' WHERE id = ?", payment.Id)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return false, err
+		}
+
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, context.DeadlineExceeded
+	}
+
+	return true, nil
+}
+
+func (m *Mysql) FindLedgerBalances() ([]models.LedgerBalanceModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            account, currency, SUM(debit) AS debit, SUM(credit) AS credit, SUM(debit) - SUM(credit) AS balance
+        FROM
+            LedgerEntry
+        GROUP BY
+            account, currency
+        ORDER BY
+            account, currency
+    `
+
+	balances := make([]models.LedgerBalanceModel, 0)
+	if err := m.Conn.SelectContext(ctx, &balances, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return balances, nil
+}
+
+// Money in and out of the provider clearing account, per payment
+func (m *Mysql) FindPaymentLedgerTotals(provider string) ([]models.PaymentLedgerTotalModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            p.id AS paymentId, p.transactionId, COALESCE(p.reference, '') AS reference, p.currency, p.status,
+            COALESCE(SUM(e.debit), 0) AS captured,
+            COALESCE(SUM(e.credit), 0) AS refunded
+        FROM
+            Payment p
+        LEFT JOIN
+            LedgerJournal j ON j.paymentId = p.id
+        LEFT JOIN
+            LedgerEntry e ON e.journalId = j.id AND e.account = 'provider_clearing'
+        WHERE
+            p.provider = ?
+        GROUP BY
+            p.id, p.transactionId, p.reference, p.currency, p.status
+        ORDER BY
+            p.id
+    `
+
+	totals := make([]models.PaymentLedgerTotalModel, 0)
+	if err := m.Conn.SelectContext(ctx, &totals, query, provider); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return totals, nil
+}
+
+func (m *Mysql) FindUnbalancedJournals() ([]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT journalId FROM LedgerEntry GROUP BY journalId HAVING SUM(debit) <> SUM(credit) ORDER BY journalId"
+
+	journals := make([]int, 0)
+	if err := m.Conn.SelectContext(ctx, &journals, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return journals, nil
+}
+
+// Returns nil without an error when there is no matching payment
+func lockPayment(ctx context.Context, tx *sqlx.Tx, condition string, args ...any) (*models.PaymentModel, error) {
+	payment := &models.PaymentModel{}
+	if err := tx.GetContext(ctx, payment, "SELECT "+paymentColumns+" FROM Payment WHERE "+condition+" FOR UPDATE", args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// The client's money is now held by the provider on the client's behalf
+func capturePayment(ctx context.Context, tx *sqlx.Tx, payment *models.PaymentModel) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE Payment SET status = 'captured' WHERE id = ?", payment.Id); err != nil {
+		return err
+	}
+
+	return recordJournal(ctx, tx, payment, models.JOURNAL_CHARGE, []models.LedgerEntryModel{
+		{Account: models.LEDGER_ACCOUNT_PROVIDER_CLEARING, Debit: payment.Amount},
+		{Account: models.LEDGER_ACCOUNT_ESCROW, UserId: &payment.ClientId, Credit: payment.Amount},
+	})
+}
+
+// Escrow is handed to the vendor, minus the platform fee
+func releasePayment(ctx context.Context, tx *sqlx.Tx, payment *models.PaymentModel) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE Payment SET status = 'released' WHERE id = ?", payment.Id); err != nil {
+		return err
+	}
+
+	fee := models.PlatformFee(payment.Amount)
+
+	return recordJournal(ctx, tx, payment, models.JOURNAL_RELEASE, []models.LedgerEntryModel{
+		{Account: models.LEDGER_ACCOUNT_ESCROW, UserId: &payment.ClientId, Debit: payment.Amount},
+		{Account: models.LEDGER_ACCOUNT_VENDOR_PAYABLE, UserId: &payment.VendorId, Credit: models.RoundMoney(payment.Amount - fee)},
+		{Account: models.LEDGER_ACCOUNT_PLATFORM_FEES, Credit: fee},
+	})
+}
+
+// An authorized payment never reached escrow, only its status changes. A
+// penalty kept from a captured payment goes to the vendor like a release
+// would, less the platform fee
+func refundPayment(ctx context.Context, tx *sqlx.Tx, payment *models.PaymentModel, penalty float64) error {
+	penalty = models.RoundMoney(min(max(penalty, 0), payment.Amount))
+	refund := models.RoundMoney(payment.Amount - penalty)
+
+	status, journalType := models.PAYMENT_STATUS_REFUNDED, models.JOURNAL_REFUND
+	if refund == 0 {
+		status, journalType = models.PAYMENT_STATUS_RELEASED, models.JOURNAL_RELEASE
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Payment SET status = ? WHERE id = ?", status, payment.Id); err != nil {
+		return err
+	}
+
+	if payment.Status != models.PAYMENT_STATUS_CAPTURED {
+		return nil
+	}
+
+	entries := []models.LedgerEntryModel{
+		{Account: models.LEDGER_ACCOUNT_ESCROW, UserId: &payment.ClientId, Debit: payment.Amount},
+	}
+
+	if refund > 0 {
+		entries = append(entries, models.LedgerEntryModel{Account: models.LEDGER_ACCOUNT_PROVIDER_CLEARING, Credit: refund})
+	}
+
+	if penalty > 0 {
+		fee := models.PlatformFee(penalty)
+		entries = append(entries,
+			models.LedgerEntryModel{Account: models.LEDGER_ACCOUNT_VENDOR_PAYABLE, UserId: &payment.VendorId, Credit: models.RoundMoney(penalty - fee)},
+			models.LedgerEntryModel{Account: models.LEDGER_ACCOUNT_PLATFORM_FEES, Credit: fee},
+		)
+	}
+
+	return recordJournal(ctx, tx, payment, journalType, entries)
+}
+
+func recordJournal(ctx context.Context, tx *sqlx.Tx, payment *models.PaymentModel, journalType models.JournalType, entries []models.LedgerEntryModel) error {
+	debit, credit := 0.0, 0.0
+	for _, entry := range entries {
+		debit += entry.Debit
+		credit += entry.Credit
+	}
+
+	if models.RoundMoney(debit) != models.RoundMoney(credit) {
+		return errors.New("ledger journal does not balance")
+	}
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO LedgerJournal (paymentId, type) VALUES (?, ?)", payment.Id, journalType)
+	if err != nil {
+		return err
+	}
+
+	journalId, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO LedgerEntry (journalId, account, userId, debit, credit, currency) VALUES (?, ?, ?, ?, ?, ?)"
+	for _, entry := range entries {
+		if _, err := tx.ExecContext(ctx, query, journalId, entry.Account, entry.UserId, entry.Debit, entry.Credit, payment.Currency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
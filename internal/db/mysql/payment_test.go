@@ -0,0 +1,516 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var paymentRows = []string{"id", "transactionId", "clientId", "vendorId", "provider", "reference", "amount", "currency", "status", "createdAt", "updatedAt"}
+
+func TestReservePayment(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("INSERT INTO(.+)Payment(.+)'pending'").
+		WithArgs(5, 3, 2, "fake", 1500.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	payment := &models.PaymentModel{TransactionId: 5, ClientId: 3, VendorId: 2, Provider: "fake", Amount: 1500, Currency: "PHP"}
+	reserved, err := db.ReservePayment(payment)
+
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, 1, payment.Id)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestReservePaymentTwice(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("INSERT INTO(.+)Payment").
+		WillReturnError(&driver.MySQLError{Number: mysqlDuplicateEntry, Message: "Duplicate entry '5' for key 'transactionId'"})
+
+	reserved, err := db.ReservePayment(&models.PaymentModel{TransactionId: 5, ClientId: 3, VendorId: 2, Provider: "fake", Amount: 1500, Currency: "PHP"})
+
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestConfirmCapturedPayment(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	status := sqlmock.NewRows([]string{"status"}).AddRow("ongoing")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM Transaction WHERE id = \\? FOR UPDATE").WithArgs(5).WillReturnRows(status)
+	mock.ExpectExec("UPDATE Payment SET reference = \\?, status = 'authorized' WHERE id = \\? AND status = 'pending'").
+		WithArgs("fake_ch_5_1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE Payment SET status = 'captured'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_CHARGE).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(4, models.LEDGER_ACCOUNT_PROVIDER_CLEARING, nil, 1500.0, 0.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(4, models.LEDGER_ACCOUNT_ESCROW, 3, 0.0, 1500.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	payment := &models.PaymentModel{
+		TransactionId: 5,
+		ClientId:      3,
+		VendorId:      2,
+		Provider:      "fake",
+		Reference:     "fake_ch_5_1",
+		Amount:        1500,
+		Currency:      "PHP",
+		Status:        models.PAYMENT_STATUS_CAPTURED,
+	}
+	payment.Id = 1
+
+	assert.NoError(t, db.ConfirmPayment(payment))
+
+	err := mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestConfirmPaymentThatIsNotPending(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	status := sqlmock.NewRows([]string{"status"}).AddRow("ongoing")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM Transaction").WithArgs(5).WillReturnRows(status)
+	mock.ExpectExec("UPDATE Payment SET reference").WithArgs("fake_ch_5_1", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	payment := &models.PaymentModel{TransactionId: 5, Reference: "fake_ch_5_1", Status: models.PAYMENT_STATUS_AUTHORIZED}
+	payment.Id = 1
+
+	assert.Error(t, db.ConfirmPayment(payment))
+
+	err := mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestConfirmPaymentOfCancelledTransaction(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	status := sqlmock.NewRows([]string{"status"}).AddRow("cancelled")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM Transaction").WithArgs(5).WillReturnRows(status)
+	mock.ExpectRollback()
+
+	payment := &models.PaymentModel{TransactionId: 5, Reference: "fake_ch_5_1", Status: models.PAYMENT_STATUS_CAPTURED}
+	payment.Id = 1
+
+	assert.ErrorIs(t, db.ConfirmPayment(payment), models.ErrTransactionNotOngoing)
+
+	err := mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCompleteTransactionReleasesEscrow(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	parties := sqlmock.NewRows([]string{"vendorId", "clientId", "serviceId"}).AddRow(2, 3, 4)
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1234.5, "PHP", "captured", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Transaction SET status = 'done'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT vendorId, clientId, serviceId FROM Transaction").WithArgs(5).WillReturnRows(parties)
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_TRANSACTION_COMPLETED, 5, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectExec("UPDATE Payment SET status = 'released'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_RELEASE).WillReturnResult(sqlmock.NewResult(6, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_ESCROW, 3, 1234.5, 0.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_VENDOR_PAYABLE, 2, 0.0, 1111.05, "PHP").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_PLATFORM_FEES, nil, 0.0, 123.45, "PHP").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	err := db.CompleteTransaction(5)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCompleteCancelledTransaction(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Transaction SET status = 'done'(.+)WHERE id = \\? AND status = 'ongoing'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := db.CompleteTransaction(5)
+
+	assert.EqualError(t, err, "only ongoing transactions can be completed")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCompleteTransactionLeavesAuthorizedPaymentToTheWebhook(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	parties := sqlmock.NewRows([]string{"vendorId", "clientId", "serviceId"}).AddRow(2, 3, 4)
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1234.5, "PHP", "authorized", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Transaction SET status = 'done'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT vendorId, clientId, serviceId FROM Transaction").WithArgs(5).WillReturnRows(parties)
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_TRANSACTION_COMPLETED, 5, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectCommit()
+
+	assert.NoError(t, db.CompleteTransaction(5))
+
+	err := mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCancelTransaction(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	parties := sqlmock.NewRows([]string{"vendorId", "clientId", "serviceId"}).AddRow(2, 3, 4)
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "captured", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Transaction SET status = 'cancelled' WHERE id = \\? AND status = 'ongoing'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectQuery("SELECT vendorId, clientId, serviceId FROM Transaction").WithArgs(5).WillReturnRows(parties)
+	mock.ExpectExec("INSERT INTO(.+)TransactionCancellation").
+		WithArgs(5, 2, models.CANCELLATION_ROLE_VENDOR, 12.5, 25.0, 0.0, 1500.0, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_TRANSACTION_CANCELLED, 5, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := db.CancelTransaction(&models.TransactionCancellationModel{
+		TransactionId:  5,
+		CancelledBy:    2,
+		Role:           models.CANCELLATION_ROLE_VENDOR,
+		HoursBefore:    12.5,
+		PenaltyPercent: 25,
+		Refund:         1500,
+		Paid:           true,
+	})
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestSettleCancelledPaymentRefundsEscrow(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "captured", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectExec("UPDATE Payment SET status = \\?").WithArgs(models.PAYMENT_STATUS_REFUNDED, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_REFUND).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(7, models.LEDGER_ACCOUNT_ESCROW, 3, 1500.0, 0.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(7, models.LEDGER_ACCOUNT_PROVIDER_CLEARING, nil, 0.0, 1500.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	err := db.SettleCancelledPayment(5, 0)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestSettleCancelledPaymentKeepsPenalty(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "captured", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectExec("UPDATE Payment SET status = \\?").WithArgs(models.PAYMENT_STATUS_REFUNDED, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_REFUND).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(7, models.LEDGER_ACCOUNT_ESCROW, 3, 1500.0, 0.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(7, models.LEDGER_ACCOUNT_PROVIDER_CLEARING, nil, 0.0, 1125.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(7, models.LEDGER_ACCOUNT_VENDOR_PAYABLE, 2, 0.0, 337.5, "PHP").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(7, models.LEDGER_ACCOUNT_PLATFORM_FEES, nil, 0.0, 37.5, "PHP").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectCommit()
+
+	err := db.SettleCancelledPayment(5, 375)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestSettleSettledPayment(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "refunded", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectCommit()
+
+	err := db.SettleCancelledPayment(5, 375)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFindReliability(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"bookings", "completed", "cancellations", "lateCancellations"}).AddRow(10, 7, 2, 1)
+	mock.ExpectQuery("FROM(.+)Transaction t(.+)LEFT JOIN TransactionCancellation(.+)t.vendorId = \\?").WithArgs(2, 2, 2).WillReturnRows(rows)
+
+	reliability, err := db.FindReliability(2, models.CANCELLATION_ROLE_VENDOR)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.7, reliability.Score)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestCancelFinishedTransaction(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Transaction SET status = 'cancelled'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := db.CancelTransaction(&models.TransactionCancellationModel{TransactionId: 5, CancelledBy: 3, Role: models.CANCELLATION_ROLE_CLIENT})
+
+	assert.EqualError(t, err, "only ongoing transactions can be cancelled")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestApplyPaymentWebhookCapturesPayment(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "authorized", "", "")
+
+	status := sqlmock.NewRows([]string{"status"}).AddRow("ongoing")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT IGNORE INTO(.+)PaymentWebhookEvent").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT(.+)t.status(.+)FOR UPDATE OF t").WithArgs("fake", "fake_ch_5_1").WillReturnRows(status)
+	mock.ExpectQuery("FROM Payment WHERE provider = \\? AND reference = \\? FOR UPDATE").WithArgs("fake", "fake_ch_5_1").WillReturnRows(payment)
+	mock.ExpectExec("UPDATE Payment SET status = 'captured'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_CHARGE).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	applied, err := db.ApplyPaymentWebhook(&models.PaymentWebhookEventModel{
+		Provider:  "fake",
+		EventId:   "evt_1",
+		Type:      models.PAYMENT_EVENT_CAPTURED,
+		Reference: "fake_ch_5_1",
+		Amount:    1500,
+		Payload:   []byte(`{}`),
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestApplyPaymentWebhookReleasesCompletedBooking(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	status := sqlmock.NewRows([]string{"status"}).AddRow("done")
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1234.5, "PHP", "authorized", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT IGNORE INTO(.+)PaymentWebhookEvent").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT(.+)t.status(.+)FOR UPDATE OF t").WithArgs("fake", "fake_ch_5_1").WillReturnRows(status)
+	mock.ExpectQuery("FROM Payment WHERE provider = \\? AND reference = \\? FOR UPDATE").WithArgs("fake", "fake_ch_5_1").WillReturnRows(payment)
+	mock.ExpectExec("UPDATE Payment SET status = 'captured'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_CHARGE).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("UPDATE Payment SET status = 'released'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_RELEASE).WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(5, models.LEDGER_ACCOUNT_ESCROW, 3, 1234.5, 0.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(5, models.LEDGER_ACCOUNT_VENDOR_PAYABLE, 2, 0.0, 1111.05, "PHP").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(5, models.LEDGER_ACCOUNT_PLATFORM_FEES, nil, 0.0, 123.45, "PHP").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectCommit()
+
+	applied, err := db.ApplyPaymentWebhook(&models.PaymentWebhookEventModel{
+		Provider:  "fake",
+		EventId:   "evt_2",
+		Type:      models.PAYMENT_EVENT_CAPTURED,
+		Reference: "fake_ch_5_1",
+		Amount:    1234.5,
+		Payload:   []byte(`{}`),
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestApplyPaymentWebhookPartialRefund(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	status := sqlmock.NewRows([]string{"status"}).AddRow("ongoing")
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "captured", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT IGNORE INTO(.+)PaymentWebhookEvent").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT(.+)t.status(.+)FOR UPDATE OF t").WithArgs("fake", "fake_ch_5_1").WillReturnRows(status)
+	mock.ExpectQuery("FROM Payment WHERE provider = \\? AND reference = \\? FOR UPDATE").WithArgs("fake", "fake_ch_5_1").WillReturnRows(payment)
+	mock.ExpectExec("UPDATE Payment SET status = \\?").WithArgs(models.PAYMENT_STATUS_REFUNDED, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO LedgerJournal").WithArgs(1, models.JOURNAL_REFUND).WillReturnResult(sqlmock.NewResult(6, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_ESCROW, 3, 1500.0, 0.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_PROVIDER_CLEARING, nil, 0.0, 1000.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_VENDOR_PAYABLE, 2, 0.0, 450.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO LedgerEntry").
+		WithArgs(6, models.LEDGER_ACCOUNT_PLATFORM_FEES, nil, 0.0, 50.0, "PHP").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectCommit()
+
+	applied, err := db.ApplyPaymentWebhook(&models.PaymentWebhookEventModel{
+		Provider:  "fake",
+		EventId:   "evt_3",
+		Type:      models.PAYMENT_EVENT_REFUNDED,
+		Reference: "fake_ch_5_1",
+		Amount:    1000,
+		Payload:   []byte(`{}`),
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestApplyDuplicatePaymentWebhook(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT IGNORE INTO(.+)PaymentWebhookEvent").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	applied, err := db.ApplyPaymentWebhook(&models.PaymentWebhookEventModel{
+		Provider:  "fake",
+		EventId:   "evt_1",
+		Type:      models.PAYMENT_EVENT_CAPTURED,
+		Reference: "fake_ch_5_1",
+		Payload:   []byte(`{}`),
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, applied)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
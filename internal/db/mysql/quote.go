@@ -0,0 +1,394 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Creates the quote, or a new version of it when QuoteId is set, together
+// with the chat message that carries it
+func (m *Mysql) NewQuote(quote *request.NewQuote) (*models.MessageModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	quoteId, version, err := upsertQuote(ctx, tx, quote)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	insertVersion := `
+        INSERT INTO
+            QuoteVersion (quoteId, version, total, currency, start, end, validUntil, note)
+        VALUES
+            (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+    `
+
+	res, err := tx.ExecContext(ctx, insertVersion, quoteId, version, quote.Total(), quote.Currency, quote.Start, quote.End, quote.ValidUntil, quote.Note)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	versionId, err := res.LastInsertId()
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	insertLineItem := "INSERT INTO QuoteLineItem (quoteVersionId, title, quantity, unitPrice) VALUES (?, ?, ?, ?)"
+	for _, item := range quote.LineItems {
+		if _, err := tx.ExecContext(ctx, insertLineItem, versionId, item.Title, item.Quantity, item.UnitPrice); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return nil, err
+			}
+
+			return nil, err
+		}
+	}
+
+	message := &models.MessageModel{
+		Sender:   quote.VendorId,
+		Receiver: quote.ClientId,
+		Content:  fmt.Sprintf("Quote #%d v%d: %s %.2f", quoteId, version, quote.Currency, quote.Total()),
+		Type:     models.MESSAGE_TYPE_QUOTE,
+		QuoteId:  &quoteId,
+	}
+
+	if message.Id, err = insertMessage(ctx, tx, message); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return message, nil
+}
+
+func upsertQuote(ctx context.Context, tx *sqlx.Tx, quote *request.NewQuote) (int, int, error) {
+	if quote.QuoteId == 0 {
+		res, err := tx.ExecContext(ctx, "INSERT INTO Quote (vendorId, clientId, serviceId) VALUES (?, ?, ?)", quote.VendorId, quote.ClientId, quote.ServiceId)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return int(id), 1, nil
+	}
+
+	current := models.QuoteModel{}
+	if err := tx.GetContext(ctx, &current, "SELECT vendorId, clientId, status, version FROM Quote WHERE id = ? FOR UPDATE", quote.QuoteId); err != nil {
+		return 0, 0, errors.New("quote not found")
+	}
+
+	if current.VendorId != quote.VendorId || current.ClientId != quote.ClientId {
+		return 0, 0, errors.New("quote belongs to another conversation")
+	}
+
+	if current.Status != models.QUOTE_STATUS_PENDING {
+		return 0, 0, errors.New("quote has already been " + string(current.Status))
+	}
+
+	version := current.Version + 1
+	if _, err := tx.ExecContext(ctx, "UPDATE Quote SET version = ?, serviceId = ? WHERE id = ?", version, quote.ServiceId, quote.QuoteId); err != nil {
+		return 0, 0, err
+	}
+
+	return quote.QuoteId, version, nil
+}
+
+// Books the latest version of the quote. The transaction, the quote status
+// and the chat message are written together so a quote is never accepted
+// without its booking
+func (m *Mysql) AcceptQuote(response *request.QuoteResponse) (*models.MessageModel, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	quote, current, err := lockQuoteForResponse(ctx, tx, response)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	// Locked so the vendor cannot be restricted while the booking is made
+	restricted := false
+	if err := tx.GetContext(ctx, &restricted, "SELECT restricted FROM Vendor WHERE vendorId = ? FOR UPDATE", quote.VendorId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, errors.New("vendor not found")
+	}
+
+	if restricted {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, errors.New("vendor is restricted")
+	}
+
+	// The booking keeps the cancellation terms in force when it was made
+	var policy models.CancellationPolicy
+	var windows models.CancellationWindows
+	findPolicy := "SELECT cancellationPolicy, cancellationWindows FROM Service WHERE id = ?"
+
+	if err := tx.QueryRowxContext(ctx, findPolicy, quote.ServiceId).Scan(&policy, &windows); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	insertTransaction := `
+        INSERT INTO
+            Transaction (vendorId, clientId, serviceId, start, end, price, currency, quoteId, cancellationPolicy, cancellationWindows)
+        VALUES
+            (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	windows = models.ResolveCancellationWindows(policy, windows)
+	res, err := tx.ExecContext(ctx, insertTransaction, quote.VendorId, quote.ClientId, quote.ServiceId, current.Start, current.End, current.Total, current.Currency, response.QuoteId, policy, windows)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	transactionId, err := res.LastInsertId()
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	event := models.TransactionEvent{
+		TransactionId: int(transactionId),
+		VendorId:      quote.VendorId,
+		ClientId:      quote.ClientId,
+		ServiceId:     quote.ServiceId,
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_TRANSACTION_CREATED, int(transactionId), event); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Quote SET status = 'accepted', transactionId = ? WHERE id = ?", transactionId, response.QuoteId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	message := &models.MessageModel{
+		Sender:   quote.ClientId,
+		Receiver: quote.VendorId,
+		Content:  fmt.Sprintf("Accepted quote #%d v%d", response.QuoteId, response.Version),
+		Type:     models.MESSAGE_TYPE_QUOTE_ACCEPTED,
+		QuoteId:  &response.QuoteId,
+	}
+
+	if message.Id, err = insertMessage(ctx, tx, message); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, 0, err
+		}
+
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, 0, context.DeadlineExceeded
+	}
+
+	return message, int(transactionId), nil
+}
+
+func (m *Mysql) DeclineQuote(response *request.QuoteResponse) (*models.MessageModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, _, err := lockQuoteForResponse(ctx, tx, response)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Quote SET status = 'declined' WHERE id = ?", response.QuoteId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	message := &models.MessageModel{
+		Sender:   quote.ClientId,
+		Receiver: quote.VendorId,
+		Content:  fmt.Sprintf("Declined quote #%d v%d", response.QuoteId, response.Version),
+		Type:     models.MESSAGE_TYPE_QUOTE_DECLINED,
+		QuoteId:  &response.QuoteId,
+	}
+
+	if message.Id, err = insertMessage(ctx, tx, message); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return message, nil
+}
+
+// Locks the quote and checks that the client is answering the latest,
+// still valid version
+func lockQuoteForResponse(ctx context.Context, tx *sqlx.Tx, response *request.QuoteResponse) (*models.QuoteModel, *models.QuoteVersionModel, error) {
+	quote := &models.QuoteModel{}
+	if err := tx.GetContext(ctx, quote, "SELECT id, vendorId, clientId, serviceId, status, version FROM Quote WHERE id = ? FOR UPDATE", response.QuoteId); err != nil {
+		return nil, nil, errors.New("quote not found")
+	}
+
+	if quote.ClientId != response.ClientId {
+		return nil, nil, errors.New("quote was sent to another client")
+	}
+
+	if quote.Status != models.QUOTE_STATUS_PENDING {
+		return nil, nil, errors.New("quote has already been " + string(quote.Status))
+	}
+
+	if quote.Version != response.Version {
+		return nil, nil, errors.New("quote has been revised, review the latest version")
+	}
+
+	query := `
+        SELECT
+            id, quoteId, version, total, currency, start, end, validUntil
+        FROM
+            QuoteVersion
+        WHERE
+            quoteId = ? AND version = ? AND validUntil >= CURDATE()
+    `
+
+	current := &models.QuoteVersionModel{}
+	if err := tx.GetContext(ctx, current, query, response.QuoteId, response.Version); err != nil {
+		return nil, nil, errors.New("quote has expired")
+	}
+
+	return quote, current, nil
+}
+
+func (m *Mysql) FindQuoteById(id int) (*models.QuoteModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, vendorId, clientId, serviceId, status, version, transactionId, createdAt, updatedAt
+        FROM
+            Quote
+        WHERE
+            id = ?
+    `
+
+	quote := &models.QuoteModel{}
+	if err := m.Conn.GetContext(ctx, quote, query, id); err != nil {
+		return nil, err
+	}
+
+	versions := make([]models.QuoteVersionModel, 0)
+	if err := m.Conn.SelectContext(ctx, &versions, "SELECT id, quoteId, version, total, currency, start, end, validUntil, note, createdAt FROM QuoteVersion WHERE quoteId = ? ORDER BY version", id); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.QuoteLineItemModel, 0)
+	if err := m.Conn.SelectContext(ctx, &items, "SELECT i.id, i.quoteVersionId, i.title, i.quantity, i.unitPrice FROM QuoteLineItem i JOIN QuoteVersion v ON v.id = i.quoteVersionId WHERE v.quoteId = ? ORDER BY i.id", id); err != nil {
+		return nil, err
+	}
+
+	for idx := range versions {
+		versions[idx].LineItems = make([]models.QuoteLineItemModel, 0)
+		for _, item := range items {
+			if item.QuoteVersionId == versions[idx].Id {
+				versions[idx].LineItems = append(versions[idx].LineItems, item)
+			}
+		}
+	}
+	quote.Versions = versions
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQuote() *request.NewQuote {
+	return &request.NewQuote{
+		VendorId:   2,
+		ClientId:   3,
+		ServiceId:  4,
+		Start:      "2026-11-02",
+		End:        "2026-11-04",
+		ValidUntil: "2026-10-25",
+		Currency:   "PHP",
+		LineItems: []request.QuoteLineItem{
+			{Title: "Tiles", Quantity: 20, UnitPrice: 150},
+			{Title: "Labor", Quantity: 2, UnitPrice: 1500},
+		},
+	}
+}
+
+func TestNewQuote(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO Quote").WithArgs(2, 3, 4).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO(.+)QuoteVersion").
+		WithArgs(1, 1, 6000.0, "PHP", "2026-11-02", "2026-11-04", "2026-10-25", "").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO QuoteLineItem").WithArgs(7, "Tiles", 20.0, 150.0).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO QuoteLineItem").WithArgs(7, "Labor", 2.0, 1500.0).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO(.+)Message").WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_MESSAGE_SENT, 9, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	message, err := db.NewQuote(newTestQuote())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, message.Id)
+	assert.Equal(t, models.MESSAGE_TYPE_QUOTE, message.Type)
+	assert.Equal(t, 1, *message.QuoteId)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestReviseAnsweredQuote(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"vendorId", "clientId", "status", "version"}).AddRow(2, 3, "accepted", 2)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM Quote WHERE id = \\? FOR UPDATE").WithArgs(1).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	quote := newTestQuote()
+	quote.QuoteId = 1
+
+	_, err := db.NewQuote(quote)
+
+	assert.EqualError(t, err, "quote has already been accepted")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestAcceptQuote(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	quote := sqlmock.NewRows([]string{"id", "vendorId", "clientId", "serviceId", "status", "version"}).AddRow(1, 2, 3, 4, "pending", 2)
+	version := sqlmock.NewRows([]string{"id", "quoteId", "version", "total", "currency", "start", "end", "validUntil"}).
+		AddRow(8, 1, 2, 5500.0, "PHP", "2026-11-02", "2026-11-04", "2026-10-25")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Quote WHERE id = \\? FOR UPDATE").WithArgs(1).WillReturnRows(quote)
+	mock.ExpectQuery("FROM(.+)QuoteVersion(.+)validUntil >= CURDATE\\(\\)").WithArgs(1, 2).WillReturnRows(version)
+	mock.ExpectQuery("SELECT restricted FROM Vendor WHERE vendorId = \\? FOR UPDATE").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"restricted"}).AddRow(false))
+	mock.ExpectQuery("SELECT cancellationPolicy, cancellationWindows FROM Service").WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"cancellationPolicy", "cancellationWindows"}).AddRow("moderate", nil))
+	mock.ExpectExec("INSERT INTO(.+)Transaction").
+		WithArgs(2, 3, 4, "2026-11-02", "2026-11-04", 5500.0, "PHP", 1, "moderate", []byte(`[{"hoursBefore":72,"penaltyPercent":25},{"hoursBefore":24,"penaltyPercent":50}]`)).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_TRANSACTION_CREATED, 11, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE Quote SET status = 'accepted', transactionId = \\?").WithArgs(11, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO(.+)Message").WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_MESSAGE_SENT, 12, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	message, transactionId, err := db.AcceptQuote(&request.QuoteResponse{QuoteId: 1, Version: 2, ClientId: 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, transactionId)
+	assert.Equal(t, models.MESSAGE_TYPE_QUOTE_ACCEPTED, message.Type)
+	assert.Equal(t, 2, message.Receiver)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestAcceptQuoteOfRestrictedVendor(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	quote := sqlmock.NewRows([]string{"id", "vendorId", "clientId", "serviceId", "status", "version"}).AddRow(1, 2, 3, 4, "pending", 2)
+	version := sqlmock.NewRows([]string{"id", "quoteId", "version", "total", "currency", "start", "end", "validUntil"}).
+		AddRow(8, 1, 2, 5500.0, "PHP", "2026-11-02", "2026-11-04", "2026-10-25")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Quote WHERE id = \\? FOR UPDATE").WithArgs(1).WillReturnRows(quote)
+	mock.ExpectQuery("FROM(.+)QuoteVersion").WithArgs(1, 2).WillReturnRows(version)
+	mock.ExpectQuery("SELECT restricted FROM Vendor").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"restricted"}).AddRow(true))
+	mock.ExpectRollback()
+
+	_, _, err := db.AcceptQuote(&request.QuoteResponse{QuoteId: 1, Version: 2, ClientId: 3})
+
+	assert.EqualError(t, err, "vendor is restricted")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestAcceptRevisedQuote(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	quote := sqlmock.NewRows([]string{"id", "vendorId", "clientId", "serviceId", "status", "version"}).AddRow(1, 2, 3, 4, "pending", 3)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Quote WHERE id = \\? FOR UPDATE").WithArgs(1).WillReturnRows(quote)
+	mock.ExpectRollback()
+
+	_, _, err := db.AcceptQuote(&request.QuoteResponse{QuoteId: 1, Version: 2, ClientId: 3})
+
+	assert.EqualError(t, err, "quote has been revised, review the latest version")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nearbyassist/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (m *Mysql) FindReceiptSource(transactionId int) (*models.ReceiptSourceModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            t.id AS transactionId,
+            t.vendorId,
+            t.clientId,
+            uVendor.name AS vendor,
+            uClient.name AS client,
+            s.title AS service,
+            t.start,
+            t.end,
+            t.status,
+            t.price,
+            t.currency,
+            t.quoteId,
+            COALESCE(t.completedAt, t.updatedAt) AS completedAt
+        FROM
+            Transaction t
+            JOIN User uVendor ON uVendor.id = t.vendorId
+            JOIN User uClient ON uClient.id = t.clientId
+            JOIN Service s ON s.id = t.serviceId
+        WHERE
+            t.id = ?
+    `
+
+	source := &models.ReceiptSourceModel{}
+	if err := m.Conn.GetContext(ctx, source, query, transactionId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return source, nil
+}
+
+// Reserves the invoice number and revision for a receipt that is about to be
+// rendered. The row has no path until StoreReceipt is called, a reservation
+// left behind by a failed render is reused so the vendor's invoice numbers
+// stay without gaps
+func (m *Mysql) ReserveReceipt(receipt *models.ReceiptModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	latest := "SELECT id, invoiceNumber, revision, path IS NULL AS pending FROM Receipt WHERE transactionId = ? ORDER BY revision DESC LIMIT 1 FOR UPDATE"
+
+	pending := false
+	err = tx.QueryRowxContext(ctx, latest, receipt.TransactionId).Scan(&receipt.Id, &receipt.InvoiceNumber, &receipt.Revision, &pending)
+	switch {
+	case err == nil && pending:
+		reuse := `
+            UPDATE
+                Receipt
+            SET
+                subtotal = :subtotal, tax = :tax, total = :total, currency = :currency, reason = :reason
+            WHERE
+                id = :id
+        `
+
+		_, err = tx.NamedExecContext(ctx, reuse, receipt)
+
+	case err == nil:
+		receipt.Revision++
+		err = insertReceipt(ctx, tx, receipt)
+
+	case errors.Is(err, sql.ErrNoRows):
+		receipt.Revision = 1
+
+		next := "INSERT INTO InvoiceSequence (vendorId, lastNumber) VALUES (?, 1) ON DUPLICATE KEY UPDATE lastNumber = lastNumber + 1"
+		if _, err = tx.ExecContext(ctx, next, receipt.VendorId); err == nil {
+			err = tx.GetContext(ctx, &receipt.InvoiceNumber, "SELECT lastNumber FROM InvoiceSequence WHERE vendorId = ?", receipt.VendorId)
+		}
+
+		if err == nil {
+			err = insertReceipt(ctx, tx, receipt)
+		}
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func insertReceipt(ctx context.Context, tx *sqlx.Tx, receipt *models.ReceiptModel) error {
+	query := `
+        INSERT INTO
+            Receipt (transactionId, vendorId, clientId, invoiceNumber, revision, subtotal, tax, total, currency, reason)
+        VALUES
+            (:transactionId, :vendorId, :clientId, :invoiceNumber, :revision, :subtotal, :tax, :total, :currency, :reason)
+    `
+
+	res, err := tx.NamedExecContext(ctx, query, receipt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	receipt.Id = int(id)
+
+	return nil
+}
+
+// Attaches the rendered document to a reserved receipt. Fails when another
+// render already stored one, the caller then removes its own file
+func (m *Mysql) StoreReceipt(id int, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	res, err := m.Conn.ExecContext(ctx, "UPDATE Receipt SET path = ? WHERE id = ? AND path IS NULL", path, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return errors.New("receipt is already stored")
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Latest revision first, reservations that were never stored are left out
+func (m *Mysql) FindReceipts(transactionId int) ([]models.ReceiptModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, transactionId, vendorId, clientId, invoiceNumber, revision, subtotal, tax, total, currency, path, reason, createdAt
+        FROM
+            Receipt
+        WHERE
+            transactionId = ?
+            AND path IS NOT NULL
+        ORDER BY
+            revision DESC
+    `
+
+	receipts := make([]models.ReceiptModel, 0)
+	if err := m.Conn.SelectContext(ctx, &receipts, query, transactionId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return receipts, nil
+}
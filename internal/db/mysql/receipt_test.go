@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"errors"
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReceipt() *models.ReceiptModel {
+	return &models.ReceiptModel{
+		TransactionId: 5,
+		VendorId:      2,
+		ClientId:      3,
+		Subtotal:      1000,
+		Tax:           120,
+		Total:         1120,
+		Currency:      "PHP",
+	}
+}
+
+func TestReserveReceiptTakesNextInvoiceNumber(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Receipt WHERE transactionId = \\?(.+)FOR UPDATE").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id", "invoiceNumber", "revision", "pending"}))
+	mock.ExpectExec("INSERT INTO InvoiceSequence(.+)ON DUPLICATE KEY UPDATE lastNumber = lastNumber \\+ 1").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT lastNumber FROM InvoiceSequence").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"lastNumber"}).AddRow(14))
+	mock.ExpectExec("INSERT INTO(.+)Receipt").
+		WithArgs(5, 2, 3, 14, 1, 1000.0, 120.0, 1120.0, "PHP", nil).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectCommit()
+
+	receipt := newTestReceipt()
+	err := db.ReserveReceipt(receipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, receipt.Id)
+	assert.Equal(t, 14, receipt.InvoiceNumber)
+	assert.Equal(t, 1, receipt.Revision)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestReserveReceiptRevision(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	reason := "Wrong schedule"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Receipt WHERE transactionId = \\?(.+)FOR UPDATE").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id", "invoiceNumber", "revision", "pending"}).AddRow(9, 14, 2, false))
+	mock.ExpectExec("INSERT INTO(.+)Receipt").
+		WithArgs(5, 2, 3, 14, 3, 1000.0, 120.0, 1120.0, "PHP", &reason).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectCommit()
+
+	receipt := newTestReceipt()
+	receipt.Reason = &reason
+
+	err := db.ReserveReceipt(receipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, receipt.Id)
+	assert.Equal(t, 14, receipt.InvoiceNumber)
+	assert.Equal(t, 3, receipt.Revision)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestReserveReceiptReusesPendingReservation(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Receipt WHERE transactionId = \\?(.+)FOR UPDATE").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id", "invoiceNumber", "revision", "pending"}).AddRow(9, 14, 1, true))
+	mock.ExpectExec("UPDATE(.+)Receipt(.+)WHERE(.+)id = \\?").
+		WithArgs(1000.0, 120.0, 1120.0, "PHP", nil, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	receipt := newTestReceipt()
+	err := db.ReserveReceipt(receipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, receipt.Id)
+	assert.Equal(t, 14, receipt.InvoiceNumber)
+	assert.Equal(t, 1, receipt.Revision)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestReserveReceiptRollsBack(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM Receipt WHERE transactionId = \\?").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id", "invoiceNumber", "revision", "pending"}))
+	mock.ExpectExec("INSERT INTO InvoiceSequence").WithArgs(2).WillReturnError(errors.New("deadlock"))
+	mock.ExpectRollback()
+
+	err := db.ReserveReceipt(newTestReceipt())
+
+	assert.EqualError(t, err, "deadlock")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestStoreReceiptAlreadyStored(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("UPDATE Receipt SET path = \\? WHERE id = \\? AND path IS NULL").
+		WithArgs("store/receipts/a.pdf", 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := db.StoreReceipt(9, "store/receipts/a.pdf")
+
+	assert.EqualError(t, err, "receipt is already stored")
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
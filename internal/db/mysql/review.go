@@ -54,6 +54,28 @@ func (m *Mysql) CreateReview(review *request.NewReview) (int, error) {
 		return 0, err
 	}
 
+	event := models.ReviewEvent{
+		ReviewId:      int(insertId),
+		TransactionId: review.TransactionId,
+		ServiceId:     review.ServiceId,
+	}
+
+	if err := tx.QueryRowxContext(ctx, "SELECT vendorId FROM Transaction WHERE id = ?", review.TransactionId).Scan(&event.VendorId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_REVIEW_POSTED, int(insertId), event); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		if err := tx.Rollback(); err != nil {
 			return 0, err
@@ -98,3 +120,28 @@ func (m *Mysql) FindAllReviewByService(id int) ([]models.ReviewModel, error) {
 
 	return reviews, nil
 }
+
+// Recomputes the vendor rating from the reviews of the given service
+func (m *Mysql) RecalculateVendorRating(serviceId int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        UPDATE
+            Vendor
+        SET
+            rating = (SELECT ROUND(AVG(rating), 1) FROM Review WHERE serviceId = ?)
+        WHERE
+            vendorId = (SELECT vendorId FROM Service WHERE id = ?)
+    `
+
+	if _, err := m.Conn.ExecContext(ctx, query, serviceId, serviceId); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
@@ -3,12 +3,14 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"math"
 	"nearbyassist/internal/models"
 	"nearbyassist/internal/request"
 	"nearbyassist/internal/response"
 	"nearbyassist/internal/types"
-	"nearbyassist/internal/utils"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 func (m *Mysql) CountServices() (int, error) {
@@ -37,9 +39,15 @@ func (m *Mysql) FindServiceById(id int) (*response.ServiceDetails, error) {
 	query := `
         SELECT
             id as serviceId,
+            title,
             description,
-            format(rate, 2) as rate,
-            latitude, 
+            rate,
+            pricingUnit,
+            minimumCharge,
+            currency,
+            cancellationPolicy,
+            cancellationWindows,
+            latitude,
             longitude
         FROM 
             Service
@@ -67,8 +75,12 @@ func (m *Mysql) FindServiceByVendor(id int) ([]*models.ServiceModel, error) {
         SELECT
             id,
             vendorId,
+            title,
             description,
             rate,
+            pricingUnit,
+            minimumCharge,
+            currency,
             latitude,
             longitude
         FROM 
@@ -98,8 +110,12 @@ func (m *Mysql) FindAllService() ([]*models.ServiceModel, error) {
         SELECT
             id,
             vendorId,
+            title,
             description,
             rate,
+            pricingUnit,
+            minimumCharge,
+            currency,
             latitude,
             longitude
         FROM 
@@ -133,14 +149,21 @@ func (m *Mysql) RegisterService(service *request.NewService) (int, error) {
 	registerService := `
 	        INSERT INTO
 	            Service
-	                (vendorId, description, rate, latitude, longitude)
+	                (vendorId, title, description, rate, pricingUnit, minimumCharge, currency, cancellationPolicy, cancellationWindows, latitude, longitude, location)
 	        VALUES 
                 (
                     :vendorId,
+                    :title,
                     :description,
                     :rate,
+                    :pricingUnit,
+                    :minimumCharge,
+                    :currency,
+                    :cancellationPolicy,
+                    :cancellationWindows,
                     :latitude,
-                    :longitude
+                    :longitude,
+                    ` + locationPoint + `
                 )
 	    `
 
@@ -158,19 +181,24 @@ func (m *Mysql) RegisterService(service *request.NewService) (int, error) {
 		return 0, err
 	}
 
-	registerTag := `
-        INSERT INTO 
-            ServiceTag (serviceId, tagId)
-        VALUES
-            (
-                ?,
-                (SELECT id FROM Tag WHERE title = ?)
-            )
-    `
+	tagIds, unknown, err := resolveTags(ctx, tx, service.Tags)
+	if err == nil && len(unknown) > 0 {
+		err = &models.UnknownTagsError{Titles: unknown}
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	registerTag := "INSERT INTO ServiceTag (serviceId, tagId) VALUES (?, ?)"
 
 	var tagErr error
-	for _, tag := range service.Tags {
-		if _, err := tx.ExecContext(ctx, registerTag, serviceId, tag); err != nil {
+	for _, tagId := range tagIds {
+		if _, err := tx.ExecContext(ctx, registerTag, serviceId, tagId); err != nil {
 			tagErr = err
 			break
 		}
@@ -181,6 +209,14 @@ func (m *Mysql) RegisterService(service *request.NewService) (int, error) {
 			return 0, err
 		}
 
+		return 0, tagErr
+	}
+
+	if err := insertAddOns(ctx, tx, int(serviceId), service.AddOns); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
 		return 0, err
 	}
 
@@ -210,10 +246,17 @@ func (m *Mysql) UpdateService(service *request.UpdateService) error {
         UPDATE
             Service
         SET
+            title = :title,
             description = :description,
             rate = :rate,
+            pricingUnit = :pricingUnit,
+            minimumCharge = :minimumCharge,
+            currency = :currency,
+            cancellationPolicy = :cancellationPolicy,
+            cancellationWindows = :cancellationWindows,
             latitude = :latitude,
-            longitude = :longitude
+            longitude = :longitude,
+            location = ` + locationPoint + `
         WHERE
             id = :id
     `
@@ -227,17 +270,20 @@ func (m *Mysql) UpdateService(service *request.UpdateService) error {
 		return err
 	}
 
-	getExistingTags := `
-        SELECT
-            st.id,
-            st.serviceId,
-            t.title AS tag
-        FROM
-            ServiceTag st
-            JOIN Tag t ON t.id = st.tagId
-        WHERE
-            st.serviceId = ?;
-    `
+	tagIds, unknown, err := resolveTags(ctx, tx, service.Tags)
+	if err == nil && len(unknown) > 0 {
+		err = &models.UnknownTagsError{Titles: unknown}
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	getExistingTags := "SELECT id, serviceId, tagId FROM ServiceTag WHERE serviceId = ?"
 
 	existingTags := make([]models.ServiceTagModel, 0)
 	if err := tx.SelectContext(ctx, &existingTags, getExistingTags, service.Id); err != nil {
@@ -249,17 +295,18 @@ func (m *Mysql) UpdateService(service *request.UpdateService) error {
 	}
 
 	deleteTag := "DELETE FROM ServiceTag WHERE id = ?"
-	insertTag := "INSERT INTO ServiceTag (serviceId, tagId) VALUES (?, (SELECT id FROM Tag WHERE title = ?))"
+	insertTag := "INSERT INTO ServiceTag (serviceId, tagId) VALUES (?, ?)"
 
-	newTags := service.Tags
+	newTags := make(map[int]bool, len(tagIds))
+	for _, tagId := range tagIds {
+		newTags[tagId] = true
+	}
 
 	for _, tag := range existingTags {
-		exists := utils.StringSliceContains(newTags, tag.Tag)
-		if exists {
-			// Remove item from newTags
-			newTags = utils.RemoveStringFromSlice(newTags, tag.Tag)
+		if newTags[tag.TagId] {
+			// Already tagged, nothing to insert
+			delete(newTags, tag.TagId)
 		} else {
-			// Append to tagsToBeDeleted
 			if _, err := tx.ExecContext(ctx, deleteTag, tag.Id); err != nil {
 				if err := tx.Rollback(); err != nil {
 					return err
@@ -270,8 +317,12 @@ func (m *Mysql) UpdateService(service *request.UpdateService) error {
 		}
 	}
 
-	for _, tag := range newTags {
-		if _, err := tx.ExecContext(ctx, insertTag, service.Id, tag); err != nil {
+	for _, tagId := range tagIds {
+		if !newTags[tagId] {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, insertTag, service.Id, tagId); err != nil {
 			if err := tx.Rollback(); err != nil {
 				return err
 			}
@@ -280,6 +331,23 @@ func (m *Mysql) UpdateService(service *request.UpdateService) error {
 		}
 	}
 
+	// Add-ons have no identity of their own so they are replaced as a whole
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ServiceAddOn WHERE serviceId = ?", service.Id); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := insertAddOns(ctx, tx, service.Id, service.AddOns); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		if err := tx.Rollback(); err != nil {
 			return err
@@ -295,6 +363,36 @@ func (m *Mysql) UpdateService(service *request.UpdateService) error {
 	return nil
 }
 
+func (m *Mysql) FindServiceAddOns(serviceId int) ([]models.ServiceAddOnModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, serviceId, title, price FROM ServiceAddOn WHERE serviceId = ? ORDER BY id"
+
+	addOns := make([]models.ServiceAddOnModel, 0)
+	if err := m.Conn.SelectContext(ctx, &addOns, query, serviceId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return addOns, nil
+}
+
+func insertAddOns(ctx context.Context, tx *sqlx.Tx, serviceId int, addOns []request.ServiceAddOn) error {
+	query := "INSERT INTO ServiceAddOn (serviceId, title, price) VALUES (?, ?, ?)"
+
+	for _, addOn := range addOns {
+		if _, err := tx.ExecContext(ctx, query, serviceId, addOn.Title, addOn.Price); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Mysql) DeleteService(id int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
@@ -345,44 +443,185 @@ func (m *Mysql) GeoSpatialSearch(params *types.SearchParams) ([]*models.ServiceS
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
+	// Unknown search terms are ignored, but a search where nothing resolves
+	// cannot match any service
+	tagIds, _, err := resolveTags(ctx, m.Conn, params.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tagIds) == 0 {
+		return []*models.ServiceSearchResult{}, nil
+	}
+
+	// The bounding box lets the spatial index discard most services before
+	// the exact distance is computed
 	query := `
         SELECT 
             s.id,
             s.vendorId,
             u.name as vendor,
+            s.title,
             s.description,
-            format(s.rate, 2) as rate,
+            s.rate,
+            s.pricingUnit,
+            s.minimumCharge,
+            s.currency,
             s.latitude,
-            s.longitude
+            s.longitude,
+            v.rating,
+            ` + verifiedColumn + ` as verified,
+            ST_Distance_Sphere(POINT(s.longitude, s.latitude), POINT(?, ?)) as distance
         FROM 
-            ServiceTag st
-            JOIN Service s ON s.id = st.serviceId
+            Service s
             JOIN User u ON u.id = s.vendorId
+            JOIN Vendor v ON v.vendorId = s.vendorId AND v.restricted = 0
         WHERE
+            MBRContains(ST_GeomFromText(?, 4326, 'axis-order=long-lat'), s.location)
+            AND ` + tagCondition + `
     `
 
-	tagCondition := ""
-	for i, tag := range params.Query {
-		if i == 0 {
-			tagCondition += fmt.Sprintf(" st.tagId = (SELECT id from Tag WHERE title = '%s')", tag)
-			continue
-		}
-		tagCondition += fmt.Sprintf(" OR st.tagId = (SELECT id from Tag WHERE title = '%s')", tag)
+	args := []any{params.Longitude, params.Latitude, boundingBox(params.Latitude, params.Longitude, params.Radius), tagIds}
+
+	if params.MinRate > 0 {
+		query += " AND s.rate >= ?"
+		args = append(args, params.MinRate)
 	}
 
-	distanceCondition := fmt.Sprintf(" AND ST_Distance_Sphere(POINT(s.longitude, s.latitude), POINT(%v, %v)) < ?", params.Longitude, params.Latitude)
+	if params.MaxRate > 0 {
+		query += " AND s.rate <= ?"
+		args = append(args, params.MaxRate)
+	}
 
-	query += tagCondition + distanceCondition
+	if params.MinRating > 0 {
+		query += " AND v.rating >= ?"
+		args = append(args, params.MinRating)
+	}
 
-	services := make([]*models.ServiceSearchResult, 0)
-	err := m.Conn.SelectContext(ctx, &services, query, params.Radius)
+	if params.VerifiedOnly {
+		query += " AND " + verifiedColumn
+	}
+
+	// Vendors with an ongoing booking on that day are not available
+	if params.AvailableOn != "" {
+		query += `
+            AND NOT EXISTS (
+                SELECT 1 FROM Transaction t
+                WHERE t.vendorId = s.vendorId AND t.status = 'ongoing' AND DATE(t.start) <= ? AND DATE(t.end) >= ?
+            )`
+		args = append(args, params.AvailableOn, params.AvailableOn)
+	}
+
+	query += " HAVING distance < ?"
+	args = append(args, params.Radius)
+
+	query, args, err = sqlx.In(query, args...)
 	if err != nil {
 		return nil, err
 	}
 
+	services := make([]*models.ServiceSearchResult, 0)
+	if err := m.Conn.SelectContext(ctx, &services, m.Conn.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
 	if ctx.Err() == context.DeadlineExceeded {
 		return nil, context.DeadlineExceeded
 	}
 
 	return services, nil
 }
+
+// Most services returned for one map viewport
+const MAP_SERVICE_LIMIT = 5000
+
+// Services inside the map viewport, capped so a zoomed out map stays cheap.
+// Reports whether the cap cut the result short so clients know the cluster
+// counts only cover part of the viewport
+func (m *Mysql) FindServicesInBounds(params *types.MapParams) ([]*models.ServiceSearchResult, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            s.id,
+            s.vendorId,
+            u.name as vendor,
+            s.latitude,
+            s.longitude
+        FROM
+            Service s
+            JOIN User u ON u.id = s.vendorId
+            JOIN Vendor v ON v.vendorId = s.vendorId AND v.restricted = 0
+        WHERE
+            MBRContains(ST_GeomFromText(?, 4326, 'axis-order=long-lat'), s.location)
+    `
+
+	args := []any{boundsPolygon(params.MinLatitude, params.MinLongitude, params.MaxLatitude, params.MaxLongitude)}
+
+	if len(params.Query) > 0 {
+		tagIds, _, err := resolveTags(ctx, m.Conn, params.Query)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if len(tagIds) == 0 {
+			return []*models.ServiceSearchResult{}, false, nil
+		}
+
+		query += " AND " + tagCondition
+		args = append(args, tagIds)
+	}
+
+	// One row past the cap tells a full viewport apart from a truncated one
+	query += " LIMIT ?"
+	args = append(args, MAP_SERVICE_LIMIT+1)
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	services := make([]*models.ServiceSearchResult, 0)
+	if err := m.Conn.SelectContext(ctx, &services, m.Conn.Rebind(query), args...); err != nil {
+		return nil, false, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, false, context.DeadlineExceeded
+	}
+
+	truncated := len(services) > MAP_SERVICE_LIMIT
+	if truncated {
+		services = services[:MAP_SERVICE_LIMIT]
+	}
+
+	return services, truncated, nil
+}
+
+// Builds the location column from the named latitude and longitude
+const locationPoint = "ST_GeomFromText(CONCAT('POINT(', :longitude, ' ', :latitude, ')'), 4326, 'axis-order=long-lat')"
+
+// Vendors with an approved identity verification
+const verifiedColumn = "EXISTS (SELECT 1 FROM IdentityVerification iv WHERE iv.user = s.vendorId AND iv.status = 'approved')"
+
+// Matches services with any of the given resolved tag ids, expanded with sqlx.In
+const tagCondition = "EXISTS (SELECT 1 FROM ServiceTag st WHERE st.serviceId = s.id AND st.tagId IN (?))"
+
+// Returns a WKT polygon in long-lat order that encloses the circle of the
+// given radius in meters
+func boundingBox(latitude, longitude, radius float64) string {
+	const metersPerDegree = 111320.0
+
+	deltaLat := radius / metersPerDegree
+	deltaLong := radius / (metersPerDegree * math.Max(math.Cos(latitude*math.Pi/180), 0.01))
+
+	minLat, maxLat := math.Max(latitude-deltaLat, -90), math.Min(latitude+deltaLat, 90)
+	minLong, maxLong := math.Max(longitude-deltaLong, -180), math.Min(longitude+deltaLong, 180)
+
+	return boundsPolygon(minLat, minLong, maxLat, maxLong)
+}
+
+func boundsPolygon(minLat, minLong, maxLat, maxLong float64) string {
+	return fmt.Sprintf("POLYGON((%[1]f %[3]f, %[2]f %[3]f, %[2]f %[4]f, %[1]f %[4]f, %[1]f %[3]f))", minLong, maxLong, minLat, maxLat)
+}
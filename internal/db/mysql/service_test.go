@@ -0,0 +1,291 @@
+package mysql
+
+import (
+	"fmt"
+	"math/rand"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/types"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoSpatialSearch(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "vendorId", "vendor", "title", "description", "rate", "pricingUnit", "minimumCharge", "currency", "latitude", "longitude", "rating", "verified", "distance"}).
+		AddRow(1, 2, "vendor", "Pipe repair", "description", 1100.0, "hour", 2200.0, "PHP", 7.07, 125.61, "4.5", 1, 120.5)
+
+	params := &types.SearchParams{Latitude: 7.07, Longitude: 125.61, Radius: 500, Query: []string{"plumber", "electrician"}}
+
+	mock.ExpectQuery("FROM Tag t(.+)UNION ALL(.+)FROM TagSynonym").
+		WithArgs("plumber", "electrician", "plumber", "electrician").
+		WillReturnRows(sqlmock.NewRows([]string{"title", "tagId"}).AddRow("plumber", 3).AddRow("electrician", 4))
+	mock.ExpectQuery("ST_Distance_Sphere(.+)MBRContains(.+)st.tagId IN \\(\\?, \\?\\)(.+)HAVING distance < \\?").
+		WithArgs(125.61, 7.07, boundingBox(7.07, 125.61, 500), 3, 4, 500.0).
+		WillReturnRows(rows)
+
+	services, err := db.GeoSpatialSearch(params)
+
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.Equal(t, 1100.0, services[0].Rate)
+	assert.Equal(t, models.PRICING_UNIT_HOUR, services[0].PricingUnit)
+	assert.Equal(t, 4.5, services[0].Rating)
+	assert.True(t, services[0].Verified)
+	assert.Equal(t, 120.5, services[0].Distance)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGeoSpatialSearchWithFilters(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id"})
+
+	params := &types.SearchParams{
+		Latitude:     7.07,
+		Longitude:    125.61,
+		Radius:       500,
+		Query:        []string{"plumber"},
+		MinRate:      100,
+		MaxRate:      500,
+		MinRating:    4,
+		VerifiedOnly: true,
+		AvailableOn:  "2026-10-20",
+	}
+
+	mock.ExpectQuery("FROM Tag t(.+)UNION ALL(.+)FROM TagSynonym").
+		WithArgs("plumber", "plumber").
+		WillReturnRows(sqlmock.NewRows([]string{"title", "tagId"}).AddRow("plumber", 3))
+	mock.ExpectQuery("s.rate >= \\?(.+)s.rate <= \\?(.+)v.rating >= \\?(.+)iv.status = 'approved'(.+)NOT EXISTS(.+)HAVING distance < \\?").
+		WithArgs(125.61, 7.07, boundingBox(7.07, 125.61, 500), 3, 100.0, 500.0, 4.0, "2026-10-20", "2026-10-20", 500.0).
+		WillReturnRows(rows)
+
+	services, err := db.GeoSpatialSearch(params)
+
+	assert.NoError(t, err)
+	assert.Empty(t, services)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestBoundingBox(t *testing.T) {
+	// 1113.2 meters is 0.01 degrees of latitude
+	box := boundingBox(0, 0, 1113.2)
+
+	assert.Equal(t, "POLYGON((-0.010000 -0.010000, 0.010000 -0.010000, 0.010000 0.010000, -0.010000 0.010000, -0.010000 -0.010000))", box)
+}
+
+func TestRegisterServiceSetsLocation(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO(.+)location(.+)ST_GeomFromText").
+		WithArgs(2, "Pipe repair", "description", 100.0, models.PRICING_UNIT_VISIT, 0.0, "PHP", models.CANCELLATION_POLICY_STRICT, nil, 7.07, 125.61, 125.61, 7.07).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("FROM Tag t(.+)UNION ALL(.+)FROM TagSynonym").
+		WithArgs("plumber", "plumber").
+		WillReturnRows(sqlmock.NewRows([]string{"title", "tagId"}).AddRow("plumber", 3))
+	mock.ExpectExec("INSERT INTO ServiceTag").WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO ServiceAddOn").WithArgs(1, "Materials", 250.0).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := db.RegisterService(&request.NewService{
+		VendorId:           2,
+		Title:              "Pipe repair",
+		Description:        "description",
+		Rate:               100,
+		PricingUnit:        models.PRICING_UNIT_VISIT,
+		Currency:           "PHP",
+		AddOns:             []request.ServiceAddOn{{Title: "Materials", Price: 250}},
+		CancellationPolicy: models.CANCELLATION_POLICY_STRICT,
+		Tags:               []string{"plumber"},
+		GeoSpatialModel:    models.GeoSpatialModel{Latitude: 7.07, Longitude: 125.61},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestRegisterServiceRejectsUnknownTags(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO(.+)location").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("FROM Tag t(.+)UNION ALL(.+)FROM TagSynonym").
+		WithArgs("plumber", "astrologer", "plumber", "astrologer").
+		WillReturnRows(sqlmock.NewRows([]string{"title", "tagId"}).AddRow("plumber", 3))
+	mock.ExpectRollback()
+
+	_, err := db.RegisterService(&request.NewService{
+		VendorId:        2,
+		Description:     "description",
+		Rate:            100,
+		Tags:            []string{"plumber", "astrologer"},
+		GeoSpatialModel: models.GeoSpatialModel{Latitude: 7.07, Longitude: 125.61},
+	})
+
+	var unknown *models.UnknownTagsError
+	assert.ErrorAs(t, err, &unknown)
+	assert.Equal(t, []string{"astrologer"}, unknown.Titles)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGeoSpatialSearchWithoutKnownTags(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectQuery("FROM Tag t(.+)UNION ALL(.+)FROM TagSynonym").
+		WillReturnRows(sqlmock.NewRows([]string{"title", "tagId"}))
+
+	services, err := db.GeoSpatialSearch(&types.SearchParams{Latitude: 7.07, Longitude: 125.61, Radius: 500, Query: []string{"astrologer"}})
+
+	assert.NoError(t, err)
+	assert.Empty(t, services)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+// Runs against a migrated MySQL database, ex:
+// BENCHMARK_DB_DSN="user:pass@tcp(localhost:3306)/nearbyassist" go test -bench GeoSpatialSearch ./internal/db/mysql
+func BenchmarkGeoSpatialSearch(b *testing.B) {
+	dsn := os.Getenv("BENCHMARK_DB_DSN")
+	if dsn == "" {
+		b.Skip("BENCHMARK_DB_DSN is not set")
+	}
+
+	conn, err := sqlx.Connect("mysql", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	db := NewMysqlWithDb(conn)
+	defer db.Conn.Close()
+
+	seedServices(b, db, 20, 500)
+
+	params := &types.SearchParams{Latitude: 7.07, Longitude: 125.61, Radius: 2000, Query: []string{"benchmark-tag"}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GeoSpatialSearch(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Spreads vendors*perVendor services over roughly 40km around Davao City
+func seedServices(b *testing.B, db *Mysql, vendors, perVendor int) {
+	b.Helper()
+
+	cleanup := func() {
+		db.Conn.MustExec("DELETE st FROM ServiceTag st JOIN Tag t ON t.id = st.tagId WHERE t.title = 'benchmark-tag'")
+		db.Conn.MustExec("DELETE s FROM Service s JOIN User u ON u.id = s.vendorId WHERE u.email LIKE 'benchmark-%'")
+		db.Conn.MustExec("DELETE v FROM Vendor v JOIN User u ON u.id = v.vendorId WHERE u.email LIKE 'benchmark-%'")
+		db.Conn.MustExec("DELETE FROM User WHERE email LIKE 'benchmark-%'")
+		db.Conn.MustExec("DELETE FROM Tag WHERE title = 'benchmark-tag'")
+	}
+
+	cleanup()
+	b.Cleanup(cleanup)
+
+	tag := db.Conn.MustExec("INSERT INTO Tag (title) VALUES ('benchmark-tag')")
+	tagId, _ := tag.LastInsertId()
+
+	random := rand.New(rand.NewSource(1))
+
+	for v := 0; v < vendors; v++ {
+		user := db.Conn.MustExec("INSERT INTO User (name, email, emailHash) VALUES ('benchmark', ?, '')", fmt.Sprintf("benchmark-%d", v))
+		userId, _ := user.LastInsertId()
+		db.Conn.MustExec("INSERT INTO Vendor (vendorId, job) VALUES (?, 'benchmark')", userId)
+
+		values := make([]string, 0, perVendor)
+		args := make([]any, 0, perVendor*5)
+		for s := 0; s < perVendor; s++ {
+			lat := 7.07 + (random.Float64()-0.5)*0.4
+			long := 125.61 + (random.Float64()-0.5)*0.4
+
+			values = append(values, "(?, 'benchmark', 100, ?, ?, ST_GeomFromText(CONCAT('POINT(', ?, ' ', ?, ')'), 4326, 'axis-order=long-lat'))")
+			args = append(args, userId, lat, long, long, lat)
+		}
+
+		db.Conn.MustExec("INSERT INTO Service (vendorId, description, rate, latitude, longitude, location) VALUES "+strings.Join(values, ", "), args...)
+		db.Conn.MustExec("INSERT INTO ServiceTag (serviceId, tagId) SELECT id, ? FROM Service WHERE vendorId = ?", tagId, userId)
+	}
+}
+
+func TestFindServicesInBoundsTruncated(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "vendorId", "vendor", "latitude", "longitude"})
+	for i := 0; i <= MAP_SERVICE_LIMIT; i++ {
+		rows.AddRow(i+1, 2, "vendor", 7.07, 125.61)
+	}
+
+	mock.ExpectQuery("MBRContains(.+)LIMIT \\?").
+		WithArgs(boundsPolygon(7, 125.5, 7.2, 125.7), MAP_SERVICE_LIMIT+1).
+		WillReturnRows(rows)
+
+	services, truncated, err := db.FindServicesInBounds(&types.MapParams{MinLatitude: 7, MinLongitude: 125.5, MaxLatitude: 7.2, MaxLongitude: 125.7, Zoom: 12})
+
+	assert.NoError(t, err)
+	assert.Len(t, services, MAP_SERVICE_LIMIT)
+	assert.True(t, truncated)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFindServicesInBoundsWithoutTags(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "vendorId", "vendor", "latitude", "longitude"}).
+		AddRow(1, 2, "vendor", 7.07, 125.61)
+
+	mock.ExpectQuery("MBRContains(.+)LIMIT \\?").
+		WithArgs(boundsPolygon(7, 125.5, 7.2, 125.7), MAP_SERVICE_LIMIT+1).
+		WillReturnRows(rows)
+
+	services, truncated, err := db.FindServicesInBounds(&types.MapParams{MinLatitude: 7, MinLongitude: 125.5, MaxLatitude: 7.2, MaxLongitude: 125.7, Zoom: 12})
+
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.False(t, truncated)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -2,15 +2,24 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
-func (m *Mysql) FindAllTags() ([]models.TagModel, error) {
+func (m *Mysql) FindAllTags(includeRetired bool) ([]models.TagModel, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT id, title FROM Tag"
+	query := "SELECT id, title, categoryId, mergedInto, retiredAt, createdAt FROM Tag"
+	if !includeRetired {
+		query += " WHERE retiredAt IS NULL"
+	}
+	query += " ORDER BY title"
 
 	tags := make([]models.TagModel, 0)
 	err := m.Conn.SelectContext(ctx, &tags, query)
@@ -25,6 +34,24 @@ func (m *Mysql) FindAllTags() ([]models.TagModel, error) {
 	return tags, nil
 }
 
+func (m *Mysql) FindTagById(id int) (*models.TagModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, title, categoryId, mergedInto, retiredAt, createdAt FROM Tag WHERE id = ?"
+
+	tag := models.NewTagModel()
+	if err := m.Conn.GetContext(ctx, tag, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return tag, nil
+}
+
 func (m *Mysql) FindAllTagByServiceId(serviceId int) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
@@ -50,3 +77,391 @@ func (m *Mysql) FindAllTagByServiceId(serviceId int) ([]string, error) {
 
 	return tags, nil
 }
+
+func (m *Mysql) FindServiceTagLocations() ([]models.ServiceTagLocationModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            st.tagId,
+            s.id AS serviceId,
+            s.latitude,
+            s.longitude
+        FROM
+            ServiceTag st
+            JOIN Service s ON s.id = st.serviceId
+            JOIN Vendor v ON v.vendorId = s.vendorId AND v.restricted = 0
+    `
+
+	locations := make([]models.ServiceTagLocationModel, 0)
+	if err := m.Conn.SelectContext(ctx, &locations, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return locations, nil
+}
+
+func (m *Mysql) NewTag(tag *request.NewTag) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "INSERT INTO Tag (title, categoryId) VALUES (:title, :categoryId)"
+
+	res, err := m.Conn.NamedExecContext(ctx, query, tag)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) UpdateTag(tag *request.UpdateTag) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "UPDATE Tag SET title = :title, categoryId = :categoryId WHERE id = :id AND retiredAt IS NULL"
+
+	res, err := m.Conn.NamedExecContext(ctx, query, tag)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return errors.New("tag not found or already retired")
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Merging retires the source but keeps its title resolving to the target,
+// so vendors and searches using the old title land on the target
+func (m *Mysql) MergeTag(sourceId, targetId int) error {
+	return m.retireTag(sourceId, &targetId, true)
+}
+
+// Retiring moves services to the replacement, or untags them when there is
+// none. Unlike a merge, the retired title stops resolving
+func (m *Mysql) RetireTag(id int, replacementId *int) error {
+	return m.retireTag(id, replacementId, false)
+}
+
+func (m *Mysql) retireTag(id int, replacementId *int, alias bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	type statement struct {
+		query string
+		args  []any
+	}
+
+	statements := make([]statement, 0)
+	if replacementId != nil {
+		statements = append(statements,
+			// Services tagged with both would end up with a duplicate row
+			statement{
+				query: "DELETE st FROM ServiceTag st JOIN ServiceTag keep ON keep.serviceId = st.serviceId WHERE st.tagId = ? AND keep.tagId = ?",
+				args:  []any{id, *replacementId},
+			},
+			statement{query: "UPDATE ServiceTag SET tagId = ? WHERE tagId = ?", args: []any{*replacementId, id}},
+			statement{query: "UPDATE TagSynonym SET tagId = ? WHERE tagId = ?", args: []any{*replacementId, id}},
+		)
+	} else {
+		statements = append(statements, statement{query: "DELETE FROM ServiceTag WHERE tagId = ?", args: []any{id}})
+	}
+
+	// Tags previously merged into this one follow it to the replacement
+	statements = append(statements, statement{query: "UPDATE Tag SET mergedInto = ? WHERE mergedInto = ?", args: []any{replacementId, id}})
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	var mergedInto *int
+	if alias {
+		mergedInto = replacementId
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE Tag SET retiredAt = CURRENT_TIMESTAMP, mergedInto = ? WHERE id = ? AND retiredAt IS NULL", mergedInto, id)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return errors.New("tag not found or already retired")
+	}
+
+	if err := tx.Commit(); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) FindAllTagCategories() ([]models.TagCategoryModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, title, parentId, createdAt, updatedAt FROM TagCategory ORDER BY title"
+
+	categories := make([]models.TagCategoryModel, 0)
+	if err := m.Conn.SelectContext(ctx, &categories, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return categories, nil
+}
+
+func (m *Mysql) NewTagCategory(category *request.NewTagCategory) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "INSERT INTO TagCategory (title, parentId) VALUES (:title, :parentId)"
+
+	res, err := m.Conn.NamedExecContext(ctx, query, category)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) UpdateTagCategory(category *request.UpdateTagCategory) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "UPDATE TagCategory SET title = :title, parentId = :parentId WHERE id = :id"
+
+	if _, err := m.Conn.NamedExecContext(ctx, query, category); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Fails while subcategories still reference it; tags become uncategorized
+func (m *Mysql) DeleteTagCategory(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "DELETE FROM TagCategory WHERE id = ?"
+
+	if _, err := m.Conn.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) FindAllTagSynonyms() ([]models.TagSynonymModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, tagId, title, createdAt FROM TagSynonym ORDER BY title"
+
+	synonyms := make([]models.TagSynonymModel, 0)
+	if err := m.Conn.SelectContext(ctx, &synonyms, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return synonyms, nil
+}
+
+func (m *Mysql) NewTagSynonym(synonym *request.NewTagSynonym) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "INSERT INTO TagSynonym (tagId, title) VALUES (:tagId, :title)"
+
+	res, err := m.Conn.NamedExecContext(ctx, query, synonym)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) DeleteTagSynonym(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "DELETE FROM TagSynonym WHERE id = ?"
+
+	if _, err := m.Conn.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Returns the active tag ids the titles resolve to and the titles that
+// matched nothing
+func (m *Mysql) ResolveTags(titles []string) ([]int, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	ids, unknown, err := resolveTags(ctx, m.Conn, titles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, nil, context.DeadlineExceeded
+	}
+
+	return ids, unknown, nil
+}
+
+// A title resolves through an active tag, a synonym of an active tag, or a
+// tag that was merged into another. Titles compare case-insensitively
+func resolveTags(ctx context.Context, q sqlx.ExtContext, titles []string) ([]int, []string, error) {
+	if len(titles) == 0 {
+		return []int{}, []string{}, nil
+	}
+
+	query := `
+        SELECT
+            t.title,
+            COALESCE(t.mergedInto, t.id) AS tagId
+        FROM
+            Tag t
+        WHERE
+            t.title IN (?)
+            AND (t.retiredAt IS NULL OR t.mergedInto IS NOT NULL)
+        UNION ALL
+        SELECT
+            s.title,
+            s.tagId
+        FROM
+            TagSynonym s
+            JOIN Tag t ON t.id = s.tagId
+        WHERE
+            s.title IN (?)
+            AND t.retiredAt IS NULL
+    `
+
+	query, args, err := sqlx.In(query, titles, titles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]struct {
+		Title string `db:"title"`
+		TagId int    `db:"tagId"`
+	}, 0)
+	if err := sqlx.SelectContext(ctx, q, &rows, q.Rebind(query), args...); err != nil {
+		return nil, nil, err
+	}
+
+	resolved := make(map[string]int, len(rows))
+	for _, row := range rows {
+		resolved[strings.ToLower(row.Title)] = row.TagId
+	}
+
+	ids := make([]int, 0, len(titles))
+	unknown := make([]string, 0)
+	seen := make(map[int]bool, len(titles))
+	for _, title := range titles {
+		id, ok := resolved[strings.ToLower(title)]
+		if !ok {
+			unknown = append(unknown, title)
+			continue
+		}
+
+		// Two synonyms of the same tag should only tag a service once
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, unknown, nil
+}
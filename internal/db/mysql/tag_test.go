@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTags(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	// "Plumber" matches the synonym case-insensitively and "plumbing" is
+	// the canonical tag it points to
+	rows := sqlmock.NewRows([]string{"title", "tagId"}).
+		AddRow("plumbing", 3).
+		AddRow("plumber", 3)
+
+	mock.ExpectQuery("FROM Tag t(.+)UNION ALL(.+)FROM TagSynonym").
+		WithArgs("Plumber", "plumbing", "astrologer", "Plumber", "plumbing", "astrologer").
+		WillReturnRows(rows)
+
+	ids, unknown, err := db.ResolveTags([]string{"Plumber", "plumbing", "astrologer"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, ids)
+	assert.Equal(t, []string{"astrologer"}, unknown)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestMergeTag(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE st FROM ServiceTag st JOIN ServiceTag keep").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE ServiceTag SET tagId = \\? WHERE tagId = \\?").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("UPDATE TagSynonym SET tagId = \\? WHERE tagId = \\?").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE Tag SET mergedInto = \\? WHERE mergedInto = \\?").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE Tag SET retiredAt = CURRENT_TIMESTAMP, mergedInto = \\?").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.MergeTag(1, 2)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestRetireTagWithoutReplacement(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM ServiceTag WHERE tagId = \\?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE Tag SET mergedInto = \\? WHERE mergedInto = \\?").WithArgs(nil, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE Tag SET retiredAt = CURRENT_TIMESTAMP, mergedInto = \\?").WithArgs(nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.RetireTag(1, nil)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestRetireTagAlreadyRetired(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM ServiceTag").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE Tag SET mergedInto").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE Tag SET retiredAt").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := db.RetireTag(1, nil)
+
+	assert.Error(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"nearbyassist/internal/models"
 	"nearbyassist/internal/request"
 	"time"
@@ -39,20 +40,52 @@ func (m *Mysql) CreateTransaction(transaction *request.NewTransaction) (int, err
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+
 	query := `
         INSERT INTO
-            Transaction (vendorId, clientId, serviceId, start, end)
+            Transaction (vendorId, clientId, serviceId, start, end, price, currency, cancellationPolicy, cancellationWindows)
         VALUES
-            (:vendorId, :clientId, :serviceId, :start, :end)
+            (:vendorId, :clientId, :serviceId, :start, :end, :price, :currency, :cancellationPolicy, :cancellationWindows)
     `
 
-	res, err := m.Conn.NamedExecContext(ctx, query, transaction)
+	res, err := tx.NamedExecContext(ctx, query, transaction)
 	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return -1, err
+		}
+
 		return -1, err
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return -1, err
+		}
+
+		return -1, err
+	}
+
+	event := models.TransactionEvent{
+		TransactionId: int(id),
+		VendorId:      transaction.VendorId,
+		ClientId:      transaction.ClientId,
+		ServiceId:     transaction.ServiceId,
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_TRANSACTION_CREATED, int(id), event); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return -1, err
+		}
+
+		return -1, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return -1, err
 	}
 
@@ -205,9 +238,206 @@ func (m *Mysql) CompleteTransaction(id int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "UPDATE Transaction SET status = 'done' WHERE id = ?"
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// A cancelled booking must not release its escrow to the vendor
+	query := "UPDATE Transaction SET status = 'done', completedAt = CURRENT_TIMESTAMP WHERE id = ? AND status = 'ongoing'"
+
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return errors.New("only ongoing transactions can be completed")
+	}
+
+	event := models.TransactionEvent{TransactionId: id}
+	findParties := "SELECT vendorId, clientId, serviceId FROM Transaction WHERE id = ?"
+
+	if err := tx.QueryRowxContext(ctx, findParties, id).Scan(&event.VendorId, &event.ClientId, &event.ServiceId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_TRANSACTION_COMPLETED, id, event); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	// Bookings paid through the platform release escrow to the vendor. A hold
+	// that is still open is released by the provider's capture webhook
+	payment, err := lockPayment(ctx, tx, "transactionId = ?", id)
+	if err == nil && payment != nil && payment.Status == models.PAYMENT_STATUS_CAPTURED {
+		err = releasePayment(ctx, tx, payment)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Only ongoing transactions can be cancelled, so a booking that is cancelled
+// can no longer be completed and have its escrow released. The payment is
+// settled with the provider afterwards, see SettleCancelledPayment
+func (m *Mysql) CancelTransaction(cancellation *models.TransactionCancellationModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	id := cancellation.TransactionId
+	res, err := tx.ExecContext(ctx, "UPDATE Transaction SET status = 'cancelled' WHERE id = ? AND status = 'ongoing'", id)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return errors.New("only ongoing transactions can be cancelled")
+	}
+
+	// The terms were worked out before the transaction was locked, a payment
+	// confirmed since then would be left in escrow. One that is still pending
+	// is given back by ConfirmPayment once it sees the cancellation
+	payment, err := lockPayment(ctx, tx, "transactionId = ?", id)
+	if err == nil && payment != nil {
+		paid := payment.Status == models.PAYMENT_STATUS_AUTHORIZED || payment.Status == models.PAYMENT_STATUS_CAPTURED
+		if paid != cancellation.Paid {
+			err = models.ErrPaymentChanged
+		}
+	} else if err == nil && cancellation.Paid {
+		err = models.ErrPaymentChanged
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	event := models.TransactionEvent{TransactionId: id}
+	findParties := "SELECT vendorId, clientId, serviceId FROM Transaction WHERE id = ?"
+
+	if err := tx.QueryRowxContext(ctx, findParties, id).Scan(&event.VendorId, &event.ClientId, &event.ServiceId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	record := `
+        INSERT INTO
+            TransactionCancellation (transactionId, cancelledBy, role, hoursBefore, penaltyPercent, penalty, refund, reason)
+        VALUES
+            (:transactionId, :cancelledBy, :role, :hoursBefore, :penaltyPercent, :penalty, :refund, :reason)
+    `
+
+	if _, err := tx.NamedExecContext(ctx, record, cancellation); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_TRANSACTION_CANCELLED, id, event); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Records what the provider did with the payment of a cancelled booking. The
+// refund is issued with the provider before this is called, the ledger and
+// payment only record it. A hold that had to be captured to collect a penalty
+// is recorded as a charge first. Payments that were already settled are left
+// alone, so this is safe to repeat
+func (m *Mysql) SettleCancelledPayment(transactionId int, penalty float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	payment, err := lockPayment(ctx, tx, "transactionId = ?", transactionId)
+	if err == nil && payment != nil && payment.Status == models.PAYMENT_STATUS_AUTHORIZED && penalty > 0 {
+		if err = capturePayment(ctx, tx, payment); err == nil {
+			payment.Status = models.PAYMENT_STATUS_CAPTURED
+		}
+	}
+
+	if err == nil && payment != nil && (payment.Status == models.PAYMENT_STATUS_AUTHORIZED || payment.Status == models.PAYMENT_STATUS_CAPTURED) {
+		err = refundPayment(ctx, tx, payment, penalty)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
 
-	if _, err := m.Conn.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
@@ -217,3 +447,67 @@ func (m *Mysql) CompleteTransaction(id int) error {
 
 	return nil
 }
+
+func (m *Mysql) FindTransactionCancellation(transactionId int) (*models.TransactionCancellationModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            transactionId, cancelledBy, role, hoursBefore, penaltyPercent, penalty, refund, reason, createdAt
+        FROM
+            TransactionCancellation
+        WHERE
+            transactionId = ?
+    `
+
+	cancellation := &models.TransactionCancellationModel{}
+	if err := m.Conn.GetContext(ctx, cancellation, query, transactionId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return cancellation, nil
+}
+
+// Bookings that have not started yet are left out, they can still go either
+// way
+func (m *Mysql) FindReliability(userId int, role models.CancellationRole) (*models.ReliabilityModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	party := "t.clientId"
+	if role == models.CANCELLATION_ROLE_VENDOR {
+		party = "t.vendorId"
+	}
+
+	query := `
+        SELECT
+            COUNT(t.id) AS bookings,
+            COALESCE(SUM(t.status = 'done'), 0) AS completed,
+            COALESCE(SUM(c.cancelledBy = ?), 0) AS cancellations,
+            COALESCE(SUM(c.cancelledBy = ? AND c.penaltyPercent > 0), 0) AS lateCancellations
+        FROM
+            Transaction t
+            LEFT JOIN TransactionCancellation c ON c.transactionId = t.id
+        WHERE
+            ` + party + ` = ?
+            AND (t.status != 'ongoing' OR t.start < NOW())
+    `
+
+	reliability := &models.ReliabilityModel{}
+	if err := m.Conn.GetContext(ctx, reliability, query, userId, userId, userId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	reliability.Calculate()
+
+	return reliability, nil
+}
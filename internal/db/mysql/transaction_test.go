@@ -49,3 +49,24 @@ func TestCountTransaction(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestCancelTransactionPaidSinceTermsWereWorkedOut(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	payment := sqlmock.NewRows(paymentRows).AddRow(1, 5, 3, 2, "fake", "fake_ch_5_1", 1500.0, "PHP", "captured", "", "")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Transaction SET status = 'cancelled'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM Payment WHERE transactionId = \\? FOR UPDATE").WithArgs(5).WillReturnRows(payment)
+	mock.ExpectRollback()
+
+	err := db.CancelTransaction(&models.TransactionCancellationModel{TransactionId: 5, CancelledBy: 3, Role: models.CANCELLATION_ROLE_CLIENT})
+
+	assert.ErrorIs(t, err, models.ErrPaymentChanged)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -62,7 +62,11 @@ func (m *Mysql) FindVendorByService(id int) (*response.ServiceVendorDetails, err
             v.rating,
             v.job,
             u.name as vendor,
-            u.imageUrl as imageUrl
+            u.imageUrl as imageUrl,
+            EXISTS(
+                SELECT 1 FROM IdentityVerification iv
+                WHERE iv.user = v.vendorId AND iv.status = 'approved'
+            ) as verified
         FROM
             Vendor v
             JOIN Service s ON s.vendorId = v.vendorId
@@ -83,39 +87,3 @@ func (m *Mysql) FindVendorByService(id int) (*response.ServiceVendorDetails, err
 
 	return vendor, nil
 }
-
-func (m *Mysql) RestrictVendor(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
-	query := "UPDATE Vendor SET restricted = 1 WHERE vendorId = ?"
-
-	_, err := m.Conn.ExecContext(ctx, query, id)
-	if err != nil {
-		return err
-	}
-
-	if ctx.Err() == context.DeadlineExceeded {
-		return context.DeadlineExceeded
-	}
-
-	return nil
-}
-
-func (m *Mysql) UnrestrictVendor(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
-	query := "UPDATE Vendor SET restricted = 0 WHERE vendorId = ?"
-
-	_, err := m.Conn.ExecContext(ctx, query, id)
-	if err != nil {
-		return err
-	}
-
-	if ctx.Err() == context.DeadlineExceeded {
-		return context.DeadlineExceeded
-	}
-
-	return nil
-}
@@ -0,0 +1,362 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/response"
+	"time"
+)
+
+func (m *Mysql) CountVendorComplaint(status models.VendorComplaintStatus) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT COUNT(*) FROM VendorComplaint"
+
+	args := make([]interface{}, 0)
+	if status != "" && status != models.VENDOR_COMPLAINT_STATUS_ALL {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+
+	count := 0
+	if err := m.Conn.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return count, nil
+}
+
+// The complaint and its evidence are stored together, a complaint is never
+// left without the photos it was filed with
+func (m *Mysql) FileVendorComplaint(complaint *request.NewComplaint, evidence []string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+        INSERT INTO 
+            VendorComplaint (complainantId, vendorId, transactionId, title, content)
+        VALUES
+            (:complainantId, :vendorId, :transactionId, :title, :content)
+    `
+
+	res, err := tx.NamedExecContext(ctx, query, complaint)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	for _, url := range evidence {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO VendorComplaintEvidence (complaintId, url) VALUES (?, ?)", id, url); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return 0, err
+			}
+
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) FindAllVendorComplaints(status models.VendorComplaintStatus) ([]*response.VendorComplaint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, complainantId, vendorId, transactionId, title, status, assignedTo, createdAt
+        FROM
+            VendorComplaint
+    `
+
+	args := make([]interface{}, 0)
+	if status != "" && status != models.VENDOR_COMPLAINT_STATUS_ALL {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+
+	query += " ORDER BY createdAt"
+
+	complaints := make([]*response.VendorComplaint, 0)
+	if err := m.Conn.SelectContext(ctx, &complaints, query, args...); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return complaints, nil
+}
+
+func (m *Mysql) FindVendorComplaintsByComplainant(userId int) ([]*response.VendorComplaint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, complainantId, vendorId, transactionId, title, status, assignedTo, createdAt
+        FROM
+            VendorComplaint
+        WHERE
+            complainantId = ?
+        ORDER BY
+            createdAt DESC
+    `
+
+	complaints := make([]*response.VendorComplaint, 0)
+	if err := m.Conn.SelectContext(ctx, &complaints, query, userId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return complaints, nil
+}
+
+func (m *Mysql) FindVendorComplaintById(id int) (*models.VendorComplaintModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id,
+            complainantId,
+            vendorId,
+            transactionId,
+            title,
+            content,
+            status,
+            assignedTo,
+            resolution,
+            COALESCE(resolutionNote, '') AS resolutionNote,
+            resolvedAt,
+            createdAt,
+            updatedAt
+        FROM
+            VendorComplaint
+        WHERE
+            id = ?
+    `
+
+	complaint := models.NewVendorComplaintModel()
+	if err := m.Conn.GetContext(ctx, complaint, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return complaint, nil
+}
+
+func (m *Mysql) FindVendorComplaintEvidence(id int) ([]models.VendorComplaintEvidenceModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, complaintId, url, createdAt, updatedAt FROM VendorComplaintEvidence WHERE complaintId = ?"
+
+	evidence := make([]models.VendorComplaintEvidenceModel, 0)
+	if err := m.Conn.SelectContext(ctx, &evidence, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return evidence, nil
+}
+
+func (m *Mysql) AssignVendorComplaint(id, staffId int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        UPDATE
+            VendorComplaint
+        SET
+            assignedTo = ?,
+            status = 'under_review'
+        WHERE
+            id = ? AND status IN ('open', 'under_review')
+    `
+
+	res, err := m.Conn.ExecContext(ctx, query, staffId, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return errors.New("Complaint not found or already closed")
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) NewVendorComplaintComment(model *models.VendorComplaintCommentModel) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            VendorComplaintComment (complaintId, adminId, comment)
+        VALUES
+            (:complaintId, :adminId, :comment)
+    `
+
+	res, err := m.Conn.NamedExecContext(ctx, query, model)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) FindVendorComplaintComments(id int) ([]models.VendorComplaintCommentModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, complaintId, adminId, comment, createdAt, updatedAt
+        FROM
+            VendorComplaintComment
+        WHERE
+            complaintId = ?
+        ORDER BY
+            createdAt
+    `
+
+	comments := make([]models.VendorComplaintCommentModel, 0)
+	if err := m.Conn.SelectContext(ctx, &comments, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return comments, nil
+}
+
+func (m *Mysql) ResolveVendorComplaint(resolution *request.ResolveComplaint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	updateComplaint := `
+        UPDATE
+            VendorComplaint
+        SET
+            status = :status,
+            resolution = :resolution,
+            resolutionNote = :note,
+            resolvedAt = CURRENT_TIMESTAMP
+        WHERE
+            id = :id AND status IN ('open', 'under_review')
+    `
+
+	res, err := tx.NamedExecContext(ctx, updateComplaint, resolution)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return errors.New("Complaint not found or already closed")
+	}
+
+	if resolution.Resolution == models.COMPLAINT_RESOLUTION_RESTRICTION {
+		restriction := &request.RestrictVendor{
+			VendorId:     resolution.VendorId,
+			Reason:       resolution.Note,
+			DurationDays: resolution.RestrictionDays,
+			IssuedBy:     resolution.AdminId,
+			ComplaintId:  &resolution.Id,
+		}
+
+		if _, err := insertRestriction(ctx, tx, restriction); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
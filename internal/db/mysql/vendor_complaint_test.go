@@ -0,0 +1,135 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountVendorComplaint(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"count"}).AddRow(3)
+
+	query := "SELECT COUNT\\(\\*\\) FROM VendorComplaint WHERE status = \\?"
+	mock.ExpectQuery(query).WithArgs(models.VENDOR_COMPLAINT_STATUS_OPEN).WillReturnRows(rows)
+
+	count, err := db.CountVendorComplaint(models.VENDOR_COMPLAINT_STATUS_OPEN)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFileVendorComplaintWithEvidence(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO(.+)VendorComplaint \\(").WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO VendorComplaintEvidence").WithArgs(4, "a.jpg").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO VendorComplaintEvidence").WithArgs(4, "b.jpg").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	id, err := db.FileVendorComplaint(&request.NewComplaint{ComplainantId: 1, VendorId: 2, TransactionId: 3, Title: "title", Content: "content"}, []string{"a.jpg", "b.jpg"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestResolveVendorComplaintWithRestriction(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE VendorComplaint").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT vendorId FROM Vendor WHERE vendorId = \\? FOR UPDATE").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"vendorId"}).AddRow(2))
+	mock.ExpectQuery("SELECT EXISTS(.+)VendorRestriction").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("INSERT INTO VendorRestriction").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE Vendor SET restricted = 1 WHERE vendorId = \\?").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_VENDOR_RESTRICTED, 2, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := db.ResolveVendorComplaint(&request.ResolveComplaint{
+		Id:         1,
+		VendorId:   2,
+		Status:     models.VENDOR_COMPLAINT_STATUS_RESOLVED,
+		Resolution: models.COMPLAINT_RESOLUTION_RESTRICTION,
+		Note:       "note",
+	})
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestResolveVendorComplaintRestrictionOnRestrictedVendor(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE VendorComplaint").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT vendorId FROM Vendor WHERE vendorId = \\? FOR UPDATE").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"vendorId"}).AddRow(2))
+	mock.ExpectQuery("SELECT EXISTS(.+)VendorRestriction").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err := db.ResolveVendorComplaint(&request.ResolveComplaint{
+		Id:         1,
+		VendorId:   2,
+		Status:     models.VENDOR_COMPLAINT_STATUS_RESOLVED,
+		Resolution: models.COMPLAINT_RESOLUTION_RESTRICTION,
+		Note:       "note",
+	})
+
+	assert.ErrorIs(t, err, models.ErrVendorAlreadyRestricted)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestResolveVendorComplaintAlreadyClosed(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE VendorComplaint").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := db.ResolveVendorComplaint(&request.ResolveComplaint{
+		Id:         1,
+		VendorId:   2,
+		Status:     models.VENDOR_COMPLAINT_STATUS_DISMISSED,
+		Resolution: models.COMPLAINT_RESOLUTION_NONE,
+		Note:       "note",
+	})
+
+	assert.Error(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,571 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Records the restriction and flags the vendor, the flag is what search,
+// bookings, service management and chat check against
+func insertRestriction(ctx context.Context, tx *sqlx.Tx, restriction *request.RestrictVendor) (int, error) {
+	// Locking the vendor keeps two admins from stacking restrictions at once
+	vendorId := 0
+	if err := tx.GetContext(ctx, &vendorId, "SELECT vendorId FROM Vendor WHERE vendorId = ? FOR UPDATE", restriction.VendorId); err != nil {
+		return 0, err
+	}
+
+	active := false
+	findActive := "SELECT EXISTS(SELECT 1 FROM VendorRestriction WHERE vendorId = ? AND liftedAt IS NULL)"
+	if err := tx.GetContext(ctx, &active, findActive, restriction.VendorId); err != nil {
+		return 0, err
+	}
+
+	if active {
+		return 0, models.ErrVendorAlreadyRestricted
+	}
+
+	query := `
+        INSERT INTO
+            VendorRestriction (vendorId, reason, issuedBy, complaintId, expiresAt)
+        VALUES
+            (
+                :vendorId, :reason, :issuedBy, :complaintId,
+                IF(:durationDays > 0, DATE_ADD(CURRENT_TIMESTAMP, INTERVAL :durationDays DAY), NULL)
+            )
+    `
+
+	res, err := tx.NamedExecContext(ctx, query, restriction)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Vendor SET restricted = 1 WHERE vendorId = ?", restriction.VendorId); err != nil {
+		return 0, err
+	}
+
+	event := models.VendorRestrictedEvent{
+		RestrictionId: int(id),
+		VendorId:      restriction.VendorId,
+		ComplaintId:   restriction.ComplaintId,
+		DurationDays:  restriction.DurationDays,
+	}
+
+	if err := insertOutboxEvent(ctx, tx, models.EVENT_VENDOR_RESTRICTED, restriction.VendorId, event); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Clears the flag only when the vendor has no other active restriction left
+func unflagVendor(ctx context.Context, tx *sqlx.Tx, vendorId int) error {
+	query := `
+        UPDATE
+            Vendor
+        SET
+            restricted = 0
+        WHERE
+            vendorId = ?
+            AND NOT EXISTS (
+                SELECT 1 FROM VendorRestriction WHERE vendorId = ? AND liftedAt IS NULL
+            )
+    `
+
+	_, err := tx.ExecContext(ctx, query, vendorId, vendorId)
+	return err
+}
+
+func (m *Mysql) RestrictVendor(restriction *request.RestrictVendor) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := insertRestriction(ctx, tx, restriction)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return id, nil
+}
+
+func (m *Mysql) UnrestrictVendor(id, adminId int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	liftRestrictions := `
+        UPDATE
+            VendorRestriction
+        SET
+            liftedAt = CURRENT_TIMESTAMP,
+            liftedBy = ?
+        WHERE
+            vendorId = ? AND liftedAt IS NULL
+    `
+
+	if _, err := tx.ExecContext(ctx, liftRestrictions, adminId, id); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE Vendor SET restricted = 0 WHERE vendorId = ?", id); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) IsVendorRestricted(vendorId int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT EXISTS(SELECT 1 FROM Vendor WHERE vendorId = ? AND restricted = 1)"
+
+	restricted := false
+	if err := m.Conn.GetContext(ctx, &restricted, query, vendorId); err != nil {
+		return false, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, context.DeadlineExceeded
+	}
+
+	return restricted, nil
+}
+
+// Used to check both ends of a chat in one round trip
+func (m *Mysql) IsAnyVendorRestricted(vendorIds ...int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query, args, err := sqlx.In("SELECT EXISTS(SELECT 1 FROM Vendor WHERE vendorId IN (?) AND restricted = 1)", vendorIds)
+	if err != nil {
+		return false, err
+	}
+
+	restricted := false
+	if err := m.Conn.GetContext(ctx, &restricted, m.Conn.Rebind(query), args...); err != nil {
+		return false, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, context.DeadlineExceeded
+	}
+
+	return restricted, nil
+}
+
+func (m *Mysql) FindActiveRestriction(vendorId int) (*models.VendorRestrictionModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, vendorId, COALESCE(reason, '') AS reason, issuedBy, complaintId, startsAt, expiresAt, liftedAt, liftedBy, createdAt
+        FROM
+            VendorRestriction
+        WHERE
+            vendorId = ? AND liftedAt IS NULL
+        ORDER BY
+            startsAt DESC, id DESC
+        LIMIT 1
+    `
+
+	restriction := &models.VendorRestrictionModel{}
+	if err := m.Conn.GetContext(ctx, restriction, query, vendorId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return restriction, nil
+}
+
+func (m *Mysql) FindRestrictionById(id int) (*models.VendorRestrictionModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, vendorId, COALESCE(reason, '') AS reason, issuedBy, complaintId, startsAt, expiresAt, liftedAt, liftedBy, createdAt
+        FROM
+            VendorRestriction
+        WHERE
+            id = ?
+    `
+
+	restriction := &models.VendorRestrictionModel{}
+	if err := m.Conn.GetContext(ctx, restriction, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return restriction, nil
+}
+
+func (m *Mysql) FindVendorRestrictions(vendorId int) ([]models.VendorRestrictionModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, vendorId, COALESCE(reason, '') AS reason, issuedBy, complaintId, startsAt, expiresAt, liftedAt, liftedBy, createdAt
+        FROM
+            VendorRestriction
+        WHERE
+            vendorId = ?
+        ORDER BY
+            startsAt DESC, id DESC
+    `
+
+	restrictions := make([]models.VendorRestrictionModel, 0)
+	if err := m.Conn.SelectContext(ctx, &restrictions, query, vendorId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return restrictions, nil
+}
+
+// Lifts every restriction past its expiry and returns how many were lifted
+func (m *Mysql) LiftExpiredRestrictions() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	liftExpired := `
+        UPDATE
+            VendorRestriction
+        SET
+            liftedAt = CURRENT_TIMESTAMP
+        WHERE
+            liftedAt IS NULL AND expiresAt IS NOT NULL AND expiresAt <= CURRENT_TIMESTAMP
+    `
+
+	res, err := tx.ExecContext(ctx, liftExpired)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	lifted, err := res.RowsAffected()
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	unflagVendors := `
+        UPDATE
+            Vendor v
+        SET
+            v.restricted = 0
+        WHERE
+            v.restricted = 1
+            AND NOT EXISTS (
+                SELECT 1 FROM VendorRestriction r WHERE r.vendorId = v.vendorId AND r.liftedAt IS NULL
+            )
+    `
+
+	if lifted > 0 {
+		if _, err := tx.ExecContext(ctx, unflagVendors); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return 0, err
+			}
+
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(lifted), nil
+}
+
+func (m *Mysql) NewRestrictionAppeal(appeal *request.NewAppeal) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            VendorRestrictionAppeal (restrictionId, vendorId, content)
+        VALUES
+            (:restrictionId, :vendorId, :content)
+    `
+
+	res, err := m.Conn.NamedExecContext(ctx, query, appeal)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) HasPendingAppeal(restrictionId int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT EXISTS(SELECT 1 FROM VendorRestrictionAppeal WHERE restrictionId = ? AND status = 'pending')"
+
+	pending := false
+	if err := m.Conn.GetContext(ctx, &pending, query, restrictionId); err != nil {
+		return false, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, context.DeadlineExceeded
+	}
+
+	return pending, nil
+}
+
+func (m *Mysql) FindAllRestrictionAppeals(status models.AppealStatus) ([]models.VendorRestrictionAppealModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, restrictionId, vendorId, content, status, reviewedBy,
+            COALESCE(reviewNote, '') AS reviewNote, reviewedAt, createdAt
+        FROM
+            VendorRestrictionAppeal
+    `
+	args := make([]any, 0)
+
+	switch status {
+	case models.APPEAL_STATUS_PENDING, models.APPEAL_STATUS_ACCEPTED, models.APPEAL_STATUS_REJECTED:
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+
+	query += " ORDER BY createdAt ASC"
+
+	appeals := make([]models.VendorRestrictionAppealModel, 0)
+	if err := m.Conn.SelectContext(ctx, &appeals, query, args...); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return appeals, nil
+}
+
+func (m *Mysql) FindRestrictionAppealsByVendor(vendorId int) ([]models.VendorRestrictionAppealModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, restrictionId, vendorId, content, status, reviewedBy,
+            COALESCE(reviewNote, '') AS reviewNote, reviewedAt, createdAt
+        FROM
+            VendorRestrictionAppeal
+        WHERE
+            vendorId = ?
+        ORDER BY
+            createdAt DESC
+    `
+
+	appeals := make([]models.VendorRestrictionAppealModel, 0)
+	if err := m.Conn.SelectContext(ctx, &appeals, query, vendorId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return appeals, nil
+}
+
+func (m *Mysql) FindRestrictionAppealById(id int) (*models.VendorRestrictionAppealModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, restrictionId, vendorId, content, status, reviewedBy,
+            COALESCE(reviewNote, '') AS reviewNote, reviewedAt, createdAt
+        FROM
+            VendorRestrictionAppeal
+        WHERE
+            id = ?
+    `
+
+	appeal := &models.VendorRestrictionAppealModel{}
+	if err := m.Conn.GetContext(ctx, appeal, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return appeal, nil
+}
+
+// Closes a pending appeal, accepting it also lifts the appealed restriction
+func (m *Mysql) ReviewRestrictionAppeal(review *request.ReviewAppeal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	updateAppeal := `
+        UPDATE
+            VendorRestrictionAppeal
+        SET
+            status = :status,
+            reviewNote = :note,
+            reviewedBy = :reviewedBy,
+            reviewedAt = CURRENT_TIMESTAMP
+        WHERE
+            id = :id AND status = 'pending'
+    `
+
+	res, err := tx.NamedExecContext(ctx, updateAppeal, review)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return errors.New("Appeal not found or already reviewed")
+	}
+
+	if review.Status == models.APPEAL_STATUS_ACCEPTED {
+		appeal := &models.VendorRestrictionAppealModel{}
+		if err := tx.GetContext(ctx, appeal, "SELECT restrictionId, vendorId FROM VendorRestrictionAppeal WHERE id = ?", review.Id); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+
+		liftRestriction := `
+            UPDATE
+                VendorRestriction
+            SET
+                liftedAt = CURRENT_TIMESTAMP,
+                liftedBy = ?
+            WHERE
+                id = ? AND liftedAt IS NULL
+        `
+
+		if _, err := tx.ExecContext(ctx, liftRestriction, review.ReviewedBy, appeal.RestrictionId); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+
+		if err := unflagVendor(ctx, tx, appeal.VendorId); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
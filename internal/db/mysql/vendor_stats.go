@@ -0,0 +1,309 @@
+package mysql
+
+import (
+	"context"
+	"nearbyassist/internal/models"
+	"time"
+)
+
+// Adds the counters to the daily totals, counters are batched by the
+// activity recorder so this runs every few seconds at most
+func (m *Mysql) RecordActivity(counters []models.ActivityCounterModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO
+            ActivityDaily (subject, subjectId, day, views, impressions)
+        VALUES
+            (:subject, :subjectId, :day, :views, :impressions)
+        ON DUPLICATE KEY UPDATE
+            views = views + VALUES(views),
+            impressions = impressions + VALUES(impressions)
+    `
+
+	for _, counter := range counters {
+		if _, err := tx.NamedExecContext(ctx, query, counter); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Periods run from the start of from up to, not including, the start of until
+func (m *Mysql) FindVendorServiceStats(vendorId int, from, until string) ([]models.ServiceStatsModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            s.id AS serviceId,
+            s.title,
+            s.currency,
+            COALESCE(a.views, 0) AS views,
+            COALESCE(a.impressions, 0) AS impressions,
+            COALESCE(b.bookings, 0) AS bookings,
+            COALESCE(c.completedJobs, 0) AS completedJobs,
+            COALESCE(r.ratingSum, 0) AS ratingSum,
+            COALESCE(r.ratingCount, 0) AS ratingCount
+        FROM
+            Service s
+            LEFT JOIN (
+                SELECT subjectId, SUM(views) AS views, SUM(impressions) AS impressions
+                FROM ActivityDaily
+                WHERE subject = 'service' AND day >= ? AND day < ?
+                GROUP BY subjectId
+            ) a ON a.subjectId = s.id
+            LEFT JOIN (
+                SELECT serviceId, COUNT(*) AS bookings
+                FROM Transaction
+                WHERE vendorId = ? AND createdAt >= ? AND createdAt < ?
+                GROUP BY serviceId
+            ) b ON b.serviceId = s.id
+            LEFT JOIN (
+                SELECT serviceId, COUNT(*) AS completedJobs
+                FROM Transaction
+                WHERE vendorId = ? AND status = 'done' AND COALESCE(completedAt, updatedAt) >= ? AND COALESCE(completedAt, updatedAt) < ?
+                GROUP BY serviceId
+            ) c ON c.serviceId = s.id
+            LEFT JOIN (
+                SELECT serviceId, SUM(rating) AS ratingSum, COUNT(*) AS ratingCount
+                FROM Review
+                WHERE createdAt >= ? AND createdAt < ?
+                GROUP BY serviceId
+            ) r ON r.serviceId = s.id
+        WHERE
+            s.vendorId = ?
+        ORDER BY
+            s.id
+    `
+
+	args := []interface{}{from, until, vendorId, from, until, vendorId, from, until, from, until, vendorId}
+
+	services := make([]models.ServiceStatsModel, 0)
+	if err := m.Conn.SelectContext(ctx, &services, query, args...); err != nil {
+		return nil, err
+	}
+
+	// Quotes are priced in their own currency, which need not be the service's
+	earningsQuery := `
+        SELECT
+            serviceId,
+            currency,
+            COALESCE(SUM(price), 0) AS amount
+        FROM
+            Transaction
+        WHERE
+            vendorId = ?
+            AND status = 'done'
+            AND currency IS NOT NULL
+            AND COALESCE(completedAt, updatedAt) >= ? AND COALESCE(completedAt, updatedAt) < ?
+        GROUP BY
+            serviceId, currency
+        ORDER BY
+            serviceId, currency
+    `
+
+	earnings := make([]struct {
+		ServiceId int     `db:"serviceId"`
+		Currency  string  `db:"currency"`
+		Amount    float64 `db:"amount"`
+	}, 0)
+	if err := m.Conn.SelectContext(ctx, &earnings, earningsQuery, vendorId, from, until); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	index := make(map[int]*models.ServiceStatsModel)
+	for i := range services {
+		index[services[i].ServiceId] = &services[i]
+	}
+
+	for _, row := range earnings {
+		if service, ok := index[row.ServiceId]; ok {
+			service.Earnings = append(service.Earnings, models.EarningModel{Currency: row.Currency, Amount: models.RoundMoney(row.Amount)})
+		}
+	}
+
+	for i := range services {
+		services[i].Calculate()
+	}
+
+	return services, nil
+}
+
+// Chats are not tied to a service, so inquiries are only counted per vendor.
+// The response time is how long the first reply to a new conversation took
+func (m *Mysql) FindVendorInquiryStats(vendorId int, from, until string) (*models.InquiryStatsModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            COUNT(*) AS inquiries,
+            COALESCE(SUM(EXISTS(
+                SELECT 1 FROM Transaction t
+                WHERE t.vendorId = ? AND t.clientId = i.clientId AND t.createdAt >= i.firstMessage
+            )), 0) AS converted,
+            AVG(TIMESTAMPDIFF(SECOND, i.firstMessage, (
+                SELECT MIN(r.createdAt) FROM Message r
+                WHERE r.sender = ? AND r.receiver = i.clientId AND r.createdAt >= i.firstMessage
+            ))) AS averageResponseSeconds
+        FROM (
+            SELECT sender AS clientId, MIN(createdAt) AS firstMessage
+            FROM Message
+            WHERE receiver = ?
+            GROUP BY sender
+            HAVING firstMessage >= ? AND firstMessage < ?
+        ) i
+    `
+
+	inquiries := &models.InquiryStatsModel{}
+	if err := m.Conn.GetContext(ctx, inquiries, query, vendorId, vendorId, vendorId, from, until); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	inquiries.Calculate()
+
+	return inquiries, nil
+}
+
+// One point for every bucket of the period, including the quiet ones
+func (m *Mysql) FindVendorStatsTrend(vendorId int, from, to time.Time, bucket models.AnalyticsBucket) ([]models.VendorStatsPointModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	start, until := from.Format(time.DateOnly), to.AddDate(0, 0, 1).Format(time.DateOnly)
+
+	activityQuery := `
+        SELECT
+            ` + analyticsBucketExpression(bucket, "a.day") + ` AS bucket,
+            COALESCE(SUM(CASE WHEN a.subject = 'vendor' THEN a.views END), 0) AS profileViews,
+            COALESCE(SUM(CASE WHEN a.subject = 'service' THEN a.views END), 0) AS serviceViews,
+            COALESCE(SUM(a.impressions), 0) AS impressions
+        FROM
+            ActivityDaily a
+            LEFT JOIN Service s ON a.subject = 'service' AND s.id = a.subjectId
+        WHERE
+            ((a.subject = 'vendor' AND a.subjectId = ?) OR s.vendorId = ?)
+            AND a.day >= ? AND a.day < ?
+        GROUP BY
+            bucket
+    `
+
+	activity := make([]models.VendorStatsPointModel, 0)
+	if err := m.Conn.SelectContext(ctx, &activity, activityQuery, vendorId, vendorId, start, until); err != nil {
+		return nil, err
+	}
+
+	jobsQuery := `
+        SELECT
+            ` + analyticsBucketExpression(bucket, "DATE(COALESCE(completedAt, updatedAt))") + ` AS bucket,
+            COALESCE(currency, '') AS currency,
+            COUNT(*) AS completedJobs,
+            COALESCE(SUM(price), 0) AS amount
+        FROM
+            Transaction
+        WHERE
+            vendorId = ?
+            AND status = 'done'
+            AND COALESCE(completedAt, updatedAt) >= ? AND COALESCE(completedAt, updatedAt) < ?
+        GROUP BY
+            bucket, currency
+    `
+
+	jobs := make([]struct {
+		Bucket        string  `db:"bucket"`
+		Currency      string  `db:"currency"`
+		CompletedJobs int     `db:"completedJobs"`
+		Amount        float64 `db:"amount"`
+	}, 0)
+	if err := m.Conn.SelectContext(ctx, &jobs, jobsQuery, vendorId, start, until); err != nil {
+		return nil, err
+	}
+
+	ratingsQuery := `
+        SELECT
+            ` + analyticsBucketExpression(bucket, "DATE(r.createdAt)") + ` AS bucket,
+            SUM(r.rating) AS ratingSum,
+            COUNT(*) AS ratingCount
+        FROM
+            Review r
+            JOIN Service s ON s.id = r.serviceId
+        WHERE
+            s.vendorId = ?
+            AND r.createdAt >= ? AND r.createdAt < ?
+        GROUP BY
+            bucket
+    `
+
+	ratings := make([]models.VendorStatsPointModel, 0)
+	if err := m.Conn.SelectContext(ctx, &ratings, ratingsQuery, vendorId, start, until); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	buckets := models.AnalyticsBuckets(from, to, bucket)
+	points := make([]models.VendorStatsPointModel, len(buckets))
+	index := make(map[string]*models.VendorStatsPointModel)
+	for i, key := range buckets {
+		points[i].Bucket = key
+		index[key] = &points[i]
+	}
+
+	for _, row := range activity {
+		if point, ok := index[row.Bucket]; ok {
+			point.ProfileViews, point.ServiceViews, point.Impressions = row.ProfileViews, row.ServiceViews, row.Impressions
+		}
+	}
+
+	for _, row := range jobs {
+		if point, ok := index[row.Bucket]; ok {
+			point.CompletedJobs += row.CompletedJobs
+			// Bookings made before prices were recorded earn nothing here
+			if row.Currency != "" {
+				point.Earnings = append(point.Earnings, models.EarningModel{Currency: row.Currency, Amount: models.RoundMoney(row.Amount)})
+			}
+		}
+	}
+
+	for _, row := range ratings {
+		if point, ok := index[row.Bucket]; ok {
+			point.RatingSum, point.RatingCount = row.RatingSum, row.RatingCount
+		}
+	}
+
+	for i := range points {
+		points[i].Calculate()
+	}
+
+	return points, nil
+}
@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordActivity(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO(.+)ActivityDaily(.+)ON DUPLICATE KEY UPDATE").
+		WithArgs(models.ACTIVITY_SUBJECT_SERVICE, 4, "2026-10-18", 2, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO(.+)ActivityDaily(.+)ON DUPLICATE KEY UPDATE").
+		WithArgs(models.ACTIVITY_SUBJECT_VENDOR, 2, "2026-10-18", 1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.RecordActivity([]models.ActivityCounterModel{
+		{Subject: models.ACTIVITY_SUBJECT_SERVICE, SubjectId: 4, Day: "2026-10-18", Views: 2, Impressions: 7},
+		{Subject: models.ACTIVITY_SUBJECT_VENDOR, SubjectId: 2, Day: "2026-10-18", Views: 1},
+	})
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFindVendorServiceStatsKeepsEarningsPerCurrency(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	services := sqlmock.NewRows([]string{"serviceId", "title", "currency", "views", "impressions", "bookings", "completedJobs", "ratingSum", "ratingCount"}).
+		AddRow(4, "Plumbing", "PHP", 10, 40, 3, 2, 9, 2).
+		AddRow(5, "Wiring", "PHP", 0, 0, 0, 0, 0, 0)
+	earnings := sqlmock.NewRows([]string{"serviceId", "currency", "amount"}).
+		AddRow(4, "PHP", 1500.5).
+		AddRow(4, "USD", 40.0)
+
+	mock.ExpectQuery("FROM(.+)Service s(.+)ActivityDaily").
+		WithArgs("2026-10-01", "2026-11-01", 2, "2026-10-01", "2026-11-01", 2, "2026-10-01", "2026-11-01", "2026-10-01", "2026-11-01", 2).
+		WillReturnRows(services)
+	mock.ExpectQuery("SELECT(.+)currency(.+)FROM(.+)Transaction(.+)GROUP BY(.+)serviceId, currency").
+		WithArgs(2, "2026-10-01", "2026-11-01").
+		WillReturnRows(earnings)
+
+	stats, err := db.FindVendorServiceStats(2, "2026-10-01", "2026-11-01")
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, []models.EarningModel{{Currency: "PHP", Amount: 1500.5}, {Currency: "USD", Amount: 40}}, stats[0].Earnings)
+	assert.Empty(t, stats[1].Earnings)
+	assert.Equal(t, 0.25, stats[0].ViewRate)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFindVendorInquiryStats(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"inquiries", "converted", "averageResponseSeconds"}).AddRow(4, 1, nil)
+	mock.ExpectQuery("FROM(.+)Message(.+)HAVING firstMessage >= \\? AND firstMessage < \\?").
+		WithArgs(2, 2, 2, "2026-10-01", "2026-11-01").
+		WillReturnRows(rows)
+
+	inquiries, err := db.FindVendorInquiryStats(2, "2026-10-01", "2026-11-01")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.25, inquiries.ConversionRate)
+	assert.Nil(t, inquiries.AverageResponseSeconds)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFindVendorStatsTrend(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	activity := sqlmock.NewRows([]string{"bucket", "profileViews", "serviceViews", "impressions"}).AddRow("2026-10-05", 2, 8, 30)
+	jobs := sqlmock.NewRows([]string{"bucket", "currency", "completedJobs", "amount"}).
+		AddRow("2026-10-12", "PHP", 2, 3000.0).
+		AddRow("2026-10-12", "", 1, 0.0)
+	ratings := sqlmock.NewRows([]string{"bucket", "ratingSum", "ratingCount"}).AddRow("2026-10-12", 9, 2)
+
+	mock.ExpectQuery("WEEKDAY(.+)FROM(.+)ActivityDaily").WithArgs(2, 2, "2026-10-06", "2026-10-19").WillReturnRows(activity)
+	mock.ExpectQuery("FROM(.+)Transaction(.+)status = 'done'").WithArgs(2, "2026-10-06", "2026-10-19").WillReturnRows(jobs)
+	mock.ExpectQuery("FROM(.+)Review r").WithArgs(2, "2026-10-06", "2026-10-19").WillReturnRows(ratings)
+
+	from := time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	points, err := db.FindVendorStatsTrend(2, from, to, models.ANALYTICS_BUCKET_WEEK)
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, "2026-10-05", points[0].Bucket)
+	assert.Equal(t, 30, points[0].Impressions)
+	assert.Empty(t, points[0].Earnings)
+	assert.Equal(t, 3, points[1].CompletedJobs)
+	assert.Equal(t, []models.EarningModel{{Currency: "PHP", Amount: 3000}}, points[1].Earnings)
+	assert.Equal(t, 4.5, *points[1].AverageRating)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
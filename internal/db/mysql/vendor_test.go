@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -59,14 +60,20 @@ func TestRestrictVendor(t *testing.T) {
 	db := NewMysqlWithDb(sqlx)
 	defer db.Conn.Close()
 
-	result := sqlmock.NewResult(0, 1)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT vendorId FROM Vendor WHERE vendorId = \\? FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"vendorId"}).AddRow(1))
+	mock.ExpectQuery("SELECT EXISTS(.+)VendorRestriction").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("INSERT INTO VendorRestriction").WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec("UPDATE Vendor SET restricted = 1 WHERE vendorId = ?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WithArgs(models.EVENT_VENDOR_RESTRICTED, 1, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	query := "UPDATE Vendor SET restricted = 1 WHERE vendorId = ?"
-	mock.ExpectExec(query).WithArgs(1).WillReturnResult(result)
-
-	err := db.RestrictVendor(1)
+	id, err := db.RestrictVendor(&request.RestrictVendor{VendorId: 1, Reason: "reason", IssuedBy: 2})
 
 	assert.NoError(t, err)
+	assert.Equal(t, 5, id)
 
 	err = mock.ExpectationsWereMet()
 	assert.NoError(t, err)
@@ -78,14 +85,52 @@ func TestUnrestrictVendor(t *testing.T) {
 	db := NewMysqlWithDb(sqlx)
 	defer db.Conn.Close()
 
-	result := sqlmock.NewResult(0, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE VendorRestriction").WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE Vendor SET restricted = 0 WHERE vendorId = ?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.UnrestrictVendor(1, 2)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestIsAnyVendorRestricted(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{"restricted"}).AddRow(true)
+	mock.ExpectQuery("FROM Vendor WHERE vendorId IN \\(\\?, \\?\\) AND restricted = 1").WithArgs(1, 2).WillReturnRows(rows)
+
+	restricted, err := db.IsAnyVendorRestricted(1, 2)
+
+	assert.NoError(t, err)
+	assert.True(t, restricted)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestLiftExpiredRestrictions(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
 
-	query := "UPDATE Vendor SET restricted = 0 WHERE vendorId = ?"
-	mock.ExpectExec(query).WithArgs(1).WillReturnResult(result)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE VendorRestriction").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE Vendor v").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
 
-	err := db.UnrestrictVendor(1)
+	lifted, err := db.LiftExpiredRestrictions()
 
 	assert.NoError(t, err)
+	assert.Equal(t, 2, lifted)
 
 	err = mock.ExpectationsWereMet()
 	assert.NoError(t, err)
@@ -2,19 +2,27 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
 	"nearbyassist/internal/response"
 	"time"
 )
 
-func (m *Mysql) FindAllIdentityVerification() ([]response.AllVerification, error) {
+func (m *Mysql) FindAllIdentityVerification(status models.VerificationStatus) ([]response.AllVerification, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT id, user, createdAt FROM IdentityVerification"
+	query := "SELECT id, user, status, createdAt FROM IdentityVerification"
+	args := make([]any, 0)
+
+	if status != models.VERIFICATION_STATUS_ALL {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
 
 	requests := make([]response.AllVerification, 0)
-	if err := m.Conn.SelectContext(ctx, &requests, query); err != nil {
+	if err := m.Conn.SelectContext(ctx, &requests, query, args...); err != nil {
 		return nil, err
 	}
 
@@ -25,22 +33,73 @@ func (m *Mysql) FindAllIdentityVerification() ([]response.AllVerification, error
 	return requests, nil
 }
 
-func (m *Mysql) NewIdentityVerification(model *models.IdentityVerificationModel) (int, error) {
+// Inserts the ID images and the verification request in a single transaction
+// so that a failed request does not leave orphaned image rows behind
+func (m *Mysql) NewIdentityVerification(model *models.IdentityVerificationModel, frontId *models.FrontIdModel, backId *models.BackIdModel, face *models.FaceModel) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	images := []struct {
+		query string
+		url   string
+		dest  *int
+	}{
+		{"INSERT INTO FrontId (url) VALUES (?)", frontId.Url, &model.FrontId},
+		{"INSERT INTO BackId (url) VALUES (?)", backId.Url, &model.BackId},
+		{"INSERT INTO Face (url) VALUES (?)", face.Url, &model.Face},
+	}
+
+	for _, image := range images {
+		res, err := tx.ExecContext(ctx, image.query, image.url)
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return 0, err
+			}
+
+			return 0, err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return 0, err
+			}
+
+			return 0, err
+		}
+
+		*image.dest = int(id)
+	}
+
 	query := `
-        INSERT INTO IdentityVerification (name, address, idType, idNumber, frontId, backId, face)
-        VALUES ( :name, :address, :idType, :idNumber, :frontId, :backId, :face)
+        INSERT INTO IdentityVerification (user, name, address, idType, idNumber, frontId, backId, face)
+        VALUES (:user, :name, :address, :idType, :idNumber, :frontId, :backId, :face)
     `
 
-	res, err := m.Conn.NamedExecContext(ctx, query, model)
+	res, err := tx.NamedExecContext(ctx, query, model)
 	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
 		return 0, err
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, err
+		}
+
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
 
@@ -55,7 +114,15 @@ func (m *Mysql) FindIdentityVerificationById(id int) (*models.IdentityVerificati
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "SELECT id, name, address, idType, idNumber, frontId, backId, face FROM IdentityVerification WHERE id = ?"
+	query := `
+        SELECT
+            id, user, name, address, idType, idNumber, frontId, backId, face,
+            status, COALESCE(reason, '') AS reason, reviewedBy, reviewedAt, createdAt
+        FROM
+            IdentityVerification
+        WHERE
+            id = ?
+    `
 
 	model := &models.IdentityVerificationModel{}
 	if err := m.Conn.GetContext(ctx, model, query, id); err != nil {
@@ -66,74 +133,85 @@ func (m *Mysql) FindIdentityVerificationById(id int) (*models.IdentityVerificati
 		return nil, context.DeadlineExceeded
 	}
 
-	return nil, nil
+	return model, nil
 }
 
-func (m *Mysql) NewFrontId(model *models.FrontIdModel) (int, error) {
+func (m *Mysql) FindLatestIdentityVerificationByUser(userId int) (*response.VerificationStatus, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "INSERT INTO FrontId (url) VALUES (:url)"
-
-	res, err := m.Conn.NamedExecContext(ctx, query, model)
-	if err != nil {
-		return 0, err
-	}
+	query := `
+        SELECT
+            id, status, COALESCE(reason, '') AS reason, reviewedAt, createdAt
+        FROM
+            IdentityVerification
+        WHERE
+            user = ?
+        ORDER BY
+            createdAt DESC, id DESC
+        LIMIT 1
+    `
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, err
+	status := &response.VerificationStatus{}
+	if err := m.Conn.GetContext(ctx, status, query, userId); err != nil {
+		return nil, err
 	}
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return 0, context.DeadlineExceeded
+		return nil, context.DeadlineExceeded
 	}
 
-	return int(id), nil
+	return status, nil
 }
 
-func (m *Mysql) NewBackId(model *models.BackIdModel) (int, error) {
+func (m *Mysql) DecideIdentityVerification(decision *request.VerificationDecision) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "INSERT INTO BackId (url) VALUES (:url)"
+	query := `
+        UPDATE
+            IdentityVerification
+        SET
+            status = :status,
+            reason = :reason,
+            reviewedBy = :reviewedBy,
+            reviewedAt = CURRENT_TIMESTAMP
+        WHERE
+            id = :id AND status = 'pending'
+    `
 
-	res, err := m.Conn.NamedExecContext(ctx, query, model)
+	res, err := m.Conn.NamedExecContext(ctx, query, decision)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, err
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return errors.New("Verification not found or already reviewed")
 	}
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return 0, context.DeadlineExceeded
+		return context.DeadlineExceeded
 	}
 
-	return int(id), nil
+	return nil
 }
 
-func (m *Mysql) NewFace(model *models.FaceModel) (int, error) {
+func (m *Mysql) IsUserVerified(userId int) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	query := "INSERT INTO Face (url) VALUES (:url)"
-
-	res, err := m.Conn.NamedExecContext(ctx, query, model)
-	if err != nil {
-		return 0, err
-	}
+	query := "SELECT EXISTS(SELECT 1 FROM IdentityVerification WHERE user = ? AND status = 'approved')"
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, err
+	verified := false
+	if err := m.Conn.GetContext(ctx, &verified, query, userId); err != nil {
+		return false, err
 	}
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return 0, context.DeadlineExceeded
+		return false, context.DeadlineExceeded
 	}
 
-	return int(id), nil
+	return verified, nil
 }
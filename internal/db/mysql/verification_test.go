@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"errors"
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIdentityVerification(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO FrontId").WithArgs("front").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO BackId").WithArgs("back").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("INSERT INTO Face").WithArgs("face").WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec("INSERT INTO IdentityVerification").WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectCommit()
+
+	model := &models.IdentityVerificationModel{User: 1, Name: "name", Address: "address", IdType: "passport", IdNumber: "123"}
+	id, err := db.NewIdentityVerification(model, &models.FrontIdModel{Url: "front"}, &models.BackIdModel{Url: "back"}, &models.FaceModel{Url: "face"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+	assert.Equal(t, 1, model.FrontId)
+	assert.Equal(t, 2, model.BackId)
+	assert.Equal(t, 3, model.Face)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestNewIdentityVerificationRollsBack(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO FrontId").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO BackId").WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	model := &models.IdentityVerificationModel{User: 1}
+	_, err := db.NewIdentityVerification(model, &models.FrontIdModel{Url: "front"}, &models.BackIdModel{Url: "back"}, &models.FaceModel{Url: "face"})
+
+	assert.Error(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,456 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"nearbyassist/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (m *Mysql) NewWebhook(webhook *models.WebhookModel) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT INTO
+            Webhook (url, secret, events, createdBy)
+        VALUES
+            (:url, :secret, :events, :createdBy)
+    `
+
+	res, err := m.Conn.NamedExecContext(ctx, query, webhook)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, context.DeadlineExceeded
+	}
+
+	return int(id), nil
+}
+
+func (m *Mysql) FindAllWebhooks() ([]models.WebhookModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, url, secret, events, active, createdBy, createdAt, updatedAt FROM Webhook ORDER BY id"
+
+	webhooks := make([]models.WebhookModel, 0)
+	if err := m.Conn.SelectContext(ctx, &webhooks, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return webhooks, nil
+}
+
+func (m *Mysql) FindActiveWebhooks() ([]models.WebhookModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, url, secret, events, active, createdBy, createdAt, updatedAt FROM Webhook WHERE active = 1"
+
+	webhooks := make([]models.WebhookModel, 0)
+	if err := m.Conn.SelectContext(ctx, &webhooks, query); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return webhooks, nil
+}
+
+func (m *Mysql) FindWebhookById(id int) (*models.WebhookModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, url, secret, events, active, createdBy, createdAt, updatedAt FROM Webhook WHERE id = ?"
+
+	webhook := &models.WebhookModel{}
+	if err := m.Conn.GetContext(ctx, webhook, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return webhook, nil
+}
+
+// Only rotates the secret when a new one is given
+func (m *Mysql) UpdateWebhook(webhook *models.WebhookModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        UPDATE
+            Webhook
+        SET
+            url = :url,
+            events = :events,
+            active = :active,
+            secret = IF(:secret = '', secret, :secret)
+        WHERE
+            id = :id
+    `
+
+	if _, err := m.Conn.NamedExecContext(ctx, query, webhook); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func (m *Mysql) DeleteWebhook(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "DELETE FROM Webhook WHERE id = ?"
+
+	res, err := m.Conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return errors.New("Webhook not found")
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Ignores deliveries that already exist for the same webhook and outbox event
+// so a redispatched event is not sent twice
+func (m *Mysql) NewWebhookDelivery(delivery *models.WebhookDeliveryModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        INSERT IGNORE INTO
+            WebhookDelivery (webhookId, outboxId, eventType, payload)
+        VALUES
+            (:webhookId, :outboxId, :eventType, :payload)
+    `
+
+	if _, err := m.Conn.NamedExecContext(ctx, query, delivery); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Claims a batch of due deliveries. Rows held by another deliverer are
+// skipped, and the claimed ones are pushed back by the lease so a partner is
+// not sent the same delivery twice while it is being posted
+func (m *Mysql) FindPendingWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT
+            d.id, d.webhookId, d.outboxId, d.eventType, d.payload, d.status, d.attempts,
+            d.responseCode, d.lastError, d.nextAttemptAt, d.deliveredAt, d.createdAt,
+            w.url, w.secret
+        FROM
+            WebhookDelivery d
+        JOIN
+            Webhook w ON w.id = d.webhookId
+        WHERE
+            d.status = 'pending' AND d.nextAttemptAt <= CURRENT_TIMESTAMP AND w.active = 1
+        ORDER BY
+            d.id ASC
+        LIMIT ?
+        FOR UPDATE OF d SKIP LOCKED
+    `
+
+	deliveries := make([]models.WebhookDeliveryModel, 0)
+	if err := tx.SelectContext(ctx, &deliveries, query, limit); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	if len(deliveries) > 0 {
+		ids := make([]int, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.Id
+		}
+
+		claim, args, err := sqlx.In("UPDATE WebhookDelivery SET nextAttemptAt = DATE_ADD(CURRENT_TIMESTAMP, INTERVAL ? SECOND) WHERE id IN (?)", int(lease.Seconds()), ids)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, tx.Rebind(claim), args...)
+		}
+
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return nil, err
+			}
+
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return deliveries, nil
+}
+
+func (m *Mysql) FindWebhookDeliveries(webhookId int) ([]models.WebhookDeliveryModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, webhookId, outboxId, eventType, payload, status, attempts,
+            responseCode, lastError, nextAttemptAt, deliveredAt, createdAt
+        FROM
+            WebhookDelivery
+        WHERE
+            webhookId = ?
+        ORDER BY
+            id DESC
+        LIMIT 100
+    `
+
+	deliveries := make([]models.WebhookDeliveryModel, 0)
+	if err := m.Conn.SelectContext(ctx, &deliveries, query, webhookId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return deliveries, nil
+}
+
+func (m *Mysql) FindWebhookDeliveryById(id int) (*models.WebhookDeliveryModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        SELECT
+            id, webhookId, outboxId, eventType, payload, status, attempts,
+            responseCode, lastError, nextAttemptAt, deliveredAt, createdAt
+        FROM
+            WebhookDelivery
+        WHERE
+            id = ?
+    `
+
+	delivery := &models.WebhookDeliveryModel{}
+	if err := m.Conn.GetContext(ctx, delivery, query, id); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return delivery, nil
+}
+
+func (m *Mysql) FindWebhookDeliveryAttempts(deliveryId int) ([]models.WebhookDeliveryAttemptModel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := "SELECT id, deliveryId, responseCode, error, durationMs, createdAt FROM WebhookDeliveryAttempt WHERE deliveryId = ? ORDER BY id"
+
+	attempts := make([]models.WebhookDeliveryAttemptModel, 0)
+	if err := m.Conn.SelectContext(ctx, &attempts, query, deliveryId); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, context.DeadlineExceeded
+	}
+
+	return attempts, nil
+}
+
+func (m *Mysql) MarkWebhookDeliverySucceeded(attempt *models.WebhookDeliveryAttemptModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := insertWebhookAttempt(ctx, tx, attempt); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	query := `
+        UPDATE
+            WebhookDelivery
+        SET
+            status = 'succeeded',
+            attempts = attempts + 1,
+            responseCode = ?,
+            lastError = NULL,
+            deliveredAt = CURRENT_TIMESTAMP
+        WHERE
+            id = ?
+    `
+
+	if _, err := tx.ExecContext(ctx, query, attempt.ResponseCode, attempt.DeliveryId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Schedules the next attempt, the delivery is marked as failed once it runs
+// out of attempts
+func (m *Mysql) MarkWebhookDeliveryFailed(attempt *models.WebhookDeliveryAttemptModel, retryIn time.Duration, maxAttempts int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := m.Conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := insertWebhookAttempt(ctx, tx, attempt); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	query := `
+        UPDATE
+            WebhookDelivery
+        SET
+            status = IF(attempts + 1 >= ?, 'failed', 'pending'),
+            attempts = attempts + 1,
+            responseCode = ?,
+            lastError = ?,
+            nextAttemptAt = DATE_ADD(CURRENT_TIMESTAMP, INTERVAL ? SECOND)
+        WHERE
+            id = ?
+    `
+
+	if _, err := tx.ExecContext(ctx, query, maxAttempts, attempt.ResponseCode, attempt.Error, int(retryIn.Seconds()), attempt.DeliveryId); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+// Queues the delivery again with a fresh set of attempts
+func (m *Mysql) RedeliverWebhookDelivery(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	query := `
+        UPDATE
+            WebhookDelivery
+        SET
+            status = 'pending',
+            attempts = 0,
+            nextAttemptAt = CURRENT_TIMESTAMP
+        WHERE
+            id = ? AND status <> 'pending'
+    `
+
+	res, err := m.Conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return errors.New("Delivery not found or already pending")
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
+
+func insertWebhookAttempt(ctx context.Context, tx *sqlx.Tx, attempt *models.WebhookDeliveryAttemptModel) error {
+	query := `
+        INSERT INTO
+            WebhookDeliveryAttempt (deliveryId, responseCode, error, durationMs)
+        VALUES
+            (:deliveryId, :responseCode, :error, :durationMs)
+    `
+
+	_, err := tx.NamedExecContext(ctx, query, attempt)
+	return err
+}
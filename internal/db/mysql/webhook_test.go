@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkWebhookDeliveryFailed(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	code := 500
+	reason := "endpoint responded with status 500"
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO WebhookDeliveryAttempt").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE WebhookDelivery").WithArgs(8, &code, &reason, 4, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.MarkWebhookDeliveryFailed(&models.WebhookDeliveryAttemptModel{
+		DeliveryId:   3,
+		ResponseCode: &code,
+		Error:        &reason,
+	}, time.Second*4, 8)
+
+	assert.NoError(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestRedeliverWebhookDeliveryAlreadyPending(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	mock.ExpectExec("UPDATE WebhookDelivery").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := db.RedeliverWebhookDelivery(1)
+
+	assert.Error(t, err)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestFindPendingWebhookDeliveries(t *testing.T) {
+	sql, mock := newMock()
+	sqlx := sqlx.NewDb(sql, "sqlmock")
+	db := NewMysqlWithDb(sqlx)
+	defer db.Conn.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "webhookId", "outboxId", "eventType", "payload", "status", "attempts",
+		"responseCode", "lastError", "nextAttemptAt", "deliveredAt", "createdAt", "url", "secret",
+	}).AddRow(3, 1, 7, "ReviewPosted", []byte(`{"reviewId":4}`), "pending", 0, nil, nil, "2026-10-18 10:00:00", nil, "2026-10-18 10:00:00", "https://partner.test/hook", "secret")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM(.+)WebhookDelivery d(.+)FOR UPDATE OF d SKIP LOCKED").WithArgs(20).WillReturnRows(rows)
+	mock.ExpectExec("UPDATE WebhookDelivery SET nextAttemptAt = (.+) WHERE id IN \\(\\?\\)").WithArgs(400, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deliveries, err := db.FindPendingWebhookDeliveries(20, time.Second*400)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, 7, deliveries[0].OutboxId)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
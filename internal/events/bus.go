@@ -0,0 +1,85 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"nearbyassist/internal/models"
+	"slices"
+	"sync"
+)
+
+// Handler consumes a single outbox event. Events are delivered at least once,
+// handlers must be safe to run again for an event they already processed.
+type Handler func(event models.OutboxEventModel) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Bus fans an event out to every subscriber registered for its type
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[models.EventType][]subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[models.EventType][]subscriber),
+	}
+}
+
+// The name records which subscribers handled an event, it must be unique
+// among the subscribers of the event type
+func (b *Bus) Subscribe(eventType models.EventType, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[eventType] = append(b.subscribers[eventType], subscriber{name: name, handler: handler})
+}
+
+// Runs every subscriber of the event, a failing or panicking subscriber does
+// not stop the others and all failures are returned together
+func (b *Bus) Publish(event models.OutboxEventModel) error {
+	return b.PublishPending(event, nil, nil)
+}
+
+// Like Publish but skips the subscribers that already handled the event, and
+// reports every subscriber that succeeds to done so a retry can skip it too
+func (b *Bus) PublishPending(event models.OutboxEventModel, delivered []string, done func(name string) error) error {
+	b.mu.RLock()
+	subscribers := b.subscribers[event.EventType]
+	b.mu.RUnlock()
+
+	errs := make([]error, 0)
+	for _, sub := range subscribers {
+		if slices.Contains(delivered, sub.name) {
+			continue
+		}
+
+		if err := run(sub, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+
+		if done == nil {
+			continue
+		}
+
+		if err := done(sub.name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func run(sub subscriber, event models.OutboxEventModel) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return sub.handler(event)
+}
@@ -0,0 +1,45 @@
+package events
+
+import (
+	"errors"
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishRunsEverySubscriber(t *testing.T) {
+	bus := NewBus()
+
+	called := make([]string, 0)
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "first", func(event models.OutboxEventModel) error {
+		called = append(called, "first")
+		return errors.New("failed")
+	})
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "second", func(event models.OutboxEventModel) error {
+		panic("boom")
+	})
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "third", func(event models.OutboxEventModel) error {
+		called = append(called, "third")
+		return nil
+	})
+	bus.Subscribe(models.EVENT_MESSAGE_SENT, "other", func(event models.OutboxEventModel) error {
+		called = append(called, "other")
+		return nil
+	})
+
+	err := bus.Publish(models.OutboxEventModel{EventType: models.EVENT_REVIEW_POSTED})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "first: failed")
+	assert.Contains(t, err.Error(), "second: panic: boom")
+	assert.Equal(t, []string{"first", "third"}, called)
+}
+
+func TestPublishWithoutSubscribers(t *testing.T) {
+	bus := NewBus()
+
+	err := bus.Publish(models.OutboxEventModel{EventType: models.EVENT_TRANSACTION_CREATED})
+
+	assert.NoError(t, err)
+}
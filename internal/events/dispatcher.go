@@ -0,0 +1,98 @@
+package events
+
+import (
+	"log"
+	"nearbyassist/internal/db"
+	"time"
+)
+
+const (
+	DISPATCH_INTERVAL     = time.Second * 2
+	DISPATCH_BATCH_SIZE   = 50
+	DISPATCH_MAX_ATTEMPTS = 10
+	DISPATCH_MAX_BACKOFF  = time.Minute * 5
+
+	// Claimed events are hidden from other dispatchers this long
+	DISPATCH_LEASE = time.Minute
+)
+
+// Dispatcher polls the outbox and publishes committed events to the bus
+type Dispatcher struct {
+	db       db.Database
+	bus      *Bus
+	interval time.Duration
+}
+
+func NewDispatcher(db db.Database, bus *Bus) *Dispatcher {
+	return &Dispatcher{
+		db:       db,
+		bus:      bus,
+		interval: DISPATCH_INTERVAL,
+	}
+}
+
+func (d *Dispatcher) Run() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchPending(); err != nil {
+			log.Printf("error dispatching events: %s\n", err.Error())
+		}
+
+		<-ticker.C
+	}
+}
+
+// Publishes one batch of pending events and returns how many succeeded.
+// Failed events are retried later with an exponential backoff until they run
+// out of attempts, a retry only runs the subscribers that failed.
+func (d *Dispatcher) DispatchPending() (int, error) {
+	pending, err := d.db.FindPendingOutboxEvents(DISPATCH_BATCH_SIZE, DISPATCH_MAX_ATTEMPTS, DISPATCH_LEASE)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, event := range pending {
+		delivered, err := d.db.FindOutboxDeliveries(event.Id)
+		if err != nil {
+			return dispatched, err
+		}
+
+		done := func(name string) error {
+			return d.db.MarkOutboxDelivered(event.Id, name)
+		}
+
+		if err := d.bus.PublishPending(event, delivered, done); err != nil {
+			log.Printf("error handling event %d (%s): %s\n", event.Id, event.EventType, err.Error())
+
+			if err := d.db.MarkOutboxEventFailed(event.Id, err.Error(), Backoff(event.Attempts)); err != nil {
+				return dispatched, err
+			}
+
+			continue
+		}
+
+		if err := d.db.MarkOutboxEventDispatched(event.Id); err != nil {
+			return dispatched, err
+		}
+
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+// Returns the delay before the next attempt, doubling on every failure
+func Backoff(attempts int) time.Duration {
+	delay := time.Second
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= DISPATCH_MAX_BACKOFF {
+			return DISPATCH_MAX_BACKOFF
+		}
+	}
+
+	return delay
+}
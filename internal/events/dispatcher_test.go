@@ -0,0 +1,74 @@
+package events
+
+import (
+	"errors"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatchPending(t *testing.T) {
+	fake := dbtest.NewFake()
+	fake.OutboxEvents = []models.OutboxEventModel{
+		{Id: 1, EventType: models.EVENT_TRANSACTION_CREATED},
+		{Id: 2, EventType: models.EVENT_REVIEW_POSTED, Attempts: 3},
+	}
+
+	bus := NewBus()
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "failing", func(event models.OutboxEventModel) error {
+		return errors.New("unavailable")
+	})
+
+	dispatched, err := NewDispatcher(fake, bus).DispatchPending()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+	assert.Equal(t, []int{1}, fake.DispatchedEvents)
+	assert.Equal(t, time.Second*8, fake.FailedEvents[2])
+}
+
+func TestRetryOnlyRunsFailedSubscribers(t *testing.T) {
+	fake := dbtest.NewFake()
+	fake.OutboxEvents = []models.OutboxEventModel{{Id: 1, EventType: models.EVENT_REVIEW_POSTED}}
+
+	calls := make(map[string]int)
+	fail := true
+
+	bus := NewBus()
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "notify", func(event models.OutboxEventModel) error {
+		calls["notify"]++
+		return nil
+	})
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "rating", func(event models.OutboxEventModel) error {
+		calls["rating"]++
+		if fail {
+			return errors.New("unavailable")
+		}
+
+		return nil
+	})
+
+	dispatcher := NewDispatcher(fake, bus)
+
+	dispatched, err := dispatcher.DispatchPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, dispatched)
+
+	fail = false
+	dispatched, err = dispatcher.DispatchPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+
+	assert.Equal(t, 1, calls["notify"])
+	assert.Equal(t, 2, calls["rating"])
+	assert.Equal(t, []string{"notify", "rating"}, fake.OutboxDeliveries[1])
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(0))
+	assert.Equal(t, time.Second*4, Backoff(2))
+	assert.Equal(t, DISPATCH_MAX_BACKOFF, Backoff(20))
+}
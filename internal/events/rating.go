@@ -0,0 +1,20 @@
+package events
+
+import (
+	"encoding/json"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+)
+
+// Keeps the vendor rating in sync with the reviews of their services.
+// Recalculating from scratch makes the handler safe to run more than once.
+func SubscribeRating(bus *Bus, db db.Database) {
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "vendor-rating", func(event models.OutboxEventModel) error {
+		payload := models.ReviewEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return db.RecalculateVendorRating(payload.ServiceId)
+	})
+}
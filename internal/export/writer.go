@@ -0,0 +1,38 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"nearbyassist/internal/models"
+)
+
+// Writes one row at a time to the underlying writer, nothing but the current
+// row is kept in memory. Close must be called to complete the file
+type Writer interface {
+	Write(record []string) error
+	Close() error
+}
+
+func NewWriter(format models.ExportFormat, w io.Writer) (Writer, error) {
+	switch format {
+	case models.EXPORT_FORMAT_CSV:
+		return newCsvWriter(w), nil
+	case models.EXPORT_FORMAT_XLSX:
+		return newXlsxWriter(w)
+	}
+
+	return nil, errors.New("unsupported export format")
+}
+
+func ContentType(format models.ExportFormat) string {
+	if format == models.EXPORT_FORMAT_XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+func Filename(list models.ExportList, format models.ExportFormat, date string) string {
+	return fmt.Sprintf("%s-%s.%s", list, date, format)
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer, err := NewWriter(models.EXPORT_FORMAT_CSV, buffer)
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.Write([]string{"ID", "Title"}))
+	assert.NoError(t, writer.Write([]string{"1", "Broken sink, \"urgent\""}))
+	assert.NoError(t, writer.Write([]string{"2", "=HYPERLINK(\"http://example.com\")"}))
+	assert.NoError(t, writer.Close())
+
+	expected := "ID,Title\n" +
+		"1,\"Broken sink, \"\"urgent\"\"\"\n" +
+		"2,\"'=HYPERLINK(\"\"http://example.com\"\")\"\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestEscapeFormula(t *testing.T) {
+	assert.Equal(t, "", escapeFormula(""))
+	assert.Equal(t, "'-1+2", escapeFormula("-1+2"))
+	assert.Equal(t, "'@SUM(A1)", escapeFormula("@SUM(A1)"))
+	assert.Equal(t, "Juan", escapeFormula("Juan"))
+}
+
+func TestXlsxWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer, err := NewWriter(models.EXPORT_FORMAT_XLSX, buffer)
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.Write([]string{"ID", "Name"}))
+	assert.NoError(t, writer.Write([]string{"1", "Peña <Plumbing> & Co\x00"}))
+	assert.NoError(t, writer.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NoError(t, err)
+
+	names := make([]string, 0)
+	var sheet []byte
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, err := file.Open()
+			assert.NoError(t, err)
+			sheet, _ = io.ReadAll(reader)
+			reader.Close()
+		}
+	}
+
+	assert.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+
+	parsed := struct {
+		Rows []struct {
+			Cells []string `xml:"c>is>t"`
+		} `xml:"sheetData>row"`
+	}{}
+	assert.NoError(t, xml.Unmarshal(sheet, &parsed))
+
+	assert.Len(t, parsed.Rows, 2)
+	assert.Equal(t, []string{"ID", "Name"}, parsed.Rows[0].Cells)
+	assert.Equal(t, []string{"1", "Peña <Plumbing> & Co�"}, parsed.Rows[1].Cells)
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	_, err := NewWriter(models.ExportFormat("pdf"), &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"log"
+	"nearbyassist/internal/jobs"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/server"
+	"nearbyassist/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ANALYTICS_DEFAULT_RANGE_DAYS = 30
+	ANALYTICS_MAX_RANGE_DAYS     = 731
+)
+
+type analyticsHandler struct {
+	server *server.Server
+}
+
+func NewAnalyticsHandler(server *server.Server) *analyticsHandler {
+	return &analyticsHandler{
+		server: server,
+	}
+}
+
+// Defaults to the last 30 days by day, over all services
+func (h *analyticsHandler) HandleGetAnalytics(c echo.Context) error {
+	filter := &request.AnalyticsFilter{}
+	if err := c.Bind(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid filter")
+	}
+
+	if filter.Bucket == "" {
+		filter.Bucket = models.ANALYTICS_BUCKET_DAY
+	}
+
+	if filter.Split == "" {
+		filter.Split = models.ANALYTICS_SPLIT_ALL
+	}
+
+	if !filter.Bucket.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "bucket must be day, week or month")
+	}
+
+	if !filter.Split.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "split must be all, tag or area")
+	}
+
+	from, to, err := parsePeriod(filter.From, filter.To)
+	if err != nil {
+		return err
+	}
+
+	filter.From, filter.To = from.Format(time.DateOnly), to.Format(time.DateOnly)
+
+	points, err := h.server.DB.FindAnalytics(filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if filter.Split == models.ANALYTICS_SPLIT_ALL {
+		points = models.FillAnalyticsGaps(points, from, to, filter.Bucket)
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"from":   filter.From,
+		"to":     filter.To,
+		"bucket": filter.Bucket,
+		"split":  filter.Split,
+		"series": points,
+	})
+}
+
+// Rebuilds the totals outside the regular schedule, from the given day when
+// history has to be corrected
+func (h *analyticsHandler) HandleRefreshAnalytics(c echo.Context) error {
+	from := c.QueryParam("from")
+	if from != "" {
+		if _, err := time.Parse(time.DateOnly, from); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "from must be formatted as YYYY-MM-DD")
+		}
+	}
+
+	go func() {
+		var err error
+		if from == "" {
+			err = jobs.RefreshAnalytics(h.server.DB)
+		} else {
+			err = h.server.DB.RefreshAnalytics(from)
+		}
+
+		if err != nil {
+			log.Printf("error refreshing analytics: %s\n", err.Error())
+		}
+	}()
+
+	return c.JSON(http.StatusAccepted, utils.Mapper{
+		"message": "analytics refresh started",
+	})
+}
+
+// Inclusive days formatted as YYYY-MM-DD, the last 30 days unless given
+func parsePeriod(fromParam, toParam string) (time.Time, time.Time, error) {
+	to := time.Now().UTC().Truncate(time.Hour * 24)
+	if toParam != "" {
+		parsed, err := time.Parse(time.DateOnly, toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, "to must be formatted as YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(ANALYTICS_DEFAULT_RANGE_DAYS - 1))
+	if fromParam != "" {
+		parsed, err := time.Parse(time.DateOnly, fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, "from must be formatted as YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, "from must not be after to")
+	}
+
+	if to.Sub(from) > time.Hour*24*ANALYTICS_MAX_RANGE_DAYS {
+		return time.Time{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, "date range is too long")
+	}
+
+	return from, to, nil
+}
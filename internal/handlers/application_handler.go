@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"nearbyassist/internal/hash"
 	"nearbyassist/internal/models"
 	"nearbyassist/internal/request"
+	"nearbyassist/internal/response"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
@@ -56,9 +58,12 @@ func (h *applicationHandler) HandleNewApplication(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnprocessableEntity, "applicant is already a vendor")
 	}
 
-	applicationId, err := h.server.DB.CreateApplication(req)
+	// Rejected applicants may apply again, but only one application can be active
+	applicationId, created, err := h.server.DB.CreateApplication(req)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if !created {
+		return echo.NewHTTPError(http.StatusConflict, "applicant already has a pending or approved application")
 	}
 
 	return c.JSON(http.StatusCreated, utils.Mapper{
@@ -80,6 +85,75 @@ func (h *applicationHandler) HandleGetApplications(c echo.Context) error {
 	})
 }
 
+func (h *applicationHandler) HandleGetOwnApplications(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	applications, err := h.server.DB.FindApplicationsByApplicant(userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"applications": applications,
+	})
+}
+
+func (h *applicationHandler) HandleGetApplication(c echo.Context) error {
+	applicationId := c.Param("applicationId")
+	id, err := strconv.Atoi(applicationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "application ID must be a number")
+	}
+
+	application, err := h.server.DB.FindApplicationById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "application not found")
+	}
+
+	user, err := h.server.DB.FindUserById(application.ApplicantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "applicant not found")
+	}
+
+	applicant := response.Applicant{
+		Id:       user.Id,
+		ImageUrl: user.ImageUrl,
+	}
+
+	if decrypted, err := h.server.Encrypt.DecryptString(user.Name); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		applicant.Name = decrypted
+	}
+
+	if decrypted, err := h.server.Encrypt.DecryptString(user.Email); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		applicant.Email = decrypted
+	}
+
+	if verified, err := h.server.DB.IsUserVerified(user.Id); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		applicant.Verified = verified
+	}
+
+	proofs, err := h.server.DB.FindApplicationProofs(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"application": application,
+		"applicant":   applicant,
+		"proofs":      proofs,
+	})
+}
+
 func (h *applicationHandler) HandleApprove(c echo.Context) error {
 	applicationId := c.Param("applicationId")
 	id, err := strconv.Atoi(applicationId)
@@ -87,10 +161,32 @@ func (h *applicationHandler) HandleApprove(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "application ID must be a number")
 	}
 
-	if err = h.server.DB.ApproveApplication(id); err != nil {
+	authHeader := c.Request().Header.Get("Authorization")
+	adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	application, err := h.server.DB.FindApplicationById(id)
+	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "application not found")
 	}
 
+	if verified, err := h.server.DB.IsUserVerified(application.ApplicantId); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if !verified {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "applicant has no approved identity verification")
+	}
+
+	review := &request.ApplicationReview{
+		Id:         id,
+		ReviewedBy: adminId,
+	}
+
+	if err = h.server.DB.ApproveApplication(review); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
 		"message":       "Application approved successfully",
 		"applicationId": id,
@@ -104,10 +200,32 @@ func (h *applicationHandler) HandleReject(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "application ID must be a number")
 	}
 
-	if err := h.server.DB.RejectApplication(id); err != nil {
+	review := &request.ApplicationReview{}
+	if err := c.Bind(review); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+
+	// The reason is shown to the applicant so they can fix it before reapplying
+	if review.Reason == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "a rejection reason is required")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		review.Id = id
+		review.ReviewedBy = adminId
+	}
+
+	if _, err := h.server.DB.FindApplicationById(id); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "application not found")
 	}
 
+	if err := h.server.DB.RejectApplication(review); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
 		"message":       "Application rejected successfully",
 		"applicationId": id,
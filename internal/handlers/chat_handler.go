@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
@@ -64,17 +67,15 @@ func (h *chatHandler) HandleWebsocket(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	h.server.Websocket.Clients[userId] = conn
+	h.server.Websocket.AddClient(userId, conn)
 	fmt.Printf("userId: %d connected\n", userId)
 
 	for {
-		message := models.NewMessageModel()
-		err := conn.ReadJSON(message)
+		frame := &request.ChatFrame{}
+		err := conn.ReadJSON(frame)
 		if err != nil {
 			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				if _, ok := h.server.Websocket.Clients[userId]; ok {
-					delete(h.server.Websocket.Clients, userId)
-				}
+				h.server.Websocket.RemoveClient(userId)
 
 				fmt.Printf("client: %d disconnected\n", userId)
 				return nil
@@ -84,8 +85,144 @@ func (h *chatHandler) HandleWebsocket(c echo.Context) error {
 			continue
 		}
 
-		h.server.Websocket.MessageChan <- *message
+		// The sender is always the owner of the connection
+		frame.Sender = userId
+
+		// A quote is answered to the vendor who sent it, whatever receiver
+		// the client claims
+		if frame.Type == models.MESSAGE_TYPE_QUOTE_ACCEPTED || frame.Type == models.MESSAGE_TYPE_QUOTE_DECLINED {
+			quote, err := h.server.DB.FindQuoteById(frame.QuoteId)
+			if err != nil {
+				h.server.Websocket.SendError(userId, errors.New("quote not found"))
+				continue
+			}
+
+			frame.Receiver = quote.VendorId
+		}
+
+		// Restricted vendors can neither send nor receive messages
+		if restricted, err := h.server.DB.IsAnyVendorRestricted(frame.Sender, frame.Receiver); err != nil {
+			log.Printf("Failed to check chat restriction: %s\n", err.Error())
+			h.server.Websocket.SendError(userId, errors.New("unable to send message, try again"))
+			continue
+		} else if restricted {
+			h.server.Websocket.SendError(userId, errors.New("message not sent, vendor is restricted"))
+			continue
+		}
+
+		switch frame.Type {
+		case "", models.MESSAGE_TYPE_TEXT:
+			message := models.NewMessageModel()
+			message.Sender = frame.Sender
+			message.Receiver = frame.Receiver
+			message.Content = frame.Content
+
+			h.server.Websocket.MessageChan <- *message
+		case models.MESSAGE_TYPE_QUOTE:
+			h.handleQuote(frame)
+		case models.MESSAGE_TYPE_QUOTE_ACCEPTED, models.MESSAGE_TYPE_QUOTE_DECLINED:
+			h.handleQuoteResponse(frame)
+		default:
+			h.server.Websocket.SendError(userId, errors.New("unknown message type"))
+		}
+	}
+}
+
+func (h *chatHandler) HandleGetQuote(c echo.Context) error {
+	quoteId := c.Param("quoteId")
+	id, err := strconv.Atoi(quoteId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "quote ID must be a number")
 	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	quote, err := h.server.DB.FindQuoteById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "quote not found")
+	}
+
+	if quote.VendorId != userId && quote.ClientId != userId {
+		return echo.NewHTTPError(http.StatusForbidden, "you are not part of this quote")
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"quote": quote,
+	})
+}
+
+// Vendors quote their own services to the user they are chatting with
+func (h *chatHandler) handleQuote(frame *request.ChatFrame) {
+	quote := frame.Quote
+	if quote == nil {
+		h.server.Websocket.SendError(frame.Sender, errors.New("quote is missing"))
+		return
+	}
+
+	quote.VendorId = frame.Sender
+	quote.ClientId = frame.Receiver
+	quote.Currency = normalizeCurrency(quote.Currency)
+
+	if err := h.server.Echo.Validator.Validate(quote); err != nil {
+		h.server.Websocket.SendError(frame.Sender, errors.New("quote is missing required fields"))
+		return
+	}
+
+	if err := utils.ValidateDateRange(quote.Start, quote.End); err != nil {
+		h.server.Websocket.SendError(frame.Sender, errors.New("quote dates must be in the future"))
+		return
+	}
+
+	if err := utils.ValidateDateRange(quote.ValidUntil, quote.ValidUntil); err != nil {
+		h.server.Websocket.SendError(frame.Sender, errors.New("quote validity must be in the future"))
+		return
+	}
+
+	if owner, err := h.server.DB.FindServiceOwner(quote.ServiceId); err != nil || owner.Id != quote.VendorId {
+		h.server.Websocket.SendError(frame.Sender, errors.New("you can only quote your own services"))
+		return
+	}
+
+	message, err := h.server.DB.NewQuote(quote)
+	if err != nil {
+		h.server.Websocket.SendError(frame.Sender, err)
+		return
+	}
+
+	h.server.Websocket.BroadcastChan <- *message
+}
+
+// Only the client a quote was sent to can answer it
+func (h *chatHandler) handleQuoteResponse(frame *request.ChatFrame) {
+	response := &request.QuoteResponse{
+		QuoteId:  frame.QuoteId,
+		Version:  frame.Version,
+		ClientId: frame.Sender,
+	}
+
+	if err := h.server.Echo.Validator.Validate(response); err != nil {
+		h.server.Websocket.SendError(frame.Sender, errors.New("quoteId and version are required"))
+		return
+	}
+
+	var message *models.MessageModel
+	var err error
+	if frame.Type == models.MESSAGE_TYPE_QUOTE_ACCEPTED {
+		message, _, err = h.server.DB.AcceptQuote(response)
+	} else {
+		message, err = h.server.DB.DeclineQuote(response)
+	}
+
+	if err != nil {
+		h.server.Websocket.SendError(frame.Sender, err)
+		return
+	}
+
+	h.server.Websocket.BroadcastChan <- *message
 }
 
 func (h *chatHandler) HandleGetConversations(c echo.Context) error {
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	filehandler "nearbyassist/internal/file"
 	"nearbyassist/internal/hash"
 	"nearbyassist/internal/models"
@@ -30,18 +32,45 @@ func (h *complaintHandler) HandleBaseRoute(c echo.Context) error {
 }
 
 func (h *complaintHandler) HandleSystemComplaintCount(c echo.Context) error {
-	count, err := h.server.DB.CountSystemComplaint()
+	counts, err := h.server.DB.CountSystemComplaintByStatus()
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
+	byStatus := map[models.SystemComplaintStatus]int{
+		models.SYSTEM_COMPLAINT_STATUS_OPEN:        0,
+		models.SYSTEM_COMPLAINT_STATUS_IN_PROGRESS: 0,
+		models.SYSTEM_COMPLAINT_STATUS_RESOLVED:    0,
+		models.SYSTEM_COMPLAINT_STATUS_CLOSED:      0,
+	}
+
+	total := 0
+	for _, count := range counts {
+		byStatus[count.Status] = count.Count
+		total += count.Count
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"count": count,
+		"count":    total,
+		"byStatus": byStatus,
 	})
 }
 
 func (h *complaintHandler) HandleGetSystemComplaint(c echo.Context) error {
-	complaints, err := h.server.DB.FindAllSystemComplaints()
+	filter := &request.SystemComplaintFilter{}
+	if err := c.Bind(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid filter")
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid status")
+	}
+
+	if filter.Priority != "" && !filter.Priority.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid priority")
+	}
+
+	complaints, err := h.server.DB.FindAllSystemComplaints(filter)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -71,6 +100,196 @@ func (h *complaintHandler) HandleGetSystemComplaintById(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
 	}
 
+	return h.systemComplaintDetails(c, complaint)
+}
+
+func (h *complaintHandler) HandleGetOwnSystemComplaints(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	complaints, err := h.server.DB.FindSystemComplaintsByReporter(userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for _, complaint := range complaints {
+		if decrypted, err := h.server.Encrypt.DecryptString(complaint.Title); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		} else {
+			complaint.Title = decrypted
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"complaints": complaints,
+	})
+}
+
+func (h *complaintHandler) HandleGetOwnSystemComplaint(c echo.Context) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	complaint, err := h.server.DB.FindSystemComplaintById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	}
+
+	if complaint.ReporterId == nil || *complaint.ReporterId != userId {
+		return echo.NewHTTPError(http.StatusForbidden, "you did not file this complaint")
+	}
+
+	// Staff assignment is internal to the triage workflow
+	complaint.AssignedTo = nil
+
+	return h.systemComplaintDetails(c, complaint)
+}
+
+func (h *complaintHandler) HandleTriageSystemComplaint(c echo.Context) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	req := &request.TriageSystemComplaint{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	} else {
+		req.Id = id
+	}
+
+	if !req.Status.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid status")
+	}
+
+	if !req.Priority.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid priority")
+	}
+
+	if _, err := h.server.DB.FindSystemComplaintById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	}
+
+	if req.AssignedTo != nil {
+		if _, err := h.server.DB.FindAdminById(*req.AssignedTo); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "staff not found")
+		}
+	}
+
+	if err := h.server.DB.TriageSystemComplaint(req); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":     "Complaint updated successfully",
+		"complaintId": id,
+	})
+}
+
+func (h *complaintHandler) HandleStaffSystemComplaintReply(c echo.Context) error {
+	return h.replyToSystemComplaint(c, models.COMPLAINT_REPLY_ROLE_STAFF)
+}
+
+func (h *complaintHandler) HandleReporterSystemComplaintReply(c echo.Context) error {
+	return h.replyToSystemComplaint(c, models.COMPLAINT_REPLY_ROLE_REPORTER)
+}
+
+func (h *complaintHandler) replyToSystemComplaint(c echo.Context, role models.ComplaintReplyRole) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	req := &request.ComplaintReply{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	authorId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	complaint, err := h.server.DB.FindSystemComplaintById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	}
+
+	if role == models.COMPLAINT_REPLY_ROLE_REPORTER && (complaint.ReporterId == nil || *complaint.ReporterId != authorId) {
+		return echo.NewHTTPError(http.StatusForbidden, "you did not file this complaint")
+	}
+
+	if complaint.Status == models.SYSTEM_COMPLAINT_STATUS_CLOSED {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "complaint is already closed")
+	}
+
+	// Replies can only be threaded under replies of the same complaint
+	if req.ParentId != nil {
+		replies, err := h.server.DB.FindSystemComplaintReplies(id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+
+		found := false
+		for _, reply := range replies {
+			if reply.Id == *req.ParentId {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return echo.NewHTTPError(http.StatusBadRequest, "parent reply not found")
+		}
+	}
+
+	model := &models.SystemComplaintReplyModel{
+		ComplaintId: id,
+		ParentId:    req.ParentId,
+		AuthorId:    authorId,
+		AuthorRole:  role,
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Content); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		model.Content = cipher
+	}
+
+	replyId, err := h.server.DB.NewSystemComplaintReply(model)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"message": "Reply posted successfully",
+		"replyId": replyId,
+	})
+}
+
+func (h *complaintHandler) systemComplaintDetails(c echo.Context, complaint *models.SystemComplaintModel) error {
 	if decrypted, err := h.server.Encrypt.DecryptString(complaint.Title); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
 	} else {
@@ -88,9 +307,23 @@ func (h *complaintHandler) HandleGetSystemComplaintById(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
+	replies, err := h.server.DB.FindSystemComplaintReplies(complaint.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for i := range replies {
+		if decrypted, err := h.server.Encrypt.DecryptString(replies[i].Content); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		} else {
+			replies[i].Content = decrypted
+		}
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
 		"complaint": complaint,
 		"images":    images,
+		"replies":   replies,
 	})
 }
 
@@ -104,8 +337,28 @@ func (h *complaintHandler) HandleSystemComplaint(c echo.Context) error {
 	}
 
 	req := &request.SystemComplaint{
-		Title:  title,
-		Detail: detail,
+		Title:    title,
+		Detail:   detail,
+		Priority: models.ComplaintPriority(c.FormValue("priority")),
+	}
+
+	if req.Priority == "" {
+		req.Priority = models.COMPLAINT_PRIORITY_MEDIUM
+	}
+
+	if !req.Priority.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid priority")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.ReporterId = userId
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
 	}
 
 	if cipher, err := h.server.Encrypt.EncryptString(req.Title); err != nil {
@@ -149,13 +402,408 @@ func (h *complaintHandler) HandleSystemComplaint(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusCreated, utils.Mapper{
-		"message": "System complaint created successfully",
+		"message":     "System complaint created successfully",
+		"complaintId": complaintId,
 	})
 }
 
 func (h *complaintHandler) HandleVendorComplaint(c echo.Context) error {
-	// TODO: implement filing a complaint for a vendor
+	vendorId, err := strconv.Atoi(c.FormValue("vendorId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "vendor ID must be a number")
+	}
+
+	transactionId, err := strconv.Atoi(c.FormValue("transactionId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	req := &request.NewComplaint{
+		VendorId:      vendorId,
+		TransactionId: transactionId,
+		Title:         c.FormValue("title"),
+		Content:       c.FormValue("content"),
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.ComplainantId = userId
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	// Validate that the complaint is tied to a transaction between the complainant and the vendor
+	if transaction, err := h.server.DB.FindTransactionById(req.TransactionId); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "transaction not found")
+	} else {
+		if transaction.ClientId != req.ComplainantId {
+			return echo.NewHTTPError(http.StatusForbidden, "you're not the client of this transaction")
+		}
+
+		if transaction.VendorId != req.VendorId {
+			return echo.NewHTTPError(http.StatusBadRequest, "vendor is not part of this transaction")
+		}
+	}
+
+	files, err := filehandler.FormParser(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Title); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		req.Title = cipher
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Content); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		req.Content = cipher
+	}
+
+	imageUrl := make([]string, 0)
+	for _, file := range files {
+		handler := filehandler.NewFileHandler(h.server.Encrypt)
+		url, err := handler.SavePhoto(file, h.server.Storage.SaveVendorComplaint)
+		if err != nil {
+			h.deleteFiles(imageUrl)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+
+		imageUrl = append(imageUrl, url)
+	}
+
+	complaintId, err := h.server.DB.FileVendorComplaint(req, imageUrl)
+	if err != nil {
+		h.deleteFiles(imageUrl)
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"message":     "Vendor complaint filed successfully",
+		"complaintId": complaintId,
+	})
+}
+
+// Photos saved for a complaint that could not be filed are removed again
+func (h *complaintHandler) deleteFiles(urls []string) {
+	for _, url := range urls {
+		if err := h.server.Storage.DeleteFile(url); err != nil {
+			log.Printf("Failed to delete %s: %s\n", url, err.Error())
+		}
+	}
+}
+
+func (h *complaintHandler) HandleGetOwnVendorComplaints(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	complaints, err := h.server.DB.FindVendorComplaintsByComplainant(userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for _, complaint := range complaints {
+		if decrypted, err := h.server.Encrypt.DecryptString(complaint.Title); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		} else {
+			complaint.Title = decrypted
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"complaints": complaints,
+	})
+}
+
+func (h *complaintHandler) HandleGetOwnVendorComplaint(c echo.Context) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	complaint, err := h.server.DB.FindVendorComplaintById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	}
+
+	if complaint.ComplainantId == nil || *complaint.ComplainantId != userId {
+		return echo.NewHTTPError(http.StatusForbidden, "you did not file this complaint")
+	}
+
+	if err := h.decryptVendorComplaint(complaint); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	}
+
+	// Staff assignment is internal to the moderation queue
+	complaint.AssignedTo = nil
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"complaint": complaint,
+	})
+}
+
+func (h *complaintHandler) HandleVendorComplaintCount(c echo.Context) error {
+	status := models.VendorComplaintStatus(c.QueryParam("status"))
+
+	count, err := h.server.DB.CountVendorComplaint(status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"count": count,
+	})
+}
+
+func (h *complaintHandler) HandleGetVendorComplaints(c echo.Context) error {
+	status := models.VendorComplaintStatus(c.QueryParam("status"))
+
+	complaints, err := h.server.DB.FindAllVendorComplaints(status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for _, complaint := range complaints {
+		if decrypted, err := h.server.Encrypt.DecryptString(complaint.Title); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		} else {
+			complaint.Title = decrypted
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"complaints": complaints,
+	})
+}
+
+func (h *complaintHandler) HandleGetVendorComplaintById(c echo.Context) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	complaint, err := h.server.DB.FindVendorComplaintById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	}
+
+	if err := h.decryptVendorComplaint(complaint); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	}
+
+	evidence, err := h.server.DB.FindVendorComplaintEvidence(complaint.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	comments, err := h.server.DB.FindVendorComplaintComments(complaint.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for i := range comments {
+		if decrypted, err := h.server.Encrypt.DecryptString(comments[i].Comment); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		} else {
+			comments[i].Comment = decrypted
+		}
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"message": "Vendor complaint route",
+		"complaint": complaint,
+		"evidence":  evidence,
+		"comments":  comments,
 	})
 }
+
+func (h *complaintHandler) HandleAssignVendorComplaint(c echo.Context) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	req := &request.AssignComplaint{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if _, err := h.server.DB.FindAdminById(req.StaffId); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "staff not found")
+	}
+
+	if err := h.server.DB.AssignVendorComplaint(id, req.StaffId); err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":     "Complaint assigned successfully",
+		"complaintId": id,
+	})
+}
+
+func (h *complaintHandler) HandleCommentVendorComplaint(c echo.Context) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	req := &request.ComplaintComment{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if _, err := h.server.DB.FindVendorComplaintById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	}
+
+	model := &models.VendorComplaintCommentModel{
+		ComplaintId: id,
+		AdminId:     adminId,
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Comment); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		model.Comment = cipher
+	}
+
+	commentId, err := h.server.DB.NewVendorComplaintComment(model)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"message":   "Comment added successfully",
+		"commentId": commentId,
+	})
+}
+
+func (h *complaintHandler) HandleResolveVendorComplaint(c echo.Context) error {
+	return h.closeVendorComplaint(c, models.VENDOR_COMPLAINT_STATUS_RESOLVED)
+}
+
+func (h *complaintHandler) HandleDismissVendorComplaint(c echo.Context) error {
+	return h.closeVendorComplaint(c, models.VENDOR_COMPLAINT_STATUS_DISMISSED)
+}
+
+func (h *complaintHandler) closeVendorComplaint(c echo.Context, status models.VendorComplaintStatus) error {
+	complaintId := c.Param("complaintId")
+	id, err := strconv.Atoi(complaintId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "complaint ID must be a number")
+	}
+
+	req := &request.ResolveComplaint{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	req.Id = id
+	req.Status = status
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.AdminId = adminId
+	}
+
+	switch {
+	case status == models.VENDOR_COMPLAINT_STATUS_DISMISSED:
+		req.Resolution = models.COMPLAINT_RESOLUTION_NONE
+	case req.Resolution == "":
+		req.Resolution = models.COMPLAINT_RESOLUTION_NONE
+	case req.Resolution != models.COMPLAINT_RESOLUTION_NONE &&
+		req.Resolution != models.COMPLAINT_RESOLUTION_WARNING &&
+		req.Resolution != models.COMPLAINT_RESOLUTION_RESTRICTION:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid resolution")
+	}
+
+	complaint, err := h.server.DB.FindVendorComplaintById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "complaint not found")
+	} else {
+		req.VendorId = complaint.VendorId
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Note); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		req.Note = cipher
+	}
+
+	if err := h.server.DB.ResolveVendorComplaint(req); err != nil {
+		if errors.Is(err, models.ErrVendorAlreadyRestricted) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":     "Complaint " + string(status),
+		"complaintId": id,
+		"resolution":  req.Resolution,
+	})
+}
+
+func (h *complaintHandler) decryptVendorComplaint(complaint *models.VendorComplaintModel) error {
+	if decrypted, err := h.server.Encrypt.DecryptString(complaint.Title); err != nil {
+		return err
+	} else {
+		complaint.Title = decrypted
+	}
+
+	if decrypted, err := h.server.Encrypt.DecryptString(complaint.Content); err != nil {
+		return err
+	} else {
+		complaint.Content = decrypted
+	}
+
+	if complaint.ResolutionNote != "" {
+		if decrypted, err := h.server.Encrypt.DecryptString(complaint.ResolutionNote); err != nil {
+			return err
+		} else {
+			complaint.ResolutionNote = decrypted
+		}
+	}
+
+	return nil
+}
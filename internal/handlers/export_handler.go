@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"nearbyassist/internal/export"
+	"nearbyassist/internal/hash"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/server"
+	"nearbyassist/internal/utils"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Rows are held back until this much is written, an export that fails before
+// that still gets an error response instead of a truncated file
+const EXPORT_BUFFER_SIZE = 64 * 1024
+
+type exportHandler struct {
+	server *server.Server
+}
+
+func NewExportHandler(server *server.Server) *exportHandler {
+	return &exportHandler{
+		server: server,
+	}
+}
+
+func (h *exportHandler) HandleExportUsers(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_USERS)
+}
+
+func (h *exportHandler) HandleExportVendors(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_VENDORS)
+}
+
+func (h *exportHandler) HandleExportApplications(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_APPLICATIONS)
+}
+
+func (h *exportHandler) HandleExportTransactions(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_TRANSACTIONS)
+}
+
+func (h *exportHandler) HandleExportSystemComplaints(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_SYSTEM_COMPLAINTS)
+}
+
+func (h *exportHandler) HandleExportVendorComplaints(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_VENDOR_COMPLAINTS)
+}
+
+func (h *exportHandler) HandleExportVerifications(c echo.Context) error {
+	return h.handleExport(c, models.EXPORT_LIST_VERIFICATIONS)
+}
+
+func (h *exportHandler) HandleGetExportAudits(c echo.Context) error {
+	audits, err := h.server.DB.FindExportAudits()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"exports": audits,
+	})
+}
+
+// Defaults to CSV. Encrypted columns are decrypted for the roles allowed to
+// read them and redacted for everyone else
+func (h *exportHandler) handleExport(c echo.Context, list models.ExportList) error {
+	filter := &request.ExportFilter{}
+	if err := c.Bind(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid filter")
+	}
+
+	filter.List = list
+	// The application list filters its status with the filter parameter
+	if list == models.EXPORT_LIST_APPLICATIONS {
+		filter.Status = c.QueryParam("filter")
+	}
+
+	if filter.Format == "" {
+		filter.Format = models.EXPORT_FORMAT_CSV
+	}
+
+	if !filter.Format.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be csv or xlsx")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	claims, err := h.server.Auth.GetClaims(authHeader[len("Bearer "):])
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+
+	role, err := utils.GetRoleFromClaims(claims)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	audit := &models.ExportAuditModel{
+		AdminId:   adminId,
+		List:      list,
+		Format:    filter.Format,
+		Filters:   exportFilters(filter),
+		Decrypted: models.CanDecryptExport(role),
+	}
+
+	if id, err := h.server.DB.NewExportAudit(audit); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		audit.Id = id
+	}
+
+	filename := export.Filename(list, filter.Format, time.Now().Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentType, export.ContentType(filter.Format))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	buffer := bufio.NewWriterSize(c.Response(), EXPORT_BUFFER_SIZE)
+	err = h.stream(buffer, filter, audit)
+	if err == nil {
+		err = buffer.Flush()
+	}
+
+	audit.Status = models.EXPORT_STATUS_COMPLETED
+	if err != nil {
+		audit.Status = models.EXPORT_STATUS_FAILED
+		audit.Error = err.Error()
+	}
+
+	if err := h.server.DB.FinishExportAudit(audit); err != nil {
+		log.Printf("Failed to finish export audit %d: %s\n", audit.Id, err.Error())
+	}
+
+	if err != nil {
+		// Once rows were sent the status cannot change anymore and the client
+		// is left with a truncated file
+		if !c.Response().Committed {
+			c.Response().Header().Del(echo.HeaderContentDisposition)
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (h *exportHandler) stream(w io.Writer, filter *request.ExportFilter, audit *models.ExportAuditModel) error {
+	writer, err := export.NewWriter(filter.Format, w)
+	if err != nil {
+		return err
+	}
+
+	columns := models.ExportColumns[filter.List]
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+	}
+
+	if err := writer.Write(titles); err != nil {
+		return err
+	}
+
+	err = h.server.DB.StreamExport(filter, func(record []string) error {
+		for i, column := range columns {
+			if !column.Encrypted || record[i] == "" {
+				continue
+			}
+
+			if !audit.Decrypted {
+				record[i] = models.EXPORT_REDACTED
+				continue
+			}
+
+			if decrypted, err := h.server.Encrypt.DecryptString(record[i]); err != nil {
+				return errors.New(hash.HASH_ERROR)
+			} else {
+				record[i] = decrypted
+			}
+		}
+
+		audit.Rows++
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// The filters recorded in the audit trail, in query string form
+func exportFilters(filter *request.ExportFilter) string {
+	values := url.Values{}
+	if filter.Status != "" {
+		values.Set("status", filter.Status)
+	}
+
+	if filter.Priority != "" {
+		values.Set("priority", string(filter.Priority))
+	}
+
+	if filter.AssignedTo != 0 {
+		values.Set("assignedTo", strconv.Itoa(filter.AssignedTo))
+	}
+
+	return values.Encode()
+}
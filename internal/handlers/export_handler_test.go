@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"nearbyassist/internal/authenticator"
+	"nearbyassist/internal/config"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/encryption"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/routes"
+	"nearbyassist/internal/server"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type exportTest struct {
+	server *server.Server
+	fake   *dbtest.Fake
+}
+
+func newExportTest(t *testing.T) *exportTest {
+	conf := &config.Config{JwtSecret: "secret", JwtDuration: 60, EncryptionKey: "0123456789abcdef0123456789abcdef"}
+	crypto := encryption.NewAes(conf)
+
+	name, _ := crypto.EncryptString("Juan Dela Cruz")
+	email, _ := crypto.EncryptString("juan@example.com")
+
+	fake := dbtest.NewFake()
+	fake.ExportRows = [][]string{{"1", name, email, "", "2026-10-18 09:00:00"}}
+
+	s := &server.Server{
+		Echo:    echo.New(),
+		DB:      fake,
+		Auth:    authenticator.NewJWTAuthenticator(conf),
+		Encrypt: crypto,
+	}
+	routes.RegisterRoutes(s)
+
+	return &exportTest{server: s, fake: fake}
+}
+
+func (e *exportTest) get(t *testing.T, role models.AdminRole, url string) *httptest.ResponseRecorder {
+	token, err := e.server.Auth.GenerateAdminAccessToken(&models.AdminModel{Model: models.Model{Id: 2}, Role: role})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %s", err.Error())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+
+	rec := httptest.NewRecorder()
+	e.server.Echo.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestStaffExportIsRedacted(t *testing.T) {
+	test := newExportTest(t)
+
+	rec := test.get(t, models.ADMIN_ROLE_STAFF, "/v1/admin/exports/users")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "1,[redacted],[redacted],,2026-10-18 09:00:00")
+	assert.NotContains(t, rec.Body.String(), "Juan")
+
+	assert.Len(t, test.fake.ExportAudits, 1)
+	assert.Equal(t, 2, test.fake.ExportAudits[0].AdminId)
+	assert.False(t, test.fake.ExportAudits[0].Decrypted)
+	assert.Equal(t, models.EXPORT_STATUS_COMPLETED, test.fake.ExportAudits[0].Status)
+}
+
+func TestAdminExportIsDecrypted(t *testing.T) {
+	test := newExportTest(t)
+
+	rec := test.get(t, models.ADMIN_ROLE_ADMIN, "/v1/admin/exports/users")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "1,Juan Dela Cruz,juan@example.com,,2026-10-18 09:00:00")
+	assert.True(t, test.fake.ExportAudits[0].Decrypted)
+}
+
+func TestStaffCannotLeaveExports(t *testing.T) {
+	test := newExportTest(t)
+
+	assert.Equal(t, http.StatusForbidden, test.get(t, models.ADMIN_ROLE_STAFF, "/v1/admin/exports/audit").Code)
+	assert.Equal(t, http.StatusForbidden, test.get(t, models.ADMIN_ROLE_STAFF, "/v1/admin/users").Code)
+}
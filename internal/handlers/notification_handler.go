@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/server"
+	"nearbyassist/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type notificationHandler struct {
+	server *server.Server
+}
+
+func NewNotificationHandler(server *server.Server) *notificationHandler {
+	return &notificationHandler{
+		server: server,
+	}
+}
+
+func (h *notificationHandler) HandleGetNotifications(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	unreadOnly := c.QueryParam("unread") == "true"
+
+	notifications, err := h.server.DB.FindNotificationsByUser(userId, unreadOnly)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	unread, err := h.server.DB.CountUnreadNotifications(userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"notifications": notifications,
+		"unread":        unread,
+	})
+}
+
+func (h *notificationHandler) HandleMarkRead(c echo.Context) error {
+	notificationId := c.Param("notificationId")
+	id, err := strconv.Atoi(notificationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "notification ID must be a number")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if notification, err := h.server.DB.FindNotificationById(id); err != nil || notification.UserId != userId {
+		return echo.NewHTTPError(http.StatusNotFound, "notification not found")
+	}
+
+	if err := h.server.DB.MarkNotificationRead(id); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":        "Notification marked as read",
+		"notificationId": id,
+	})
+}
+
+func (h *notificationHandler) HandleMarkAllRead(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	count, err := h.server.DB.MarkAllNotificationsRead(userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message": "Notifications marked as read",
+		"count":   count,
+	})
+}
+
+func (h *notificationHandler) HandleRegisterDevice(c echo.Context) error {
+	req := &request.DeviceToken{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if !req.Platform.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid platform")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.UserId = userId
+	}
+
+	if err := h.server.DB.SaveDeviceToken(req); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"message": "Device registered",
+	})
+}
+
+func (h *notificationHandler) HandleUnregisterDevice(c echo.Context) error {
+	req := &request.DeviceToken{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if req.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.server.DB.DeleteDeviceToken(userId, req.Token); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message": "Device unregistered",
+	})
+}
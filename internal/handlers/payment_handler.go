@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/payment"
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/server"
+	"nearbyassist/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type paymentHandler struct {
+	server *server.Server
+}
+
+func NewPaymentHandler(server *server.Server) *paymentHandler {
+	return &paymentHandler{
+		server: server,
+	}
+}
+
+// Charges the client for the booked price and holds it in escrow until the
+// transaction is completed or cancelled
+func (h *paymentHandler) HandlePay(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	req := &request.NewPayment{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	transaction, err := h.server.DB.FindTransactionById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "transaction not found")
+	}
+
+	if transaction.ClientId != userId {
+		return echo.NewHTTPError(http.StatusForbidden, "you're not the client of this transaction")
+	}
+
+	if transaction.Status != models.TRANSACTION_STATUS_ONGOING {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "only ongoing transactions can be paid")
+	}
+
+	if transaction.Price == nil || transaction.Currency == nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "transaction has no price")
+	}
+
+	if _, err := h.server.DB.FindPaymentByTransaction(id); err == nil {
+		return echo.NewHTTPError(http.StatusConflict, "transaction is already paid")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	amount := models.RoundMoney(*transaction.Price)
+
+	record := &models.PaymentModel{
+		TransactionId: id,
+		ClientId:      transaction.ClientId,
+		VendorId:      transaction.VendorId,
+		Provider:      h.server.Payment.Name(),
+		Amount:        amount,
+		Currency:      *transaction.Currency,
+		Status:        models.PAYMENT_STATUS_PENDING,
+	}
+
+	// Concurrent attempts lose here, before anything is charged
+	if reserved, err := h.server.DB.ReservePayment(record); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if !reserved {
+		return echo.NewHTTPError(http.StatusConflict, "transaction is already paid")
+	}
+
+	charge, err := h.server.Payment.Authorize(req.Method, amount, *transaction.Currency, id)
+	if err != nil {
+		h.releaseReservation(record)
+
+		if errors.Is(err, payment.ErrDeclined) {
+			return echo.NewHTTPError(http.StatusPaymentRequired, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	record.Reference = charge.Reference
+	record.Status = models.PAYMENT_STATUS_CAPTURED
+
+	// The hold is kept when capture fails, the provider's webhook finishes it
+	if _, err := h.server.Payment.Capture(charge.Reference, amount); err != nil {
+		log.Printf("Failed to capture charge %s: %s\n", charge.Reference, err.Error())
+		record.Status = models.PAYMENT_STATUS_AUTHORIZED
+	}
+
+	if err := h.server.DB.ConfirmPayment(record); err != nil {
+		// A charge we could not record is given back rather than left behind
+		// where reconciliation cannot see it
+		if _, refundErr := h.server.Payment.Refund(charge.Reference, amount); refundErr != nil {
+			log.Printf("Failed to void unrecorded charge %s of payment %d: %s\n", charge.Reference, record.Id, refundErr.Error())
+		} else {
+			h.releaseReservation(record)
+		}
+
+		if errors.Is(err, models.ErrTransactionNotOngoing) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"paymentId": record.Id,
+		"status":    record.Status,
+	})
+}
+
+// A pending payment that is not released keeps the transaction from being
+// paid again, so failures are logged for follow up
+func (h *paymentHandler) releaseReservation(record *models.PaymentModel) {
+	if err := h.server.DB.DeletePendingPayment(record.Id); err != nil {
+		log.Printf("Failed to release pending payment %d: %s\n", record.Id, err.Error())
+	}
+}
+
+func (h *paymentHandler) HandleGetPayment(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	record, err := h.server.DB.FindPaymentByTransaction(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "payment not found")
+	}
+
+	if record.ClientId != userId && record.VendorId != userId {
+		return echo.NewHTTPError(http.StatusForbidden, "you are not part of this transaction")
+	}
+
+	entries, err := h.server.DB.FindPaymentEntries(record.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"payment": record,
+		"entries": entries,
+	})
+}
+
+// Called by the payment provider, authenticated by the webhook signature
+func (h *paymentHandler) HandlePaymentWebhook(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to read request body")
+	}
+
+	event, err := h.server.Payment.VerifyWebhook(c.Request().Header, body)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	applied, err := h.server.DB.ApplyPaymentWebhook(&models.PaymentWebhookEventModel{
+		Provider:  h.server.Payment.Name(),
+		EventId:   event.Id,
+		Type:      event.Type,
+		Reference: event.Reference,
+		Amount:    event.Amount,
+		Payload:   body,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"received":  true,
+		"duplicate": !applied,
+	})
+}
+
+func (h *paymentHandler) HandleGetBalances(c echo.Context) error {
+	balances, err := h.server.DB.FindLedgerBalances()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"balances": balances,
+	})
+}
+
+func (h *paymentHandler) HandleReconcile(c echo.Context) error {
+	report, err := payment.Reconcile(h.server.DB, h.server.Payment)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"report": report,
+	})
+}
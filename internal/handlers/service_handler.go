@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"nearbyassist/internal/hash"
 	"nearbyassist/internal/models"
 	"nearbyassist/internal/request"
 	"nearbyassist/internal/response"
+	"nearbyassist/internal/routing_engine"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
@@ -59,13 +62,28 @@ func (h *serviceHandler) HandleRegisterService(c echo.Context) error {
 		req.VendorId = userId
 	}
 
+	req.Currency = normalizeCurrency(req.Currency)
+	if req.CancellationPolicy == "" {
+		req.CancellationPolicy = models.CANCELLATION_POLICY_FLEXIBLE
+	}
+
 	if err := c.Validate(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if err := validatePricing(req.Rate, req.MinimumCharge, req.PricingUnit); err != nil {
+		return err
+	}
+
+	if err := validateCancellationPolicy(req.CancellationPolicy, req.CancellationWindows); err != nil {
+		return err
+	}
+
 	// Validate that the user is a registered vendor
-	if _, err := h.server.DB.FindVendorById(req.VendorId); err != nil {
+	if vendor, err := h.server.DB.FindVendorById(req.VendorId); err != nil {
 		return echo.NewHTTPError(http.StatusForbidden, "user is not a registered vendor")
+	} else if vendor.Restricted == 1 {
+		return echo.NewHTTPError(http.StatusForbidden, "vendor is restricted")
 	}
 
 	// encrypt description
@@ -80,6 +98,8 @@ func (h *serviceHandler) HandleRegisterService(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
 	}
 
+	h.server.Autocomplete.Invalidate()
+
 	return c.JSON(http.StatusCreated, utils.Mapper{
 		"serviceId": insertId,
 	})
@@ -104,12 +124,25 @@ func (h *serviceHandler) HandleUpdateService(c echo.Context) error {
 		req.VendorId = userId
 	}
 
+	req.Currency = normalizeCurrency(req.Currency)
+	if req.CancellationPolicy == "" {
+		req.CancellationPolicy = models.CANCELLATION_POLICY_FLEXIBLE
+	}
+
 	if err := c.Validate(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing required fields")
 	} else {
 		req.Id = id
 	}
 
+	if err := validatePricing(req.Rate, req.MinimumCharge, req.PricingUnit); err != nil {
+		return err
+	}
+
+	if err := validateCancellationPolicy(req.CancellationPolicy, req.CancellationWindows); err != nil {
+		return err
+	}
+
 	// Validate if the service id  is owned by the requester
 	if owner, err := h.server.DB.FindServiceOwner(req.Id); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "service not found")
@@ -119,6 +152,12 @@ func (h *serviceHandler) HandleUpdateService(c echo.Context) error {
 		}
 	}
 
+	if restricted, err := h.server.DB.IsVendorRestricted(req.VendorId); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if restricted {
+		return echo.NewHTTPError(http.StatusForbidden, "vendor is restricted")
+	}
+
 	if cipher, err := h.server.Encrypt.EncryptString(req.Description); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
 	} else {
@@ -126,9 +165,16 @@ func (h *serviceHandler) HandleUpdateService(c echo.Context) error {
 	}
 
 	if err := h.server.DB.UpdateService(req); err != nil {
+		var unknownTags *models.UnknownTagsError
+		if errors.As(err, &unknownTags) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
+	h.server.Autocomplete.Invalidate()
+
 	return c.JSON(http.StatusOK, utils.Mapper{
 		"message":   "Service updated",
 		"serviceId": serviceId,
@@ -157,10 +203,18 @@ func (h *serviceHandler) HandleDeleteService(c echo.Context) error {
 		}
 	}
 
+	if restricted, err := h.server.DB.IsVendorRestricted(userId); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if restricted {
+		return echo.NewHTTPError(http.StatusForbidden, "vendor is restricted")
+	}
+
 	if err := h.server.DB.DeleteService(id); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
+	h.server.Autocomplete.Invalidate()
+
 	return c.JSON(http.StatusNoContent, nil)
 }
 
@@ -191,12 +245,20 @@ func (h *serviceHandler) HandleSearchService(c echo.Context) error {
 			break
 		}
 
+		distance := service.Distance
 		res := response.SearchResult{
 			Id:             service.Id,
 			Suggestability: score,
 			Vendor:         decrypted,
+			Title:          service.Title,
+			Rate:           service.Rate,
+			PricingUnit:    string(service.PricingUnit),
+			Currency:       service.Currency,
+			Rating:         service.Rating,
+			Verified:       service.Verified,
 			Latitude:       service.Latitude,
 			Longitude:      service.Longitude,
+			Distance:       &distance,
 		}
 
 		searchResult = append(searchResult, res)
@@ -206,13 +268,130 @@ func (h *serviceHandler) HandleSearchService(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, scoreError.Error())
 	}
 
-	// sort services by Suggestability
-	sortedResult := utils.BubbleSort(searchResult)
+	origin := models.NewLocationWithData(params.Latitude, params.Longitude)
+	searchResult, err = h.annotateTravel(origin, searchResult, routing_engine.Profile(params.Profile))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if params.MaxDuration > 0 {
+		searchResult = filterByDuration(searchResult, params.MaxDuration)
+	}
+
+	utils.SortSearchResults(searchResult, params.SortBy)
+
+	// Vendors finding their own services is not an impression
+	viewer := viewerId(h.server, c)
+	owned := make(map[int]bool)
+	for _, service := range services {
+		if service.VendorId == viewer {
+			owned[service.Id] = true
+		}
+	}
+
+	shown := make([]int, 0, len(searchResult))
+	for i := range searchResult {
+		searchResult[i].Rank = i + 1
+		if !owned[searchResult[i].Id] {
+			shown = append(shown, searchResult[i].Id)
+		}
+	}
 
-	// TODO: Generate ranks for the sorted services
+	h.server.Activity.RecordImpressions(shown)
 
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"services": sortedResult,
+		"services": searchResult,
+	})
+}
+
+// Adds the road distance and duration from the origin to every result,
+// straight line estimates are used when the engine can not answer
+func (h *serviceHandler) annotateTravel(origin *models.Location, results []response.SearchResult, profile routing_engine.Profile) ([]response.SearchResult, error) {
+	if profile == "" {
+		profile = routing_engine.PROFILE_DRIVING
+	} else if !profile.IsValid() {
+		return nil, errors.New("profile must be one of driving, walking or cycling")
+	}
+
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	destinations := make([]models.Location, 0, len(results))
+	for _, result := range results {
+		destinations = append(destinations, *models.NewLocationWithData(result.Latitude, result.Longitude))
+	}
+
+	table, err := h.server.RouteEngine.FindTable(origin, destinations, profile)
+	if err != nil {
+		log.Printf("error finding travel times, using estimates: %s\n", err.Error())
+
+		if table, err = routing_engine.NewFallback().FindTable(origin, destinations, profile); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, entry := range table.Entries {
+		results[i].Approximate = table.Approximate
+		if !entry.Reachable {
+			continue
+		}
+
+		// Road distance replaces the straight line distance from the search
+		distance, duration := entry.Distance, entry.Duration
+		results[i].Distance = &distance
+		results[i].Duration = &duration
+	}
+
+	return results, nil
+}
+
+// Drops results that are unreachable or further than the given seconds away
+func filterByDuration(results []response.SearchResult, maxDuration float64) []response.SearchResult {
+	filtered := make([]response.SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Duration != nil && *result.Duration <= maxDuration {
+			filtered = append(filtered, result)
+		}
+	}
+
+	return filtered
+}
+
+// takes bbox=minLat,minLong,maxLat,maxLong and zoom as QueryString, q=tags is optional
+func (h *serviceHandler) HandleServiceMap(c echo.Context) error {
+	params, err := utils.GetMapParams(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	services, truncated, err := h.server.DB.FindServicesInBounds(params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	clusters, singles := utils.ClusterServices(services, params.Zoom)
+
+	pins := make([]response.MapService, 0, len(singles))
+	for _, service := range singles {
+		decrypted, err := h.server.Encrypt.DecryptString(service.Vendor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		}
+
+		pins = append(pins, response.MapService{
+			Id:        service.Id,
+			Vendor:    decrypted,
+			Latitude:  service.Latitude,
+			Longitude: service.Longitude,
+		})
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"zoom":      params.Zoom,
+		"clusters":  clusters,
+		"services":  pins,
+		"truncated": truncated,
 	})
 }
 
@@ -235,6 +414,14 @@ func (h *serviceHandler) HandleGetDetails(c echo.Context) error {
 		service.Tags = tags
 	}
 
+	if addOns, err := h.server.DB.FindServiceAddOns(id); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		service.AddOns = addOns
+	}
+
+	service.CancellationWindows = models.ResolveCancellationWindows(service.CancellationPolicy, service.CancellationWindows)
+
 	// Get vendor info
 	vendor, err := h.server.DB.FindVendorByService(service.ServiceId)
 	if err != nil {
@@ -247,6 +434,10 @@ func (h *serviceHandler) HandleGetDetails(c echo.Context) error {
 		vendor.Vendor = decrypted
 	}
 
+	if viewerId(h.server, c) != vendor.VendorId {
+		h.server.Activity.RecordServiceView(service.ServiceId)
+	}
+
 	// Get count per review rating
 	reviews, err := h.server.DB.FindAllReviewByService(service.ServiceId)
 	if err != nil {
@@ -301,6 +492,7 @@ func (h *serviceHandler) HandleGetByVendor(c echo.Context) error {
 }
 
 // takes origin as QueryString ex: origin=lat,long
+// optional: profile=driving|walking|cycling, alternatives=true, steps=true
 func (h *serviceHandler) HandleFindRoute(c echo.Context) error {
 	serviceId := c.Param("serviceId")
 	id, err := strconv.Atoi(serviceId)
@@ -318,20 +510,49 @@ func (h *serviceHandler) HandleFindRoute(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid origin coordinates")
 	}
 
+	options := routing_engine.RouteOptions{
+		Profile:      routing_engine.Profile(c.QueryParam("profile")),
+		Alternatives: c.QueryParam("alternatives") == "true",
+		Steps:        c.QueryParam("steps") == "true",
+	}
+
+	if options.Profile == "" {
+		options.Profile = routing_engine.PROFILE_DRIVING
+	} else if !options.Profile.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "profile must be one of driving, walking or cycling")
+	}
+
 	destination := models.NewLocationWithData(service.Latitude, service.Longitude)
 
-	polyline, err := h.server.RouteEngine.FindRoute(origin, destination)
+	result, err := h.server.RouteEngine.FindRoute(origin, destination, options)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Could not find routes at the moment")
+		switch {
+		case errors.Is(err, routing_engine.ErrNoRoute), errors.Is(err, routing_engine.ErrNoSegment):
+			return echo.NewHTTPError(http.StatusNotFound, "No route found to this service")
+		case errors.Is(err, routing_engine.ErrInvalidQuery), errors.Is(err, routing_engine.ErrTooBig):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Could not find routes at the moment")
 	}
 
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"polyline": polyline,
+		"profile":      result.Profile,
+		"approximate":  result.Approximate,
+		"polyline":     result.Route.Polyline,
+		"distance":     result.Route.Distance,
+		"duration":     result.Route.Duration,
+		"steps":        result.Route.Steps,
+		"alternatives": result.Alternatives,
 	})
 }
 
 func parseOrigin(query string) (*models.Location, error) {
 	coords := strings.Split(query, ",")
+	if len(coords) != 2 {
+		return nil, errors.New("origin must be in the form lat,long")
+	}
+
 	lat, err := strconv.ParseFloat(coords[0], 64)
 	if err != nil {
 		return nil, err
@@ -346,3 +567,63 @@ func parseOrigin(query string) (*models.Location, error) {
 
 	return origin, nil
 }
+
+// Services without a currency are priced in the default one
+func normalizeCurrency(currency string) string {
+	if currency = strings.ToUpper(strings.TrimSpace(currency)); currency == "" {
+		return models.DEFAULT_CURRENCY
+	}
+
+	return currency
+}
+
+// A minimum charge only makes sense when the price depends on the time spent
+// or the number of visits
+func validatePricing(rate, minimumCharge float64, unit models.PricingUnit) error {
+	if unit == models.PRICING_UNIT_FIXED && minimumCharge > 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "fixed price services cannot have a minimum charge")
+	}
+
+	if minimumCharge > 0 && minimumCharge < rate {
+		return echo.NewHTTPError(http.StatusBadRequest, "minimum charge must not be lower than the rate")
+	}
+
+	return nil
+}
+
+// Only custom policies carry their own windows, each lead time at most once
+func validateCancellationPolicy(policy models.CancellationPolicy, windows models.CancellationWindows) error {
+	if policy != models.CANCELLATION_POLICY_CUSTOM {
+		if len(windows) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "cancellation windows can only be set for a custom policy")
+		}
+
+		return nil
+	}
+
+	if len(windows) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "custom cancellation policy requires at least one window")
+	}
+
+	seen := make(map[int]bool)
+	for _, window := range windows {
+		if seen[window.HoursBefore] {
+			return echo.NewHTTPError(http.StatusBadRequest, "cancellation windows must not share the same hours")
+		}
+		seen[window.HoursBefore] = true
+	}
+
+	return nil
+}
+
+// The signed in user, or 0 when the token can not be read. Only used to
+// leave vendors' own visits out of their stats
+func viewerId(server *server.Server, c echo.Context) int {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(server.Auth, authHeader)
+	if err != nil {
+		return 0
+	}
+
+	return userId
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -19,12 +22,425 @@ func NewTagHandler(server *server.Server) *tagHandler {
 }
 
 func (h *tagHandler) HandleGetTags(c echo.Context) error {
-	tags, err := h.server.DB.FindAllTags()
+	tags, err := h.server.DB.FindAllTags(false)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
+	categories, err := h.server.DB.FindAllTagCategories()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"tags":       tags,
+		"categories": categories,
+	})
+}
+
+func (h *tagHandler) HandleAutocomplete(c echo.Context) error {
+	params, err := utils.GetAutocompleteParams(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var origin *models.Location
+	if params.HasLocation {
+		origin = &models.Location{Latitude: params.Latitude, Longitude: params.Longitude}
+	}
+
+	suggestions := h.server.Autocomplete.Suggest(params.Query, origin, params.Radius, params.Limit)
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"suggestions": suggestions,
+	})
+}
+
+func (h *tagHandler) HandleGetTaxonomy(c echo.Context) error {
+	tags, err := h.server.DB.FindAllTags(true)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	categories, err := h.server.DB.FindAllTagCategories()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	synonyms, err := h.server.DB.FindAllTagSynonyms()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"tags":       tags,
+		"categories": categories,
+		"synonyms":   synonyms,
+	})
+}
+
+func (h *tagHandler) HandleNewTag(c echo.Context) error {
+	req := &request.NewTag{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if err := h.checkCategory(req.CategoryId); err != nil {
+		return err
+	}
+
+	if err := h.checkTitleAvailable(req.Title, 0); err != nil {
+		return err
+	}
+
+	tagId, err := h.server.DB.NewTag(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"tagId": tagId,
+	})
+}
+
+func (h *tagHandler) HandleUpdateTag(c echo.Context) error {
+	tagId := c.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "tag ID must be a number")
+	}
+
+	req := &request.UpdateTag{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+	req.Id = id
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if _, err := h.findActiveTag(id); err != nil {
+		return err
+	}
+
+	if err := h.checkCategory(req.CategoryId); err != nil {
+		return err
+	}
+
+	if err := h.checkTitleAvailable(req.Title, id); err != nil {
+		return err
+	}
+
+	if err := h.server.DB.UpdateTag(req); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"tags": tags,
+		"message": "Tag updated successfully",
+		"tagId":   id,
 	})
 }
+
+func (h *tagHandler) HandleMergeTag(c echo.Context) error {
+	tagId := c.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "tag ID must be a number")
+	}
+
+	req := &request.MergeTag{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+	req.SourceId = id
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if req.SourceId == req.TargetId {
+		return echo.NewHTTPError(http.StatusBadRequest, "a tag cannot be merged into itself")
+	}
+
+	if _, err := h.findActiveTag(req.SourceId); err != nil {
+		return err
+	}
+
+	if _, err := h.findActiveTag(req.TargetId); err != nil {
+		return err
+	}
+
+	if err := h.server.DB.MergeTag(req.SourceId, req.TargetId); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message": "Tag merged successfully",
+		"tagId":   req.TargetId,
+	})
+}
+
+func (h *tagHandler) HandleRetireTag(c echo.Context) error {
+	tagId := c.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "tag ID must be a number")
+	}
+
+	req := &request.RetireTag{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+	req.Id = id
+
+	if _, err := h.findActiveTag(id); err != nil {
+		return err
+	}
+
+	if req.ReplacementId != nil {
+		if *req.ReplacementId == id {
+			return echo.NewHTTPError(http.StatusBadRequest, "a tag cannot replace itself")
+		}
+
+		if _, err := h.findActiveTag(*req.ReplacementId); err != nil {
+			return err
+		}
+	}
+
+	if err := h.server.DB.RetireTag(req.Id, req.ReplacementId); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message": "Tag retired successfully",
+		"tagId":   id,
+	})
+}
+
+func (h *tagHandler) HandleNewSynonym(c echo.Context) error {
+	tagId := c.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "tag ID must be a number")
+	}
+
+	req := &request.NewTagSynonym{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+	req.TagId = id
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if _, err := h.findActiveTag(id); err != nil {
+		return err
+	}
+
+	if err := h.checkTitleAvailable(req.Title, 0); err != nil {
+		return err
+	}
+
+	synonymId, err := h.server.DB.NewTagSynonym(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"synonymId": synonymId,
+	})
+}
+
+func (h *tagHandler) HandleDeleteSynonym(c echo.Context) error {
+	synonymId := c.Param("synonymId")
+	id, err := strconv.Atoi(synonymId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "synonym ID must be a number")
+	}
+
+	if err := h.server.DB.DeleteTagSynonym(id); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":   "Synonym deleted successfully",
+		"synonymId": id,
+	})
+}
+
+func (h *tagHandler) HandleGetCategories(c echo.Context) error {
+	categories, err := h.server.DB.FindAllTagCategories()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"categories": categories,
+	})
+}
+
+func (h *tagHandler) HandleNewCategory(c echo.Context) error {
+	req := &request.NewTagCategory{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if err := h.checkCategory(req.ParentId); err != nil {
+		return err
+	}
+
+	categoryId, err := h.server.DB.NewTagCategory(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"categoryId": categoryId,
+	})
+}
+
+func (h *tagHandler) HandleUpdateCategory(c echo.Context) error {
+	categoryId := c.Param("categoryId")
+	id, err := strconv.Atoi(categoryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "category ID must be a number")
+	}
+
+	req := &request.UpdateTagCategory{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+	req.Id = id
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	categories, err := h.server.DB.FindAllTagCategories()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	parents := make(map[int]*int, len(categories))
+	for _, category := range categories {
+		parents[category.Id] = category.ParentId
+	}
+
+	if _, ok := parents[id]; !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "category not found")
+	}
+
+	// Walk up from the new parent; reaching this category would make a cycle
+	for parent := req.ParentId; parent != nil; parent = parents[*parent] {
+		if *parent == id {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "a category cannot be nested inside itself")
+		}
+
+		if _, ok := parents[*parent]; !ok {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "parent category not found")
+		}
+	}
+
+	if err := h.server.DB.UpdateTagCategory(req); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":    "Category updated successfully",
+		"categoryId": id,
+	})
+}
+
+func (h *tagHandler) HandleDeleteCategory(c echo.Context) error {
+	categoryId := c.Param("categoryId")
+	id, err := strconv.Atoi(categoryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "category ID must be a number")
+	}
+
+	if err := h.server.DB.DeleteTagCategory(id); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, "category still has subcategories")
+	}
+
+	h.server.Autocomplete.Invalidate()
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":    "Category deleted successfully",
+		"categoryId": id,
+	})
+}
+
+func (h *tagHandler) findActiveTag(id int) (*models.TagModel, error) {
+	tag, err := h.server.DB.FindTagById(id)
+	if err != nil || tag == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "tag not found")
+	}
+
+	if tag.RetiredAt != nil {
+		return nil, echo.NewHTTPError(http.StatusUnprocessableEntity, "tag has been retired")
+	}
+
+	return tag, nil
+}
+
+func (h *tagHandler) checkCategory(id *int) error {
+	if id == nil {
+		return nil
+	}
+
+	categories, err := h.server.DB.FindAllTagCategories()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for _, category := range categories {
+		if category.Id == *id {
+			return nil
+		}
+	}
+
+	return echo.NewHTTPError(http.StatusUnprocessableEntity, "category not found")
+}
+
+// Titles are shared between tags and synonyms so each one resolves to a
+// single tag
+func (h *tagHandler) checkTitleAvailable(title string, tagId int) error {
+	ids, _, err := h.server.DB.ResolveTags([]string{title})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for _, id := range ids {
+		if id != tagId {
+			return echo.NewHTTPError(http.StatusConflict, "title is already used by another tag or synonym")
+		}
+	}
+
+	return nil
+}
@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"nearbyassist/internal/hash"
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/payment"
+	"nearbyassist/internal/receipt"
 	"nearbyassist/internal/request"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -109,14 +115,28 @@ func (h *transactionHandler) HandleNewTransaction(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	// Validate that the vendor entered exists
-	if _, err := h.server.DB.FindVendorById(req.VendorId); err != nil {
+	// The service decides who is booked, whatever vendor the client sent
+	if owner, err := h.server.DB.FindServiceOwner(req.ServiceId); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Service not found")
+	} else if owner.Id != req.VendorId {
+		return echo.NewHTTPError(http.StatusBadRequest, "Service does not belong to the vendor")
+	}
+
+	// Validate that the vendor entered exists and can take bookings
+	if vendor, err := h.server.DB.FindVendorById(req.VendorId); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Vendor not found")
+	} else if vendor.Restricted == 1 {
+		return echo.NewHTTPError(http.StatusForbidden, "Vendor is restricted")
 	}
 
-	// Validate that the service exists
-	if _, err := h.server.DB.FindServiceById(req.ServiceId); err != nil {
+	// Validate that the service exists, its current price is what gets paid
+	if service, err := h.server.DB.FindServiceById(req.ServiceId); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Service not found")
+	} else {
+		req.Price = max(service.Rate, service.MinimumCharge)
+		req.Currency = service.Currency
+		req.CancellationPolicy = service.CancellationPolicy
+		req.CancellationWindows = models.ResolveCancellationWindows(service.CancellationPolicy, service.CancellationWindows)
 	}
 
 	transactionId, err := h.server.DB.CreateTransaction(req)
@@ -183,7 +203,8 @@ func (h *transactionHandler) HandleCompleteTransaction(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	if transaction, err := h.server.DB.FindTransactionById(id); err != nil {
+	transaction, err := h.server.DB.FindTransactionById(id)
+	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "transaction not found")
 	} else {
 		if transaction.ClientId != userId {
@@ -193,6 +214,10 @@ func (h *transactionHandler) HandleCompleteTransaction(c echo.Context) error {
 		if transaction.Status == models.TRANSACTION_STATUS_DONE {
 			return echo.NewHTTPError(http.StatusUnprocessableEntity, "transaction already marked as completed")
 		}
+
+		if transaction.Status != models.TRANSACTION_STATUS_ONGOING {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "only ongoing transactions can be completed")
+		}
 	}
 
 	if err := h.server.DB.CompleteTransaction(id); err != nil {
@@ -204,3 +229,401 @@ func (h *transactionHandler) HandleCompleteTransaction(c echo.Context) error {
 		"transactionId": transactionId,
 	})
 }
+
+// The caller's side of the cancellation policy decides the penalty, whatever
+// the client paid beyond it is refunded once the transaction is cancelled.
+// Cancelling again retries a refund the provider failed to make
+func (h *transactionHandler) HandleCancelTransaction(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	req := &request.CancelTransaction{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request data")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	transaction, userId, err := h.findOwnTransaction(c, id)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Status == models.TRANSACTION_STATUS_CANCELLED {
+		return h.retryCancellationRefund(c, transaction)
+	}
+
+	if transaction.Status != models.TRANSACTION_STATUS_ONGOING {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "only ongoing transactions can be cancelled")
+	}
+
+	terms, payment, err := h.cancellationTerms(transaction, userId)
+	if err != nil {
+		return err
+	}
+
+	if req.Reason != "" {
+		terms.Reason = &req.Reason
+	}
+
+	if payment != nil {
+		terms.Penalty, terms.Refund = splitPayment(payment, terms.Penalty)
+	}
+
+	// Cancelled before anything is refunded, completing the transaction at
+	// the same time can no longer release the escrow to the vendor
+	if err := h.server.DB.CancelTransaction(terms); err != nil {
+		if errors.Is(err, models.ErrPaymentChanged) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if payment != nil {
+		if err := h.settleCancellation(payment, terms); err != nil {
+			return err
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":       "transaction cancelled",
+		"transactionId": id,
+		"cancellation":  terms,
+	})
+}
+
+// Only the cancellation's refund is retried, its terms were recorded when
+// the transaction was cancelled
+func (h *transactionHandler) retryCancellationRefund(c echo.Context, transaction *models.TransactionModel) error {
+	payment, err := h.server.DB.FindPaymentByTransaction(transaction.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if payment == nil || (payment.Status != models.PAYMENT_STATUS_AUTHORIZED && payment.Status != models.PAYMENT_STATUS_CAPTURED) {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "transaction is already cancelled")
+	}
+
+	terms, err := h.server.DB.FindTransactionCancellation(transaction.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.settleCancellation(payment, terms); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":       "transaction cancelled",
+		"transactionId": transaction.Id,
+		"cancellation":  terms,
+	})
+}
+
+// Previews what cancelling now would cost, or what it did cost once cancelled
+func (h *transactionHandler) HandleGetCancellation(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	transaction, userId, err := h.findOwnTransaction(c, id)
+	if err != nil {
+		return err
+	}
+
+	switch transaction.Status {
+	case models.TRANSACTION_STATUS_CANCELLED:
+		cancellation, err := h.server.DB.FindTransactionCancellation(id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return echo.NewHTTPError(http.StatusNotFound, "transaction was cancelled before cancellations were recorded")
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(http.StatusOK, utils.Mapper{
+			"preview":      false,
+			"cancellation": cancellation,
+		})
+
+	case models.TRANSACTION_STATUS_ONGOING:
+		terms, payment, err := h.cancellationTerms(transaction, userId)
+		if err != nil {
+			return err
+		}
+
+		if payment != nil {
+			terms.Penalty, terms.Refund = splitPayment(payment, terms.Penalty)
+		}
+
+		return c.JSON(http.StatusOK, utils.Mapper{
+			"preview":      true,
+			"policy":       transaction.CancellationPolicy,
+			"windows":      transaction.CancellationWindows,
+			"cancellation": terms,
+		})
+	}
+
+	return echo.NewHTTPError(http.StatusUnprocessableEntity, "only ongoing transactions can be cancelled")
+}
+
+func (h *transactionHandler) HandleGetReliability(c echo.Context) error {
+	userIdParam := c.Param("userId")
+	userId, err := strconv.Atoi(userIdParam)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "user ID must be a number")
+	}
+
+	asClient, err := h.server.DB.FindReliability(userId, models.CANCELLATION_ROLE_CLIENT)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	asVendor, err := h.server.DB.FindReliability(userId, models.CANCELLATION_ROLE_VENDOR)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"userId": userId,
+		"client": asClient,
+		"vendor": asVendor,
+	})
+}
+
+// The payment is nil when the booking was never paid through the platform
+func (h *transactionHandler) cancellationTerms(transaction *models.TransactionModel, userId int) (*models.TransactionCancellationModel, *models.PaymentModel, error) {
+	terms, err := transaction.CancellationTerms(userId, time.Now())
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	payment, err := h.server.DB.FindPaymentByTransaction(transaction.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return terms, nil, nil
+		}
+
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if payment.Status != models.PAYMENT_STATUS_AUTHORIZED && payment.Status != models.PAYMENT_STATUS_CAPTURED {
+		return terms, nil, nil
+	}
+
+	terms.Paid = true
+
+	return terms, payment, nil
+}
+
+// A hold is captured in full when a penalty has to be kept from it, otherwise
+// it is voided. The charge is looked up first so a retry only does what the
+// provider has not done yet, the ledger records it once the provider is done
+func (h *transactionHandler) settleCancellation(payment *models.PaymentModel, terms *models.TransactionCancellationModel) error {
+	// Cancelling again retries the refund once a provider is configured
+	if h.server.Payment == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "transaction cancelled but payments are disabled, unable to refund payment")
+	}
+
+	if err := h.refundCancellation(payment, terms); err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "transaction cancelled but unable to refund payment: "+err.Error())
+	}
+
+	if err := h.server.DB.SettleCancelledPayment(payment.TransactionId, terms.Penalty); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (h *transactionHandler) refundCancellation(record *models.PaymentModel, terms *models.TransactionCancellationModel) error {
+	charge, err := h.server.Payment.FindCharge(record.Reference)
+	if err != nil {
+		return err
+	}
+
+	switch charge.Status {
+	case payment.CHARGE_STATUS_VOIDED:
+		return nil
+
+	case payment.CHARGE_STATUS_AUTHORIZED:
+		if terms.Penalty == 0 {
+			_, err := h.server.Payment.Refund(record.Reference, record.Amount)
+			return err
+		}
+
+		if charge, err = h.server.Payment.Capture(record.Reference, record.Amount); err != nil {
+			return err
+		}
+	}
+
+	due := models.RoundMoney(terms.Refund - charge.Refunded)
+	if due <= 0 {
+		return nil
+	}
+
+	_, err = h.server.Payment.Refund(record.Reference, due)
+	return err
+}
+
+// The penalty can never exceed what was paid
+func splitPayment(payment *models.PaymentModel, penalty float64) (float64, float64) {
+	penalty = models.RoundMoney(min(penalty, payment.Amount))
+	return penalty, models.RoundMoney(payment.Amount - penalty)
+}
+
+func (h *transactionHandler) HandleGetReceipts(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	if _, _, err := h.findOwnTransaction(c, id); err != nil {
+		return err
+	}
+
+	receipts, err := h.server.DB.FindReceipts(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"receipts": receipts,
+	})
+}
+
+// Serves the latest revision unless one is asked for
+func (h *transactionHandler) HandleDownloadReceipt(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	revision := 0
+	if param := c.QueryParam("revision"); param != "" {
+		if revision, err = strconv.Atoi(param); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "revision must be a number")
+		}
+	}
+
+	if _, _, err := h.findOwnTransaction(c, id); err != nil {
+		return err
+	}
+
+	receipts, err := h.server.DB.FindReceipts(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	var found *models.ReceiptModel
+	for i := range receipts {
+		if revision == 0 || receipts[i].Revision == revision {
+			found = &receipts[i]
+			break
+		}
+	}
+
+	if found == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "receipt not found")
+	}
+
+	bytes, err := h.server.Storage.ReadFile(found.Path)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	decrypted, err := h.server.Encrypt.DecryptFile(bytes)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	filename := fmt.Sprintf("%s-r%d.pdf", receipt.FormatInvoiceNumber(found.VendorId, found.InvoiceNumber), found.Revision)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	return c.Blob(http.StatusOK, "application/pdf", decrypted)
+}
+
+// Vendors correct a receipt by issuing a new revision under the same number
+func (h *transactionHandler) HandleReviseReceipt(c echo.Context) error {
+	transactionId := c.Param("transactionId")
+	id, err := strconv.Atoi(transactionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction ID must be a number")
+	}
+
+	req := &request.ReviseReceipt{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	transaction, err := h.server.DB.FindTransactionById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "transaction not found")
+	}
+
+	if transaction.VendorId != userId {
+		return echo.NewHTTPError(http.StatusForbidden, "you're not the vendor of this transaction")
+	}
+
+	// Only a receipt that was already issued can be corrected
+	if receipts, err := h.server.DB.FindReceipts(id); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if len(receipts) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "receipt not found")
+	}
+
+	issued, err := h.server.Receipts.Generate(id, &req.Reason)
+	if err != nil {
+		if errors.Is(err, receipt.ErrNotCompleted) || errors.Is(err, receipt.ErrNoPrice) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"receipt": issued,
+	})
+}
+
+// Receipts and cancellations are only visible to the two parties of the
+// transaction
+func (h *transactionHandler) findOwnTransaction(c echo.Context, id int) (*models.TransactionModel, int, error) {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	transaction, err := h.server.DB.FindTransactionById(id)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, "transaction not found")
+	}
+
+	if transaction.ClientId != userId && transaction.VendorId != userId {
+		return nil, 0, echo.NewHTTPError(http.StatusForbidden, "you are not part of this transaction")
+	}
+
+	return transaction, userId, nil
+}
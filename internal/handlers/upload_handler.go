@@ -36,6 +36,12 @@ func (h *uploadHandler) HandleNewServicePhoto(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "Service not found")
 	}
 
+	if restricted, err := h.server.DB.IsVendorRestricted(params["vendorId"]); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if restricted {
+		return echo.NewHTTPError(http.StatusForbidden, "Vendor is restricted")
+	}
+
 	files, err := filehandler.FormParser(c)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
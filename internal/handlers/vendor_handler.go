@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
+	"nearbyassist/internal/hash"
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -49,10 +54,20 @@ func (h *vendorHandler) HandleGetVendor(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "vendor not found")
 	}
 
+	verified, err := h.server.DB.IsUserVerified(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if viewerId(h.server, c) != id {
+		h.server.Activity.RecordVendorView(id)
+	}
+
 	// TODO: retrieve review count
 
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"vendor": vendor,
+		"vendor":   vendor,
+		"verified": verified,
 	})
 }
 
@@ -63,13 +78,50 @@ func (h *vendorHandler) HandleRestrict(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "vendor ID must be a number")
 	}
 
-	err = h.server.DB.RestrictVendor(id)
-	if err != nil {
+	req := &request.RestrictVendor{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.VendorId = id
+		req.IssuedBy = adminId
+		req.ComplaintId = nil
+	}
+
+	if _, err := h.server.DB.FindVendorById(id); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "vendor not found")
 	}
 
+	if _, err := h.server.DB.FindActiveRestriction(id); err == nil {
+		return echo.NewHTTPError(http.StatusConflict, "vendor is already restricted")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Reason); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		req.Reason = cipher
+	}
+
+	restrictionId, err := h.server.DB.RestrictVendor(req)
+	if errors.Is(err, models.ErrVendorAlreadyRestricted) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
-		"restrictedId": id,
+		"restrictedId":  id,
+		"restrictionId": restrictionId,
 	})
 }
 
@@ -80,12 +132,349 @@ func (h *vendorHandler) HandleUnrestrict(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "vendor ID must be a number")
 	}
 
-	err = h.server.DB.UnrestrictVendor(id)
+	authHeader := c.Request().Header.Get("Authorization")
+	adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
 	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if _, err := h.server.DB.FindVendorById(id); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "vendor not found")
 	}
 
+	if err := h.server.DB.UnrestrictVendor(id, adminId); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
 	return c.JSON(http.StatusOK, utils.Mapper{
 		"unrestrictedId": id,
 	})
 }
+
+func (h *vendorHandler) HandleGetRestrictions(c echo.Context) error {
+	vendorId := c.Param("vendorId")
+	id, err := strconv.Atoi(vendorId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "vendor ID must be a number")
+	}
+
+	restrictions, err := h.server.DB.FindVendorRestrictions(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for i := range restrictions {
+		if err := h.decryptRestriction(&restrictions[i]); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"restrictions": restrictions,
+	})
+}
+
+// How the caller's profile and services performed over a period, the last 30
+// days by day unless asked otherwise
+func (h *vendorHandler) HandleGetOwnStats(c echo.Context) error {
+	filter := &request.VendorStatsFilter{}
+	if err := c.Bind(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid filter")
+	}
+
+	if filter.Bucket == "" {
+		filter.Bucket = models.ANALYTICS_BUCKET_DAY
+	}
+
+	if !filter.Bucket.IsValid() {
+		return echo.NewHTTPError(http.StatusBadRequest, "bucket must be day, week or month")
+	}
+
+	from, to, err := parsePeriod(filter.From, filter.To)
+	if err != nil {
+		return err
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if _, err := h.server.DB.FindVendorById(userId); err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, "user is not a registered vendor")
+	}
+
+	start, until := from.Format(time.DateOnly), to.AddDate(0, 0, 1).Format(time.DateOnly)
+
+	services, err := h.server.DB.FindVendorServiceStats(userId, start, until)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	inquiries, err := h.server.DB.FindVendorInquiryStats(userId, start, until)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	trend, err := h.server.DB.FindVendorStatsTrend(userId, from, to, filter.Bucket)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	stats := &models.VendorStatsModel{
+		From:      start,
+		To:        to.Format(time.DateOnly),
+		Bucket:    filter.Bucket,
+		Inquiries: inquiries,
+		Services:  services,
+		Trend:     trend,
+	}
+	stats.Calculate()
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"stats": stats,
+	})
+}
+
+func (h *vendorHandler) HandleGetOwnRestriction(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	restriction, err := h.server.DB.FindActiveRestriction(userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusOK, utils.Mapper{
+				"restricted": false,
+			})
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.decryptRestriction(restriction); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"restricted":  true,
+		"restriction": restriction,
+	})
+}
+
+func (h *vendorHandler) HandleNewAppeal(c echo.Context) error {
+	req := &request.NewAppeal{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.VendorId = userId
+	}
+
+	restriction, err := h.server.DB.FindRestrictionById(req.RestrictionId)
+	if err != nil || restriction.VendorId != req.VendorId {
+		return echo.NewHTTPError(http.StatusNotFound, "restriction not found")
+	}
+
+	if restriction.LiftedAt != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "restriction has already been lifted")
+	}
+
+	if pending, err := h.server.DB.HasPendingAppeal(req.RestrictionId); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else if pending {
+		return echo.NewHTTPError(http.StatusConflict, "an appeal for this restriction is already pending")
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Content); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		req.Content = cipher
+	}
+
+	appealId, err := h.server.DB.NewRestrictionAppeal(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"message":  "Appeal submitted successfully",
+		"appealId": appealId,
+	})
+}
+
+func (h *vendorHandler) HandleGetOwnAppeals(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	appeals, err := h.server.DB.FindRestrictionAppealsByVendor(userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for i := range appeals {
+		if err := h.decryptAppeal(&appeals[i]); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		}
+
+		// Reviewer identity is internal to staff
+		appeals[i].ReviewedBy = nil
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"appeals": appeals,
+	})
+}
+
+func (h *vendorHandler) HandleGetAppeals(c echo.Context) error {
+	status := models.AppealStatus(c.QueryParam("status"))
+
+	appeals, err := h.server.DB.FindAllRestrictionAppeals(status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	for i := range appeals {
+		if err := h.decryptAppeal(&appeals[i]); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		}
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"appeals": appeals,
+	})
+}
+
+func (h *vendorHandler) HandleGetAppeal(c echo.Context) error {
+	appealId := c.Param("appealId")
+	id, err := strconv.Atoi(appealId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "appeal ID must be a number")
+	}
+
+	appeal, err := h.server.DB.FindRestrictionAppealById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "appeal not found")
+	}
+
+	if err := h.decryptAppeal(appeal); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	}
+
+	restriction, err := h.server.DB.FindRestrictionById(appeal.RestrictionId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.decryptRestriction(restriction); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"appeal":      appeal,
+		"restriction": restriction,
+	})
+}
+
+func (h *vendorHandler) HandleAcceptAppeal(c echo.Context) error {
+	return h.reviewAppeal(c, models.APPEAL_STATUS_ACCEPTED)
+}
+
+func (h *vendorHandler) HandleRejectAppeal(c echo.Context) error {
+	return h.reviewAppeal(c, models.APPEAL_STATUS_REJECTED)
+}
+
+func (h *vendorHandler) reviewAppeal(c echo.Context, status models.AppealStatus) error {
+	appealId := c.Param("appealId")
+	id, err := strconv.Atoi(appealId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "appeal ID must be a number")
+	}
+
+	req := &request.ReviewAppeal{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing required fields")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.Id = id
+		req.Status = status
+		req.ReviewedBy = adminId
+	}
+
+	if _, err := h.server.DB.FindRestrictionAppealById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "appeal not found")
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(req.Note); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		req.Note = cipher
+	}
+
+	if err := h.server.DB.ReviewRestrictionAppeal(req); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":  "Appeal " + string(status),
+		"appealId": id,
+	})
+}
+
+// Restrictions recorded by the migration for vendors that were already
+// restricted have no reason
+func (h *vendorHandler) decryptRestriction(restriction *models.VendorRestrictionModel) error {
+	if restriction.Reason == "" {
+		return nil
+	}
+
+	if decrypted, err := h.server.Encrypt.DecryptString(restriction.Reason); err != nil {
+		return err
+	} else {
+		restriction.Reason = decrypted
+	}
+
+	return nil
+}
+
+func (h *vendorHandler) decryptAppeal(appeal *models.VendorRestrictionAppealModel) error {
+	if decrypted, err := h.server.Encrypt.DecryptString(appeal.Content); err != nil {
+		return err
+	} else {
+		appeal.Content = decrypted
+	}
+
+	if appeal.ReviewNote == "" {
+		return nil
+	}
+
+	if decrypted, err := h.server.Encrypt.DecryptString(appeal.ReviewNote); err != nil {
+		return err
+	} else {
+		appeal.ReviewNote = decrypted
+	}
+
+	return nil
+}
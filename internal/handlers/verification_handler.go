@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
+	"log"
+	"mime/multipart"
 	filehandler "nearbyassist/internal/file"
 	"nearbyassist/internal/hash"
 	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
 	"nearbyassist/internal/server"
 	"nearbyassist/internal/utils"
 	"net/http"
@@ -35,53 +40,43 @@ func (h *verificationHandler) HandleVerifyIdentity(c echo.Context) error {
 		IdNumber: idNumber,
 	}
 
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	if userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	} else {
+		req.User = userId
+	}
+
+	// A user may only resubmit once the previous request has been rejected or sent back
+	if latest, err := h.server.DB.FindLatestIdentityVerificationByUser(req.User); err == nil {
+		switch latest.Status {
+		case models.VERIFICATION_STATUS_PENDING:
+			return echo.NewHTTPError(http.StatusConflict, "Identity verification is still pending review")
+		case models.VERIFICATION_STATUS_APPROVED:
+			return echo.NewHTTPError(http.StatusConflict, "Identity is already verified")
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
 	files, err := filehandler.FormParser(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to parse submitted files")
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to parse submitted files")
 	}
 
+	images := make(map[string]*multipart.FileHeader)
 	for _, file := range files {
-		handler := filehandler.NewFileHandler(h.server.Encrypt)
-
-		switch file.Filename {
-		case "frontId":
-			url, err := handler.SavePhoto(file, h.server.Storage.SaveFrontId)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save front id")
-			}
-
-			if id, err := h.server.DB.NewFrontId(&models.FrontIdModel{Url: url}); err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save front id to db")
-			} else {
-				req.FrontId = id
-			}
-		case "backId":
-			url, err := handler.SavePhoto(file, h.server.Storage.SaveBackId)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save back id")
-			}
-
-			if id, err := h.server.DB.NewBackId(&models.BackIdModel{Url: url}); err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save back id to db")
-			} else {
-				req.BackId = id
-			}
-		case "face":
-			url, err := handler.SavePhoto(file, h.server.Storage.SaveFace)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save face")
-			}
-
-			if id, err := h.server.DB.NewFace(&models.FaceModel{Url: url}); err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save face to db")
-			} else {
-				req.Face = id
-			}
-		}
+		images[file.Filename] = file
 	}
 
-	if err := c.Validate(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	for _, filename := range []string{"frontId", "backId", "face"} {
+		if _, ok := images[filename]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing "+filename+" image")
+		}
 	}
 
 	if cipher, err := h.server.Encrypt.EncryptString(req.Name); err != nil {
@@ -102,8 +97,33 @@ func (h *verificationHandler) HandleVerifyIdentity(c echo.Context) error {
 		req.IdNumber = cipher
 	}
 
-	verificationId, err := h.server.DB.NewIdentityVerification(req)
+	handler := filehandler.NewFileHandler(h.server.Encrypt)
+
+	frontUrl, err := handler.SavePhoto(images["frontId"], h.server.Storage.SaveFrontId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save front id")
+	}
+
+	backUrl, err := handler.SavePhoto(images["backId"], h.server.Storage.SaveBackId)
+	if err != nil {
+		h.deleteFiles([]string{frontUrl})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save back id")
+	}
+
+	faceUrl, err := handler.SavePhoto(images["face"], h.server.Storage.SaveFace)
+	if err != nil {
+		h.deleteFiles([]string{frontUrl, backUrl})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save face")
+	}
+
+	verificationId, err := h.server.DB.NewIdentityVerification(
+		req,
+		&models.FrontIdModel{Url: frontUrl},
+		&models.BackIdModel{Url: backUrl},
+		&models.FaceModel{Url: faceUrl},
+	)
 	if err != nil {
+		h.deleteFiles([]string{frontUrl, backUrl, faceUrl})
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
@@ -113,8 +133,40 @@ func (h *verificationHandler) HandleVerifyIdentity(c echo.Context) error {
 	})
 }
 
+// ID photos saved for a request that could not be recorded are removed again
+func (h *verificationHandler) deleteFiles(urls []string) {
+	for _, url := range urls {
+		if err := h.server.Storage.DeleteFile(url); err != nil {
+			log.Printf("Failed to delete %s: %s\n", url, err.Error())
+		}
+	}
+}
+
+func (h *verificationHandler) HandleGetIdentityVerificationStatus(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	userId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	status, err := h.server.DB.FindLatestIdentityVerificationByUser(userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "No identity verification submitted")
+		}
+
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"verification": status,
+	})
+}
+
 func (h *verificationHandler) HandleGetAllIdentityVerification(c echo.Context) error {
-	requests, err := h.server.DB.FindAllIdentityVerification()
+	status := models.VerificationStatus(c.QueryParam("status"))
+
+	requests, err := h.server.DB.FindAllIdentityVerification(status)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -158,3 +210,57 @@ func (h *verificationHandler) HandleGetIdentityVerification(c echo.Context) erro
 		"request": request,
 	})
 }
+
+func (h *verificationHandler) HandleApproveIdentityVerification(c echo.Context) error {
+	return h.decideIdentityVerification(c, models.VERIFICATION_STATUS_APPROVED)
+}
+
+func (h *verificationHandler) HandleRejectIdentityVerification(c echo.Context) error {
+	return h.decideIdentityVerification(c, models.VERIFICATION_STATUS_REJECTED)
+}
+
+func (h *verificationHandler) HandleRequestIdentityResubmission(c echo.Context) error {
+	return h.decideIdentityVerification(c, models.VERIFICATION_STATUS_RESUBMIT)
+}
+
+func (h *verificationHandler) decideIdentityVerification(c echo.Context, status models.VerificationStatus) error {
+	verificationId := c.Param("verificationId")
+	id, err := strconv.Atoi(verificationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Verification ID must be a number")
+	}
+
+	req := &request.VerificationDecision{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Users need to know why their request was turned down
+	if status != models.VERIFICATION_STATUS_APPROVED && req.Reason == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "A reason is required")
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	req.Id = id
+	req.Status = status
+	req.ReviewedBy = adminId
+
+	if _, err := h.server.DB.FindIdentityVerificationById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Identity verification not found")
+	}
+
+	if err := h.server.DB.DecideIdentityVerification(req); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":        "Identity verification updated.",
+		"verificationId": id,
+		"status":         status,
+	})
+}
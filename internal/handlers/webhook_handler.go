@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"nearbyassist/internal/hash"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/request"
+	"nearbyassist/internal/server"
+	"nearbyassist/internal/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type webhookHandler struct {
+	server *server.Server
+}
+
+func NewWebhookHandler(server *server.Server) *webhookHandler {
+	return &webhookHandler{
+		server: server,
+	}
+}
+
+func (h *webhookHandler) HandleGetWebhooks(c echo.Context) error {
+	webhooks, err := h.server.DB.FindAllWebhooks()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"webhooks": webhooks,
+		"events":   models.WebhookEvents,
+	})
+}
+
+func (h *webhookHandler) HandleGetWebhook(c echo.Context) error {
+	webhookId := c.Param("webhookId")
+	id, err := strconv.Atoi(webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "webhook ID must be a number")
+	}
+
+	webhook, err := h.server.DB.FindWebhookById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"webhook": webhook,
+	})
+}
+
+// The secret is only returned here, partners need it to verify signatures
+func (h *webhookHandler) HandleNewWebhook(c echo.Context) error {
+	req := &request.NewWebhook{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	events, err := joinWebhookEvents(req.Events)
+	if err != nil {
+		return err
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	adminId, err := utils.GetUserIdFromJWT(h.server.Auth, authHeader)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	webhook := &models.WebhookModel{
+		Url:       req.Url,
+		Events:    events,
+		CreatedBy: adminId,
+	}
+
+	if cipher, err := h.server.Encrypt.EncryptString(secret); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+	} else {
+		webhook.Secret = cipher
+	}
+
+	webhookId, err := h.server.DB.NewWebhook(webhook)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, utils.Mapper{
+		"webhookId": webhookId,
+		"secret":    secret,
+	})
+}
+
+func (h *webhookHandler) HandleUpdateWebhook(c echo.Context) error {
+	webhookId := c.Param("webhookId")
+	id, err := strconv.Atoi(webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "webhook ID must be a number")
+	}
+
+	req := &request.UpdateWebhook{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to process data provided")
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	events, err := joinWebhookEvents(req.Events)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.server.DB.FindWebhookById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	}
+
+	webhook := &models.WebhookModel{
+		Url:    req.Url,
+		Events: events,
+		Active: req.Active,
+	}
+	webhook.Id = id
+
+	// An empty secret keeps the current one
+	if req.Secret != "" {
+		if cipher, err := h.server.Encrypt.EncryptString(req.Secret); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, hash.HASH_ERROR)
+		} else {
+			webhook.Secret = cipher
+		}
+	}
+
+	if err := h.server.DB.UpdateWebhook(webhook); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":   "Webhook updated",
+		"webhookId": id,
+	})
+}
+
+func (h *webhookHandler) HandleDeleteWebhook(c echo.Context) error {
+	webhookId := c.Param("webhookId")
+	id, err := strconv.Atoi(webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "webhook ID must be a number")
+	}
+
+	if err := h.server.DB.DeleteWebhook(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *webhookHandler) HandleGetDeliveries(c echo.Context) error {
+	webhookId := c.Param("webhookId")
+	id, err := strconv.Atoi(webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "webhook ID must be a number")
+	}
+
+	if _, err := h.server.DB.FindWebhookById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	}
+
+	deliveries, err := h.server.DB.FindWebhookDeliveries(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"deliveries": deliveries,
+	})
+}
+
+func (h *webhookHandler) HandleGetDelivery(c echo.Context) error {
+	deliveryId := c.Param("deliveryId")
+	id, err := strconv.Atoi(deliveryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "delivery ID must be a number")
+	}
+
+	delivery, err := h.server.DB.FindWebhookDeliveryById(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "delivery not found")
+	}
+
+	attempts, err := h.server.DB.FindWebhookDeliveryAttempts(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"delivery": delivery,
+		"attempts": attempts,
+	})
+}
+
+func (h *webhookHandler) HandleRedeliver(c echo.Context) error {
+	deliveryId := c.Param("deliveryId")
+	id, err := strconv.Atoi(deliveryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "delivery ID must be a number")
+	}
+
+	if _, err := h.server.DB.FindWebhookDeliveryById(id); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "delivery not found")
+	}
+
+	if err := h.server.DB.RedeliverWebhookDelivery(id); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, utils.Mapper{
+		"message":    "Delivery queued",
+		"deliveryId": id,
+	})
+}
+
+func joinWebhookEvents(events []models.EventType) (string, error) {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		if !models.IsWebhookEvent(event) {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "unsupported event: "+string(event))
+		}
+
+		names = append(names, string(event))
+	}
+
+	return strings.Join(names, ","), nil
+}
+
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"log"
+	"nearbyassist/internal/db"
+	"time"
+)
+
+const (
+	ANALYTICS_REFRESH_INTERVAL = time.Minute * 15
+	// Days before the latest refreshed one that are rebuilt, catching rows
+	// written after their day was last refreshed
+	ANALYTICS_LOOKBACK_DAYS = 2
+	// Where the first refresh starts, covering the whole history
+	ANALYTICS_BACKFILL_FROM = "1970-01-01"
+)
+
+// Periodically rebuilds the recent daily analytics totals
+func RunAnalyticsRefresh(db db.Database, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := RefreshAnalytics(db); err != nil {
+			log.Printf("error refreshing analytics: %s\n", err.Error())
+		}
+
+		<-ticker.C
+	}
+}
+
+func RefreshAnalytics(db db.Database) error {
+	watermark, err := db.FindAnalyticsWatermark()
+	if err != nil {
+		return err
+	}
+
+	from := ANALYTICS_BACKFILL_FROM
+	if latest, err := time.Parse(time.DateOnly, watermark); err == nil {
+		from = latest.AddDate(0, 0, -ANALYTICS_LOOKBACK_DAYS).Format(time.DateOnly)
+	}
+
+	return db.RefreshAnalytics(from)
+}
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"log"
+	"nearbyassist/internal/db"
+	"time"
+)
+
+const RESTRICTION_EXPIRY_INTERVAL = time.Minute
+
+// Periodically lifts vendor restrictions that are past their expiry
+func RunRestrictionExpiry(db db.Database, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if lifted, err := db.LiftExpiredRestrictions(); err != nil {
+			log.Printf("error lifting expired restrictions: %s\n", err.Error())
+		} else if lifted > 0 {
+			log.Printf("lifted %d expired restriction(s)\n", lifted)
+		}
+
+		<-ticker.C
+	}
+}
@@ -2,13 +2,16 @@ package middleware
 
 import (
 	"nearbyassist/internal/authenticator"
+	"nearbyassist/internal/models"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
-func CheckRole(jwtChecker authenticator.Authenticator) echo.MiddlewareFunc {
+// Admin routes are limited to the given roles
+func CheckRole(jwtChecker authenticator.Authenticator, roles ...models.AdminRole) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get("Authorization")
@@ -32,7 +35,7 @@ func CheckRole(jwtChecker authenticator.Authenticator) echo.MiddlewareFunc {
 			// Check if the user is accessing admin-only route
 			url := c.Request().URL.String()
 			iAdminRoute := strings.Contains(url, "/admin")
-			if iAdminRoute && role != "admin" {
+			if iAdminRoute && !slices.Contains(roles, models.AdminRole(role)) {
 				return echo.NewHTTPError(http.StatusForbidden, "Unauthorized access")
 			}
 
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+type AnalyticsBucket string
+type AnalyticsSplit string
+
+const (
+	ANALYTICS_BUCKET_DAY   AnalyticsBucket = "day"
+	ANALYTICS_BUCKET_WEEK  AnalyticsBucket = "week"
+	ANALYTICS_BUCKET_MONTH AnalyticsBucket = "month"
+
+	ANALYTICS_SPLIT_ALL  AnalyticsSplit = "all"
+	ANALYTICS_SPLIT_TAG  AnalyticsSplit = "tag"
+	ANALYTICS_SPLIT_AREA AnalyticsSplit = "area"
+)
+
+// Services are grouped into areas by rounding their coordinates, one decimal
+// is a cell of roughly 11km
+const ANALYTICS_AREA_PRECISION = 1
+
+func (b AnalyticsBucket) IsValid() bool {
+	switch b {
+	case ANALYTICS_BUCKET_DAY, ANALYTICS_BUCKET_WEEK, ANALYTICS_BUCKET_MONTH:
+		return true
+	}
+
+	return false
+}
+
+func (s AnalyticsSplit) IsValid() bool {
+	switch s {
+	case ANALYTICS_SPLIT_ALL, ANALYTICS_SPLIT_TAG, ANALYTICS_SPLIT_AREA:
+		return true
+	}
+
+	return false
+}
+
+// First day of the bucket the day falls in, weeks start on Monday
+func (b AnalyticsBucket) Start(day time.Time) time.Time {
+	switch b {
+	case ANALYTICS_BUCKET_WEEK:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case ANALYTICS_BUCKET_MONTH:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+
+	return day
+}
+
+func (b AnalyticsBucket) next(start time.Time) time.Time {
+	switch b {
+	case ANALYTICS_BUCKET_WEEK:
+		return start.AddDate(0, 0, 7)
+	case ANALYTICS_BUCKET_MONTH:
+		return start.AddDate(0, 1, 0)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
+// User and vendor signups have no tag or area, they are only counted in the
+// overall series
+type AnalyticsPointModel struct {
+	Bucket            string   `json:"bucket" db:"bucket"`
+	Key               string   `json:"key,omitempty" db:"dimensionKey"`
+	Label             string   `json:"label,omitempty" db:"label"`
+	Signups           int      `json:"signups" db:"signups"`
+	NewVendors        int      `json:"newVendors" db:"newVendors"`
+	BookingsCreated   int      `json:"bookingsCreated" db:"bookingsCreated"`
+	BookingsCompleted int      `json:"bookingsCompleted" db:"bookingsCompleted"`
+	BookingsCancelled int      `json:"bookingsCancelled" db:"bookingsCancelled"`
+	CancellationRate  float64  `json:"cancellationRate" db:"-"`
+	RatingSum         int      `json:"-" db:"ratingSum"`
+	RatingCount       int      `json:"ratingCount" db:"ratingCount"`
+	AverageRating     *float64 `json:"averageRating" db:"-"`
+	Complaints        int      `json:"complaints" db:"complaints"`
+}
+
+// The cancellation rate is the share of bookings closed in the bucket that
+// ended cancelled
+func (p *AnalyticsPointModel) Calculate() {
+	p.CancellationRate = ratio(p.BookingsCancelled, p.BookingsCompleted+p.BookingsCancelled)
+	p.AverageRating = averageRating(p.RatingSum, p.RatingCount)
+}
+
+// Every bucket between the two days, inclusive, as the day it starts on
+func AnalyticsBuckets(from, to time.Time, bucket AnalyticsBucket) []string {
+	buckets := make([]string, 0)
+	for start := bucket.Start(from); !start.After(to); start = bucket.next(start) {
+		buckets = append(buckets, start.Format(time.DateOnly))
+	}
+
+	return buckets
+}
+
+// Adds empty points for buckets without activity so charts get a continuous
+// series, only meant for the overall series
+func FillAnalyticsGaps(points []AnalyticsPointModel, from, to time.Time, bucket AnalyticsBucket) []AnalyticsPointModel {
+	existing := make(map[string]AnalyticsPointModel)
+	for _, point := range points {
+		existing[point.Bucket] = point
+	}
+
+	filled := make([]AnalyticsPointModel, 0, len(points))
+	for _, key := range AnalyticsBuckets(from, to, bucket) {
+		if point, ok := existing[key]; ok {
+			filled = append(filled, point)
+		} else {
+			filled = append(filled, AnalyticsPointModel{Bucket: key})
+		}
+	}
+
+	return filled
+}
+
+func averageRating(sum, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+
+	average := math.Round(float64(sum)/float64(count)*100) / 100
+	return &average
+}
+
+func ratio(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+
+	return math.Round(float64(part)/float64(whole)*10000) / 10000
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsBucketStart(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2026-10-18", ANALYTICS_BUCKET_DAY.Start(day).Format(time.DateOnly))
+	assert.Equal(t, "2026-10-12", ANALYTICS_BUCKET_WEEK.Start(day).Format(time.DateOnly))
+	assert.Equal(t, "2026-10-01", ANALYTICS_BUCKET_MONTH.Start(day).Format(time.DateOnly))
+}
+
+func TestFillAnalyticsGaps(t *testing.T) {
+	from := time.Date(2026, 9, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC)
+	points := []AnalyticsPointModel{{Bucket: "2026-10-01", Signups: 4}}
+
+	filled := FillAnalyticsGaps(points, from, to, ANALYTICS_BUCKET_MONTH)
+
+	assert.Len(t, filled, 3)
+	assert.Equal(t, "2026-09-01", filled[0].Bucket)
+	assert.Equal(t, 4, filled[1].Signups)
+	assert.Equal(t, "2026-11-01", filled[2].Bucket)
+}
+
+func TestAnalyticsPointCalculate(t *testing.T) {
+	point := &AnalyticsPointModel{BookingsCompleted: 6, BookingsCancelled: 2, RatingSum: 14, RatingCount: 3}
+	point.Calculate()
+
+	assert.Equal(t, 0.25, point.CancellationRate)
+	assert.Equal(t, 4.67, *point.AverageRating)
+
+	empty := &AnalyticsPointModel{}
+	empty.Calculate()
+
+	assert.Equal(t, 0.0, empty.CancellationRate)
+	assert.Nil(t, empty.AverageRating)
+}
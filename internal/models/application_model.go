@@ -13,9 +13,12 @@ type ApplicationModel struct {
 	Model
 	UpdateableModel
 	GeoSpatialModel
-	ApplicantId int               `json:"applicantId" db:"applicantId" validate:"required"`
-	Job         string            `json:"job" db:"job" validate:"required"`
-	Status      ApplicationStatus `json:"status" db:"status"`
+	ApplicantId     int               `json:"applicantId" db:"applicantId" validate:"required"`
+	Job             string            `json:"job" db:"job" validate:"required"`
+	Status          ApplicationStatus `json:"status" db:"status"`
+	RejectionReason string            `json:"rejectionReason" db:"rejectionReason"`
+	ReviewedBy      *int              `json:"reviewedBy" db:"reviewedBy"`
+	ReviewedAt      *string           `json:"reviewedAt" db:"reviewedAt"`
 }
 
 func NewApplicationModel() *ApplicationModel {
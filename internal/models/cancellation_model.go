@@ -0,0 +1,160 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+type CancellationPolicy string
+type CancellationRole string
+
+const (
+	CANCELLATION_POLICY_FLEXIBLE CancellationPolicy = "flexible"
+	CANCELLATION_POLICY_MODERATE CancellationPolicy = "moderate"
+	CANCELLATION_POLICY_STRICT   CancellationPolicy = "strict"
+	CANCELLATION_POLICY_CUSTOM   CancellationPolicy = "custom"
+
+	CANCELLATION_ROLE_CLIENT CancellationRole = "client"
+	CANCELLATION_ROLE_VENDOR CancellationRole = "vendor"
+)
+
+// Cancelling less than HoursBefore hours before the start costs
+// PenaltyPercent of the price
+type CancellationWindow struct {
+	HoursBefore    int     `json:"hoursBefore" validate:"gte=1,lte=720"`
+	PenaltyPercent float64 `json:"penaltyPercent" validate:"gte=0,lte=100"`
+}
+
+// Stored as a JSON column
+type CancellationWindows []CancellationWindow
+
+var cancellationPresets = map[CancellationPolicy]CancellationWindows{
+	CANCELLATION_POLICY_FLEXIBLE: {{HoursBefore: 24, PenaltyPercent: 25}},
+	CANCELLATION_POLICY_MODERATE: {{HoursBefore: 72, PenaltyPercent: 25}, {HoursBefore: 24, PenaltyPercent: 50}},
+	CANCELLATION_POLICY_STRICT:   {{HoursBefore: 168, PenaltyPercent: 50}, {HoursBefore: 48, PenaltyPercent: 100}},
+}
+
+// The windows that apply under a policy, custom windows are ignored for the
+// presets. Widest window first
+func ResolveCancellationWindows(policy CancellationPolicy, custom CancellationWindows) CancellationWindows {
+	source := custom
+	if preset, ok := cancellationPresets[policy]; ok {
+		source = preset
+	}
+
+	windows := make(CancellationWindows, len(source))
+	copy(windows, source)
+	sort.SliceStable(windows, func(a, b int) bool {
+		return windows[a].HoursBefore > windows[b].HoursBefore
+	})
+
+	return windows
+}
+
+// Hours left until the start, negative once it has started, and the highest
+// penalty among the windows already entered
+func (w CancellationWindows) Evaluate(start, at time.Time) (float64, float64) {
+	hoursBefore := math.Round(start.Sub(at).Hours()*100) / 100
+
+	penalty := 0.0
+	for _, window := range w {
+		if hoursBefore < float64(window.HoursBefore) {
+			penalty = math.Max(penalty, window.PenaltyPercent)
+		}
+	}
+
+	return hoursBefore, penalty
+}
+
+func (w CancellationWindows) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(w)
+}
+
+func (w *CancellationWindows) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, w)
+	case string:
+		return json.Unmarshal([]byte(value), w)
+	}
+
+	return errors.New("unsupported cancellation windows value")
+}
+
+type TransactionCancellationModel struct {
+	TransactionId  int              `json:"transactionId" db:"transactionId"`
+	CancelledBy    int              `json:"cancelledBy" db:"cancelledBy"`
+	Role           CancellationRole `json:"role" db:"role"`
+	HoursBefore    float64          `json:"hoursBefore" db:"hoursBefore"`
+	PenaltyPercent float64          `json:"penaltyPercent" db:"penaltyPercent"`
+	Penalty        float64          `json:"penalty" db:"penalty"`
+	Refund         float64          `json:"refund" db:"refund"`
+	Reason         *string          `json:"reason" db:"reason"`
+	CreatedAt      string           `json:"createdAt" db:"createdAt"`
+	Paid           bool             `json:"-" db:"-"` // Whether the terms account for a confirmed payment
+}
+
+// Terms for cancelling the booking at the given time. The penalty percent is
+// recorded for both parties so late cancellations show in their reliability,
+// but only a client is charged
+func (t *TransactionModel) CancellationTerms(userId int, at time.Time) (*TransactionCancellationModel, error) {
+	start, err := time.ParseInLocation(time.DateTime, t.Start, time.UTC)
+	if err != nil {
+		if start, err = time.ParseInLocation(time.DateOnly, t.Start, time.UTC); err != nil {
+			return nil, err
+		}
+	}
+
+	hoursBefore, percent := t.CancellationWindows.Evaluate(start, at)
+
+	terms := &TransactionCancellationModel{
+		TransactionId:  t.Id,
+		CancelledBy:    userId,
+		Role:           CANCELLATION_ROLE_CLIENT,
+		HoursBefore:    hoursBefore,
+		PenaltyPercent: percent,
+	}
+
+	if userId == t.VendorId {
+		terms.Role = CANCELLATION_ROLE_VENDOR
+		return terms, nil
+	}
+
+	if t.Price != nil {
+		terms.Penalty = RoundMoney(*t.Price * percent / 100)
+	}
+
+	return terms, nil
+}
+
+// How often a user backs out of bookings in one role. A late cancellation,
+// one that fell inside a penalty window, counts twice against the score
+type ReliabilityModel struct {
+	Bookings          int     `json:"bookings" db:"bookings"`
+	Completed         int     `json:"completed" db:"completed"`
+	Cancellations     int     `json:"cancellations" db:"cancellations"`
+	LateCancellations int     `json:"lateCancellations" db:"lateCancellations"`
+	Score             float64 `json:"score" db:"-"`
+}
+
+// Score is between 0 and 1, users without bookings start at 1
+func (r *ReliabilityModel) Calculate() {
+	if r.Bookings == 0 {
+		r.Score = 1
+		return
+	}
+
+	missed := float64(r.Cancellations+r.LateCancellations) / float64(r.Bookings)
+	r.Score = math.Round(math.Max(0, 1-missed)*100) / 100
+}
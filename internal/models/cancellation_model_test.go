@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCancellationWindows(t *testing.T) {
+	custom := CancellationWindows{{HoursBefore: 12, PenaltyPercent: 80}, {HoursBefore: 48, PenaltyPercent: 10}}
+
+	assert.Equal(t, cancellationPresets[CANCELLATION_POLICY_STRICT], ResolveCancellationWindows(CANCELLATION_POLICY_STRICT, custom))
+	assert.Equal(t, CancellationWindows{{HoursBefore: 48, PenaltyPercent: 10}, {HoursBefore: 12, PenaltyPercent: 80}}, ResolveCancellationWindows(CANCELLATION_POLICY_CUSTOM, custom))
+	assert.Equal(t, 12, custom[0].HoursBefore)
+}
+
+func TestEvaluateCancellationWindows(t *testing.T) {
+	windows := ResolveCancellationWindows(CANCELLATION_POLICY_MODERATE, nil)
+	start := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		at      time.Time
+		hours   float64
+		penalty float64
+	}{
+		{start.Add(-100 * time.Hour), 100, 0},
+		{start.Add(-72 * time.Hour), 72, 0},
+		{start.Add(-30 * time.Hour), 30, 25},
+		{start.Add(-90 * time.Minute), 1.5, 50},
+		{start.Add(2 * time.Hour), -2, 50},
+	}
+
+	for _, c := range cases {
+		hours, penalty := windows.Evaluate(start, c.at)
+		assert.Equal(t, c.hours, hours)
+		assert.Equal(t, c.penalty, penalty)
+	}
+}
+
+func TestCancellationTerms(t *testing.T) {
+	price := 2000.0
+	transaction := &TransactionModel{
+		VendorId:            2,
+		ClientId:            3,
+		Start:               "2026-11-02 00:00:00",
+		Price:               &price,
+		CancellationWindows: ResolveCancellationWindows(CANCELLATION_POLICY_FLEXIBLE, nil),
+	}
+	at := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	client, err := transaction.CancellationTerms(3, at)
+	assert.NoError(t, err)
+	assert.Equal(t, CANCELLATION_ROLE_CLIENT, client.Role)
+	assert.Equal(t, 12.0, client.HoursBefore)
+	assert.Equal(t, 500.0, client.Penalty)
+
+	vendor, err := transaction.CancellationTerms(2, at)
+	assert.NoError(t, err)
+	assert.Equal(t, CANCELLATION_ROLE_VENDOR, vendor.Role)
+	assert.Equal(t, 25.0, vendor.PenaltyPercent)
+	assert.Equal(t, 0.0, vendor.Penalty)
+
+	transaction.CancellationWindows = nil
+	legacy, err := transaction.CancellationTerms(3, at)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, legacy.Penalty)
+}
+
+func TestReliabilityScore(t *testing.T) {
+	fresh := &ReliabilityModel{}
+	fresh.Calculate()
+	assert.Equal(t, 1.0, fresh.Score)
+
+	flaky := &ReliabilityModel{Bookings: 4, Cancellations: 3, LateCancellations: 2}
+	flaky.Calculate()
+	assert.Equal(t, 0.0, flaky.Score)
+}
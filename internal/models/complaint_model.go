@@ -1,20 +1,105 @@
 package models
 
-type ComplaintModel struct {
+type VendorComplaintStatus string
+type ComplaintResolution string
+type SystemComplaintStatus string
+type ComplaintPriority string
+type ComplaintReplyRole string
+
+const (
+	VENDOR_COMPLAINT_STATUS_ALL          VendorComplaintStatus = "all"
+	VENDOR_COMPLAINT_STATUS_OPEN         VendorComplaintStatus = "open"
+	VENDOR_COMPLAINT_STATUS_UNDER_REVIEW VendorComplaintStatus = "under_review"
+	VENDOR_COMPLAINT_STATUS_RESOLVED     VendorComplaintStatus = "resolved"
+	VENDOR_COMPLAINT_STATUS_DISMISSED    VendorComplaintStatus = "dismissed"
+
+	COMPLAINT_RESOLUTION_NONE        ComplaintResolution = "none"
+	COMPLAINT_RESOLUTION_WARNING     ComplaintResolution = "warning"
+	COMPLAINT_RESOLUTION_RESTRICTION ComplaintResolution = "restriction"
+
+	SYSTEM_COMPLAINT_STATUS_OPEN        SystemComplaintStatus = "open"
+	SYSTEM_COMPLAINT_STATUS_IN_PROGRESS SystemComplaintStatus = "in_progress"
+	SYSTEM_COMPLAINT_STATUS_RESOLVED    SystemComplaintStatus = "resolved"
+	SYSTEM_COMPLAINT_STATUS_CLOSED      SystemComplaintStatus = "closed"
+
+	COMPLAINT_PRIORITY_LOW    ComplaintPriority = "low"
+	COMPLAINT_PRIORITY_MEDIUM ComplaintPriority = "medium"
+	COMPLAINT_PRIORITY_HIGH   ComplaintPriority = "high"
+	COMPLAINT_PRIORITY_URGENT ComplaintPriority = "urgent"
+
+	COMPLAINT_REPLY_ROLE_REPORTER ComplaintReplyRole = "reporter"
+	COMPLAINT_REPLY_ROLE_STAFF    ComplaintReplyRole = "staff"
+)
+
+func (s SystemComplaintStatus) IsValid() bool {
+	switch s {
+	case SYSTEM_COMPLAINT_STATUS_OPEN, SYSTEM_COMPLAINT_STATUS_IN_PROGRESS, SYSTEM_COMPLAINT_STATUS_RESOLVED, SYSTEM_COMPLAINT_STATUS_CLOSED:
+		return true
+	}
+
+	return false
+}
+
+func (p ComplaintPriority) IsValid() bool {
+	switch p {
+	case COMPLAINT_PRIORITY_LOW, COMPLAINT_PRIORITY_MEDIUM, COMPLAINT_PRIORITY_HIGH, COMPLAINT_PRIORITY_URGENT:
+		return true
+	}
+
+	return false
+}
+
+type VendorComplaintModel struct {
 	Model
 	UpdateableModel
-	Code    int    `json:"code" db:"code"`
-	Title   string `json:"title" db:"title"`
-	Content string `json:"content" db:"content"`
+	ComplainantId  *int                  `json:"complainantId" db:"complainantId"`
+	VendorId       int                   `json:"vendorId" db:"vendorId"`
+	TransactionId  *int                  `json:"transactionId" db:"transactionId"`
+	Title          string                `json:"title" db:"title"`
+	Content        string                `json:"content" db:"content"`
+	Status         VendorComplaintStatus `json:"status" db:"status"`
+	AssignedTo     *int                  `json:"assignedTo" db:"assignedTo"`
+	Resolution     ComplaintResolution   `json:"resolution" db:"resolution"`
+	ResolutionNote string                `json:"resolutionNote" db:"resolutionNote"`
+	ResolvedAt     *string               `json:"resolvedAt" db:"resolvedAt"`
 }
 
-func NewComplaintModel() *ComplaintModel {
-	return &ComplaintModel{}
+func NewVendorComplaintModel() *VendorComplaintModel {
+	return &VendorComplaintModel{}
+}
+
+type VendorComplaintEvidenceModel struct {
+	Model
+	UpdateableModel
+	ComplaintId int    `json:"complaintId" db:"complaintId"`
+	Url         string `json:"url" db:"url"`
+}
+
+type VendorComplaintCommentModel struct {
+	Model
+	UpdateableModel
+	ComplaintId int    `json:"complaintId" db:"complaintId"`
+	AdminId     int    `json:"adminId" db:"adminId"`
+	Comment     string `json:"comment" db:"comment"`
 }
 
 type SystemComplaintModel struct {
 	Model
 	UpdateableModel
-	Title  string `json:"title" db:"title"`
-	Detail string `json:"detail" db:"detail"`
+	ReporterId *int                  `json:"reporterId" db:"reporterId"`
+	Title      string                `json:"title" db:"title"`
+	Detail     string                `json:"detail" db:"detail"`
+	Status     SystemComplaintStatus `json:"status" db:"status"`
+	Priority   ComplaintPriority     `json:"priority" db:"priority"`
+	AssignedTo *int                  `json:"assignedTo" db:"assignedTo"`
+}
+
+type SystemComplaintReplyModel struct {
+	Model
+	UpdateableModel
+	ComplaintId int                `json:"complaintId" db:"complaintId"`
+	ParentId    *int               `json:"parentId" db:"parentId"`
+	AuthorId    int                `json:"authorId" db:"authorId"`
+	AuthorRole  ComplaintReplyRole `json:"authorRole" db:"authorRole"`
+	Content     string             `json:"content" db:"content"`
 }
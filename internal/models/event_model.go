@@ -0,0 +1,58 @@
+package models
+
+import "encoding/json"
+
+type EventType string
+
+const (
+	EVENT_TRANSACTION_CREATED   EventType = "TransactionCreated"
+	EVENT_TRANSACTION_COMPLETED EventType = "TransactionCompleted"
+	EVENT_TRANSACTION_CANCELLED EventType = "TransactionCancelled"
+	EVENT_REVIEW_POSTED         EventType = "ReviewPosted"
+	EVENT_APPLICATION_APPROVED  EventType = "ApplicationApproved"
+	EVENT_APPLICATION_REJECTED  EventType = "ApplicationRejected"
+	EVENT_VENDOR_RESTRICTED     EventType = "VendorRestricted"
+	EVENT_MESSAGE_SENT          EventType = "MessageSent"
+)
+
+type OutboxEventModel struct {
+	Id          int             `json:"id" db:"id"`
+	EventType   EventType       `json:"eventType" db:"eventType"`
+	AggregateId int             `json:"aggregateId" db:"aggregateId"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	CreatedAt   string          `json:"createdAt" db:"createdAt"`
+}
+
+type TransactionEvent struct {
+	TransactionId int `json:"transactionId"`
+	VendorId      int `json:"vendorId"`
+	ClientId      int `json:"clientId"`
+	ServiceId     int `json:"serviceId"`
+}
+
+type ReviewEvent struct {
+	ReviewId      int `json:"reviewId"`
+	TransactionId int `json:"transactionId"`
+	ServiceId     int `json:"serviceId"`
+	VendorId      int `json:"vendorId"`
+}
+
+type ApplicationEvent struct {
+	ApplicationId int    `json:"applicationId"`
+	ApplicantId   int    `json:"applicantId"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type VendorRestrictedEvent struct {
+	RestrictionId int  `json:"restrictionId"`
+	VendorId      int  `json:"vendorId"`
+	ComplaintId   *int `json:"complaintId"`
+	DurationDays  int  `json:"durationDays"`
+}
+
+type MessageEvent struct {
+	MessageId int `json:"messageId"`
+	Sender    int `json:"sender"`
+	Receiver  int `json:"receiver"`
+}
@@ -0,0 +1,153 @@
+package models
+
+type ExportList string
+type ExportFormat string
+type ExportStatus string
+
+const (
+	EXPORT_LIST_USERS             ExportList = "users"
+	EXPORT_LIST_VENDORS           ExportList = "vendors"
+	EXPORT_LIST_APPLICATIONS      ExportList = "applications"
+	EXPORT_LIST_TRANSACTIONS      ExportList = "transactions"
+	EXPORT_LIST_SYSTEM_COMPLAINTS ExportList = "system_complaints"
+	EXPORT_LIST_VENDOR_COMPLAINTS ExportList = "vendor_complaints"
+	EXPORT_LIST_VERIFICATIONS     ExportList = "verifications"
+
+	EXPORT_FORMAT_CSV  ExportFormat = "csv"
+	EXPORT_FORMAT_XLSX ExportFormat = "xlsx"
+
+	EXPORT_STATUS_STARTED   ExportStatus = "started"
+	EXPORT_STATUS_COMPLETED ExportStatus = "completed"
+	EXPORT_STATUS_FAILED    ExportStatus = "failed"
+)
+
+// Written in place of encrypted values for roles that may not read them
+const EXPORT_REDACTED = "[redacted]"
+
+// Roles allowed to see the personal data that is stored encrypted
+var ExportDecryptRoles = []AdminRole{ADMIN_ROLE_ADMIN}
+
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case EXPORT_FORMAT_CSV, EXPORT_FORMAT_XLSX:
+		return true
+	}
+
+	return false
+}
+
+func CanDecryptExport(role AdminRole) bool {
+	for _, allowed := range ExportDecryptRoles {
+		if allowed == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ExportColumn struct {
+	Title     string
+	Encrypted bool
+}
+
+// Columns of every list in the order the rows are written, the export
+// queries select their values in the same order
+var ExportColumns = map[ExportList][]ExportColumn{
+	EXPORT_LIST_USERS: {
+		{Title: "ID"},
+		{Title: "Name", Encrypted: true},
+		{Title: "Email", Encrypted: true},
+		{Title: "Image URL"},
+		{Title: "Created At"},
+	},
+	EXPORT_LIST_VENDORS: {
+		{Title: "ID"},
+		{Title: "User ID"},
+		{Title: "Name", Encrypted: true},
+		{Title: "Job"},
+		{Title: "Rating"},
+		{Title: "Restricted"},
+		{Title: "Created At"},
+	},
+	EXPORT_LIST_APPLICATIONS: {
+		{Title: "ID"},
+		{Title: "Applicant ID"},
+		{Title: "Applicant", Encrypted: true},
+		{Title: "Job"},
+		{Title: "Latitude"},
+		{Title: "Longitude"},
+		{Title: "Status"},
+		{Title: "Rejection Reason"},
+		{Title: "Reviewed By"},
+		{Title: "Reviewed At"},
+		{Title: "Created At"},
+	},
+	EXPORT_LIST_TRANSACTIONS: {
+		{Title: "ID"},
+		{Title: "Service ID"},
+		{Title: "Client ID"},
+		{Title: "Client", Encrypted: true},
+		{Title: "Vendor ID"},
+		{Title: "Vendor", Encrypted: true},
+		{Title: "Status"},
+		{Title: "Start"},
+		{Title: "End"},
+		{Title: "Price"},
+		{Title: "Currency"},
+		{Title: "Created At"},
+		{Title: "Completed At"},
+	},
+	EXPORT_LIST_SYSTEM_COMPLAINTS: {
+		{Title: "ID"},
+		{Title: "Reporter ID"},
+		{Title: "Title", Encrypted: true},
+		{Title: "Detail", Encrypted: true},
+		{Title: "Status"},
+		{Title: "Priority"},
+		{Title: "Assigned To"},
+		{Title: "Created At"},
+	},
+	EXPORT_LIST_VENDOR_COMPLAINTS: {
+		{Title: "ID"},
+		{Title: "Complainant ID"},
+		{Title: "Vendor ID"},
+		{Title: "Transaction ID"},
+		{Title: "Title", Encrypted: true},
+		{Title: "Content", Encrypted: true},
+		{Title: "Status"},
+		{Title: "Resolution"},
+		{Title: "Resolution Note", Encrypted: true},
+		{Title: "Assigned To"},
+		{Title: "Resolved At"},
+		{Title: "Created At"},
+	},
+	EXPORT_LIST_VERIFICATIONS: {
+		{Title: "ID"},
+		{Title: "User ID"},
+		{Title: "Name", Encrypted: true},
+		{Title: "Address", Encrypted: true},
+		{Title: "ID Type"},
+		{Title: "ID Number", Encrypted: true},
+		{Title: "Status"},
+		{Title: "Reason"},
+		{Title: "Reviewed By"},
+		{Title: "Reviewed At"},
+		{Title: "Created At"},
+	},
+}
+
+// An export is recorded before the first row is sent, so exports that never
+// finish still show up in the audit trail
+type ExportAuditModel struct {
+	Model
+	AdminId     int          `json:"adminId" db:"adminId"`
+	List        ExportList   `json:"list" db:"list"`
+	Format      ExportFormat `json:"format" db:"format"`
+	Filters     string       `json:"filters" db:"filters"`
+	Decrypted   bool         `json:"decrypted" db:"decrypted"`
+	Status      ExportStatus `json:"status" db:"status"`
+	Rows        int          `json:"rows" db:"rowCount"`
+	Error       string       `json:"error" db:"error"`
+	CompletedAt *string      `json:"completedAt" db:"completedAt"`
+}
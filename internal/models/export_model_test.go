@@ -0,0 +1,19 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanDecryptExport(t *testing.T) {
+	assert.True(t, CanDecryptExport(ADMIN_ROLE_ADMIN))
+	assert.False(t, CanDecryptExport(ADMIN_ROLE_STAFF))
+	assert.False(t, CanDecryptExport(AdminRole("")))
+}
+
+func TestExportFormat(t *testing.T) {
+	assert.True(t, EXPORT_FORMAT_CSV.IsValid())
+	assert.True(t, EXPORT_FORMAT_XLSX.IsValid())
+	assert.False(t, ExportFormat("pdf").IsValid())
+}
@@ -6,15 +6,28 @@ import (
 	"strings"
 )
 
+type MessageType string
+
+const (
+	MESSAGE_TYPE_TEXT           MessageType = "text"
+	MESSAGE_TYPE_QUOTE          MessageType = "quote"
+	MESSAGE_TYPE_QUOTE_ACCEPTED MessageType = "quote_accepted"
+	MESSAGE_TYPE_QUOTE_DECLINED MessageType = "quote_declined"
+)
+
 type MessageModel struct {
 	Model
-	Sender   int    `json:"sender" db:"sender"`
-	Receiver int    `json:"receiver" db:"receiver"`
-	Content  string `json:"content" db:"content"`
+	Sender   int         `json:"sender" db:"sender"`
+	Receiver int         `json:"receiver" db:"receiver"`
+	Content  string      `json:"content" db:"content"`
+	Type     MessageType `json:"type" db:"type"`
+	QuoteId  *int        `json:"quoteId" db:"quoteId"`
 }
 
 func NewMessageModel() *MessageModel {
-	return &MessageModel{}
+	return &MessageModel{
+		Type: MESSAGE_TYPE_TEXT,
+	}
 }
 
 func MessageValueMapFactory(queryParam string) (map[string]int, error) {
@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+type NotificationType string
+type DevicePlatform string
+
+const (
+	NOTIFICATION_TRANSACTION_NEW       NotificationType = "transaction.new"
+	NOTIFICATION_TRANSACTION_COMPLETED NotificationType = "transaction.completed"
+	NOTIFICATION_APPLICATION_APPROVED  NotificationType = "application.approved"
+	NOTIFICATION_APPLICATION_REJECTED  NotificationType = "application.rejected"
+	NOTIFICATION_REVIEW_NEW            NotificationType = "review.new"
+	NOTIFICATION_MESSAGE_NEW           NotificationType = "message.new"
+
+	DEVICE_PLATFORM_ANDROID DevicePlatform = "android"
+	DEVICE_PLATFORM_IOS     DevicePlatform = "ios"
+	DEVICE_PLATFORM_WEB     DevicePlatform = "web"
+)
+
+func (p DevicePlatform) IsValid() bool {
+	switch p {
+	case DEVICE_PLATFORM_ANDROID, DEVICE_PLATFORM_IOS, DEVICE_PLATFORM_WEB:
+		return true
+	}
+
+	return false
+}
+
+type NotificationModel struct {
+	Model
+	UserId  int              `json:"userId" db:"userId"`
+	Type    NotificationType `json:"type" db:"type"`
+	Payload json.RawMessage  `json:"payload" db:"payload"`
+	ReadAt  *string          `json:"readAt" db:"readAt"`
+}
+
+type DeviceTokenModel struct {
+	Model
+	UserId   int            `json:"userId" db:"userId"`
+	Token    string         `json:"token" db:"token"`
+	Platform DevicePlatform `json:"platform" db:"platform"`
+}
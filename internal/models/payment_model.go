@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+type PaymentStatus string
+type LedgerAccount string
+type JournalType string
+type PaymentEventType string
+
+const (
+	PAYMENT_STATUS_PENDING    PaymentStatus = "pending"
+	PAYMENT_STATUS_AUTHORIZED PaymentStatus = "authorized"
+	PAYMENT_STATUS_CAPTURED   PaymentStatus = "captured"
+	PAYMENT_STATUS_RELEASED   PaymentStatus = "released"
+	PAYMENT_STATUS_REFUNDED   PaymentStatus = "refunded"
+	PAYMENT_STATUS_FAILED     PaymentStatus = "failed"
+)
+
+// Provider clearing is the money held by the payment provider, escrow is
+// owed to the client until the job is done, vendor payable is owed to the
+// vendor and platform fees are ours
+const (
+	LEDGER_ACCOUNT_PROVIDER_CLEARING LedgerAccount = "provider_clearing"
+	LEDGER_ACCOUNT_ESCROW            LedgerAccount = "escrow"
+	LEDGER_ACCOUNT_VENDOR_PAYABLE    LedgerAccount = "vendor_payable"
+	LEDGER_ACCOUNT_PLATFORM_FEES     LedgerAccount = "platform_fees"
+)
+
+const (
+	JOURNAL_CHARGE  JournalType = "charge"
+	JOURNAL_RELEASE JournalType = "release"
+	JOURNAL_REFUND  JournalType = "refund"
+)
+
+// Normalized provider webhook events
+const (
+	PAYMENT_EVENT_CAPTURED PaymentEventType = "charge.captured"
+	PAYMENT_EVENT_REFUNDED PaymentEventType = "charge.refunded"
+	PAYMENT_EVENT_FAILED   PaymentEventType = "charge.failed"
+)
+
+var (
+	ErrTransactionNotOngoing = errors.New("transaction is no longer ongoing")
+	ErrPaymentChanged        = errors.New("payment changed while cancelling, try again")
+)
+
+// Share of a released payment kept by the platform
+const PLATFORM_FEE_RATE = 0.10
+
+// Rounded to cents so the release journal balances
+func PlatformFee(amount float64) float64 {
+	return RoundMoney(amount * PLATFORM_FEE_RATE)
+}
+
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+type PaymentModel struct {
+	Model
+	UpdateableModel
+	TransactionId int           `json:"transactionId" db:"transactionId"`
+	ClientId      int           `json:"clientId" db:"clientId"`
+	VendorId      int           `json:"vendorId" db:"vendorId"`
+	Provider      string        `json:"provider" db:"provider"`
+	Reference     string        `json:"reference" db:"reference"`
+	Amount        float64       `json:"amount" db:"amount"`
+	Currency      string        `json:"currency" db:"currency"`
+	Status        PaymentStatus `json:"status" db:"status"`
+}
+
+type LedgerEntryModel struct {
+	Model
+	JournalId   int           `json:"journalId" db:"journalId"`
+	JournalType JournalType   `json:"journalType" db:"journalType"`
+	Account     LedgerAccount `json:"account" db:"account"`
+	UserId      *int          `json:"userId" db:"userId"`
+	Debit       float64       `json:"debit" db:"debit"`
+	Credit      float64       `json:"credit" db:"credit"`
+	Currency    string        `json:"currency" db:"currency"`
+}
+
+// Balance is debits minus credits
+type LedgerBalanceModel struct {
+	Account  LedgerAccount `json:"account" db:"account"`
+	Currency string        `json:"currency" db:"currency"`
+	Debit    float64       `json:"debit" db:"debit"`
+	Credit   float64       `json:"credit" db:"credit"`
+	Balance  float64       `json:"balance" db:"balance"`
+}
+
+// What the ledger says went through the provider for a single payment
+type PaymentLedgerTotalModel struct {
+	PaymentId     int           `json:"paymentId" db:"paymentId"`
+	TransactionId int           `json:"transactionId" db:"transactionId"`
+	Reference     string        `json:"reference" db:"reference"`
+	Currency      string        `json:"currency" db:"currency"`
+	Status        PaymentStatus `json:"status" db:"status"`
+	Captured      float64       `json:"captured" db:"captured"`
+	Refunded      float64       `json:"refunded" db:"refunded"`
+}
+
+type PaymentWebhookEventModel struct {
+	Model
+	Provider  string           `json:"provider" db:"provider"`
+	EventId   string           `json:"eventId" db:"eventId"`
+	Type      PaymentEventType `json:"type" db:"type"`
+	Reference string           `json:"reference" db:"reference"`
+	Amount    float64          `json:"amount" db:"amount"`
+	Payload   json.RawMessage  `json:"payload" db:"payload"`
+}
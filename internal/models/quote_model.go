@@ -0,0 +1,44 @@
+package models
+
+type QuoteStatus string
+
+const (
+	QUOTE_STATUS_PENDING  QuoteStatus = "pending"
+	QUOTE_STATUS_ACCEPTED QuoteStatus = "accepted"
+	QUOTE_STATUS_DECLINED QuoteStatus = "declined"
+)
+
+// Version points at the latest revision, only that one can be accepted
+type QuoteModel struct {
+	Model
+	UpdateableModel
+	VendorId      int                 `json:"vendorId" db:"vendorId"`
+	ClientId      int                 `json:"clientId" db:"clientId"`
+	ServiceId     int                 `json:"serviceId" db:"serviceId"`
+	Status        QuoteStatus         `json:"status" db:"status"`
+	Version       int                 `json:"version" db:"version"`
+	TransactionId *int                `json:"transactionId" db:"transactionId"`
+	Versions      []QuoteVersionModel `json:"versions,omitempty" db:"-"`
+}
+
+type QuoteVersionModel struct {
+	Id         int                  `json:"id" db:"id"`
+	QuoteId    int                  `json:"quoteId" db:"quoteId"`
+	Version    int                  `json:"version" db:"version"`
+	Total      float64              `json:"total" db:"total"`
+	Currency   string               `json:"currency" db:"currency"`
+	Start      string               `json:"start" db:"start"`
+	End        string               `json:"end" db:"end"`
+	ValidUntil string               `json:"validUntil" db:"validUntil"`
+	Note       *string              `json:"note" db:"note"`
+	CreatedAt  string               `json:"createdAt" db:"createdAt"`
+	LineItems  []QuoteLineItemModel `json:"lineItems" db:"-"`
+}
+
+type QuoteLineItemModel struct {
+	Id             int     `json:"id" db:"id"`
+	QuoteVersionId int     `json:"quoteVersionId" db:"quoteVersionId"`
+	Title          string  `json:"title" db:"title"`
+	Quantity       float64 `json:"quantity" db:"quantity"`
+	UnitPrice      float64 `json:"unitPrice" db:"unitPrice"`
+}
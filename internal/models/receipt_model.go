@@ -0,0 +1,33 @@
+package models
+
+type ReceiptModel struct {
+	Model
+	TransactionId int     `json:"transactionId" db:"transactionId"`
+	VendorId      int     `json:"vendorId" db:"vendorId"`
+	ClientId      int     `json:"clientId" db:"clientId"`
+	InvoiceNumber int     `json:"invoiceNumber" db:"invoiceNumber"`
+	Revision      int     `json:"revision" db:"revision"`
+	Subtotal      float64 `json:"subtotal" db:"subtotal"`
+	Tax           float64 `json:"tax" db:"tax"`
+	Total         float64 `json:"total" db:"total"`
+	Currency      string  `json:"currency" db:"currency"`
+	Path          string  `json:"-" db:"path"`
+	Reason        *string `json:"reason" db:"reason"`
+}
+
+// Everything printed on a receipt, names are still encrypted
+type ReceiptSourceModel struct {
+	TransactionId int               `db:"transactionId"`
+	VendorId      int               `db:"vendorId"`
+	ClientId      int               `db:"clientId"`
+	Vendor        string            `db:"vendor"`
+	Client        string            `db:"client"`
+	Service       string            `db:"service"`
+	Start         string            `db:"start"`
+	End           string            `db:"end"`
+	Status        TransactionStatus `db:"status"`
+	Price         *float64          `db:"price"`
+	Currency      *string           `db:"currency"`
+	QuoteId       *int              `db:"quoteId"`
+	CompletedAt   string            `db:"completedAt"`
+}
@@ -1,19 +1,45 @@
 package models
 
+type PricingUnit string
+
+const (
+	PRICING_UNIT_HOUR  PricingUnit = "hour"
+	PRICING_UNIT_VISIT PricingUnit = "visit"
+	PRICING_UNIT_FIXED PricingUnit = "fixed"
+)
+
+// Currency of services created before pricing was structured
+const DEFAULT_CURRENCY = "PHP"
+
 type ServiceSearchResult struct {
 	ServiceModel
-	Vendor string `json:"vendor" db:"vendor"`
+	Vendor   string  `json:"vendor" db:"vendor"`
+	Rating   float64 `json:"rating" db:"rating"`
+	Verified bool    `json:"verified" db:"verified"`
+	Distance float64 `json:"distance" db:"distance"`
 }
 
 type ServiceModel struct {
 	Model
 	UpdateableModel
 	GeoSpatialModel
-	VendorId    int    `json:"vendorId" db:"vendorId" validate:"required"`
-	Description string `json:"description" db:"description" validate:"required"`
-	Rate        string `json:"rate" db:"rate" validate:"required"`
+	VendorId      int         `json:"vendorId" db:"vendorId" validate:"required"`
+	Title         string      `json:"title" db:"title" validate:"required"`
+	Description   string      `json:"description" db:"description" validate:"required"`
+	Rate          float64     `json:"rate" db:"rate" validate:"required"`
+	PricingUnit   PricingUnit `json:"pricingUnit" db:"pricingUnit"`
+	MinimumCharge float64     `json:"minimumCharge" db:"minimumCharge"`
+	Currency      string      `json:"currency" db:"currency"`
 }
 
 func NewServiceModel() *ServiceModel {
 	return &ServiceModel{}
 }
+
+// Optional extra charged on top of the rate, ex: materials or travel
+type ServiceAddOnModel struct {
+	Id        int     `json:"id" db:"id"`
+	ServiceId int     `json:"serviceId" db:"serviceId"`
+	Title     string  `json:"title" db:"title"`
+	Price     float64 `json:"price" db:"price"`
+}
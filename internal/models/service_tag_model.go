@@ -3,5 +3,14 @@ package models
 type ServiceTagModel struct {
 	Id        int    `db:"id"`
 	ServiceId int    `db:"serviceId"`
+	TagId     int    `db:"tagId"`
 	Tag       string `db:"tag"`
 }
+
+// A tagged service of an active vendor, used to count nearby services per tag
+type ServiceTagLocationModel struct {
+	TagId     int     `db:"tagId"`
+	ServiceId int     `db:"serviceId"`
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+}
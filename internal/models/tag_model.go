@@ -1,10 +1,37 @@
 package models
 
+import "strings"
+
 type TagModel struct {
 	Model
-	Title string `json:"title" db:"title"`
+	Title      string  `json:"title" db:"title"`
+	CategoryId *int    `json:"categoryId" db:"categoryId"`
+	MergedInto *int    `json:"mergedInto,omitempty" db:"mergedInto"`
+	RetiredAt  *string `json:"retiredAt,omitempty" db:"retiredAt"`
 }
 
 func NewTagModel() *TagModel {
 	return &TagModel{}
 }
+
+type TagCategoryModel struct {
+	Model
+	UpdateableModel
+	Title    string `json:"title" db:"title"`
+	ParentId *int   `json:"parentId" db:"parentId"`
+}
+
+type TagSynonymModel struct {
+	Model
+	TagId int    `json:"tagId" db:"tagId"`
+	Title string `json:"title" db:"title"`
+}
+
+// Returned when a vendor sends tag titles that match no tag or synonym
+type UnknownTagsError struct {
+	Titles []string
+}
+
+func (e *UnknownTagsError) Error() string {
+	return "unknown tags: " + strings.Join(e.Titles, ", ")
+}
@@ -15,13 +15,20 @@ const (
 type TransactionModel struct {
 	Model
 	UpdateableModel
-	VendorId   int               `json:"vendorId" db:"vendorId" validate:"required"`
-	ClientId   int               `json:"clientId" db:"clientId" validate:"required"`
-	ServiceId  int               `json:"serviceId" db:"serviceId" validate:"required"`
-	Start      string            `json:"start" db:"start" validate:"required"`
-	End        string            `json:"end" db:"end" validate:"required"`
-	Status     TransactionStatus `json:"status" db:"status"`
-	IsReviewed bool              `json:"isReviewed" db:"isReviewed"`
+	VendorId    int               `json:"vendorId" db:"vendorId" validate:"required"`
+	ClientId    int               `json:"clientId" db:"clientId" validate:"required"`
+	ServiceId   int               `json:"serviceId" db:"serviceId" validate:"required"`
+	Start       string            `json:"start" db:"start" validate:"required"`
+	End         string            `json:"end" db:"end" validate:"required"`
+	Status      TransactionStatus `json:"status" db:"status"`
+	IsReviewed  bool              `json:"isReviewed" db:"isReviewed"`
+	Price       *float64          `json:"price" db:"price"`
+	Currency    *string           `json:"currency" db:"currency"`
+	QuoteId     *int              `json:"quoteId" db:"quoteId"`
+	CompletedAt *string           `json:"completedAt" db:"completedAt"`
+	// Snapshot of the service policy at booking time
+	CancellationPolicy  *CancellationPolicy `json:"cancellationPolicy" db:"cancellationPolicy"`
+	CancellationWindows CancellationWindows `json:"cancellationWindows" db:"cancellationWindows"`
 }
 
 func NewTransactionModel() *TransactionModel {
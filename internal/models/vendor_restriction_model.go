@@ -0,0 +1,39 @@
+package models
+
+import "errors"
+
+type AppealStatus string
+
+const (
+	APPEAL_STATUS_ALL      AppealStatus = "all"
+	APPEAL_STATUS_PENDING  AppealStatus = "pending"
+	APPEAL_STATUS_ACCEPTED AppealStatus = "accepted"
+	APPEAL_STATUS_REJECTED AppealStatus = "rejected"
+)
+
+var ErrVendorAlreadyRestricted = errors.New("vendor is already restricted")
+
+type VendorRestrictionModel struct {
+	Model
+	UpdateableModel
+	VendorId    int     `json:"vendorId" db:"vendorId"`
+	Reason      string  `json:"reason" db:"reason"`
+	IssuedBy    int     `json:"issuedBy" db:"issuedBy"`
+	ComplaintId *int    `json:"complaintId" db:"complaintId"`
+	StartsAt    string  `json:"startsAt" db:"startsAt"`
+	ExpiresAt   *string `json:"expiresAt" db:"expiresAt"`
+	LiftedAt    *string `json:"liftedAt" db:"liftedAt"`
+	LiftedBy    *int    `json:"liftedBy" db:"liftedBy"`
+}
+
+type VendorRestrictionAppealModel struct {
+	Model
+	UpdateableModel
+	RestrictionId int          `json:"restrictionId" db:"restrictionId"`
+	VendorId      int          `json:"vendorId" db:"vendorId"`
+	Content       string       `json:"content" db:"content"`
+	Status        AppealStatus `json:"status" db:"status"`
+	ReviewedBy    *int         `json:"reviewedBy" db:"reviewedBy"`
+	ReviewNote    string       `json:"reviewNote" db:"reviewNote"`
+	ReviewedAt    *string      `json:"reviewedAt" db:"reviewedAt"`
+}
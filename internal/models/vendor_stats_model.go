@@ -0,0 +1,131 @@
+package models
+
+import "math"
+
+type ActivitySubject string
+
+const (
+	ACTIVITY_SUBJECT_VENDOR  ActivitySubject = "vendor"
+	ACTIVITY_SUBJECT_SERVICE ActivitySubject = "service"
+)
+
+// Views and search impressions of a vendor profile or a service on one day
+type ActivityCounterModel struct {
+	Subject     ActivitySubject `json:"subject" db:"subject"`
+	SubjectId   int             `json:"subjectId" db:"subjectId"`
+	Day         string          `json:"day" db:"day"`
+	Views       int             `json:"views" db:"views"`
+	Impressions int             `json:"impressions" db:"impressions"`
+}
+
+// Earnings are the prices of completed jobs, kept apart per currency
+type EarningModel struct {
+	Currency string  `json:"currency" db:"currency"`
+	Amount   float64 `json:"amount" db:"amount"`
+}
+
+type ServiceStatsModel struct {
+	ServiceId     int            `json:"serviceId" db:"serviceId"`
+	Title         string         `json:"title" db:"title"`
+	Currency      string         `json:"currency" db:"currency"`
+	Views         int            `json:"views" db:"views"`
+	Impressions   int            `json:"impressions" db:"impressions"`
+	Bookings      int            `json:"bookings" db:"bookings"`
+	CompletedJobs int            `json:"completedJobs" db:"completedJobs"`
+	Earnings      []EarningModel `json:"earnings" db:"-"`
+	RatingSum     int            `json:"-" db:"ratingSum"`
+	RatingCount   int            `json:"ratingCount" db:"ratingCount"`
+	AverageRating *float64       `json:"averageRating" db:"-"`
+	// Share of impressions that led to a view of the service
+	ViewRate float64 `json:"viewRate" db:"-"`
+}
+
+func (s *ServiceStatsModel) Calculate() {
+	s.AverageRating = averageRating(s.RatingSum, s.RatingCount)
+	s.ViewRate = ratio(s.Views, s.Impressions)
+	if s.Earnings == nil {
+		s.Earnings = make([]EarningModel, 0)
+	}
+}
+
+// A conversation counts as an inquiry in the period its first message was
+// sent in, and as converted once the client booked the vendor after it
+type InquiryStatsModel struct {
+	Inquiries              int      `json:"inquiries" db:"inquiries"`
+	Converted              int      `json:"converted" db:"converted"`
+	ConversionRate         float64  `json:"conversionRate" db:"-"`
+	AverageResponseSeconds *float64 `json:"averageResponseSeconds" db:"averageResponseSeconds"`
+}
+
+func (i *InquiryStatsModel) Calculate() {
+	i.ConversionRate = ratio(i.Converted, i.Inquiries)
+	if i.AverageResponseSeconds != nil {
+		rounded := math.Round(*i.AverageResponseSeconds)
+		i.AverageResponseSeconds = &rounded
+	}
+}
+
+type VendorStatsPointModel struct {
+	Bucket        string         `json:"bucket" db:"bucket"`
+	ProfileViews  int            `json:"profileViews" db:"profileViews"`
+	ServiceViews  int            `json:"serviceViews" db:"serviceViews"`
+	Impressions   int            `json:"impressions" db:"impressions"`
+	CompletedJobs int            `json:"completedJobs" db:"completedJobs"`
+	Earnings      []EarningModel `json:"earnings" db:"-"`
+	RatingSum     int            `json:"-" db:"ratingSum"`
+	RatingCount   int            `json:"ratingCount" db:"ratingCount"`
+	AverageRating *float64       `json:"averageRating" db:"-"`
+}
+
+func (p *VendorStatsPointModel) Calculate() {
+	p.AverageRating = averageRating(p.RatingSum, p.RatingCount)
+	if p.Earnings == nil {
+		p.Earnings = make([]EarningModel, 0)
+	}
+}
+
+type VendorStatsModel struct {
+	From          string                  `json:"from"`
+	To            string                  `json:"to"`
+	Bucket        AnalyticsBucket         `json:"bucket"`
+	ProfileViews  int                     `json:"profileViews"`
+	ServiceViews  int                     `json:"serviceViews"`
+	Impressions   int                     `json:"impressions"`
+	CompletedJobs int                     `json:"completedJobs"`
+	Earnings      []EarningModel          `json:"earnings"`
+	RatingCount   int                     `json:"ratingCount"`
+	AverageRating *float64                `json:"averageRating"`
+	Inquiries     *InquiryStatsModel      `json:"inquiries"`
+	Services      []ServiceStatsModel     `json:"services"`
+	Trend         []VendorStatsPointModel `json:"trend"`
+}
+
+// Totals are summed from the trend so both always agree
+func (v *VendorStatsModel) Calculate() {
+	earnings := make(map[string]float64)
+	currencies := make([]string, 0)
+	ratingSum := 0
+
+	for _, point := range v.Trend {
+		v.ProfileViews += point.ProfileViews
+		v.ServiceViews += point.ServiceViews
+		v.Impressions += point.Impressions
+		v.CompletedJobs += point.CompletedJobs
+		v.RatingCount += point.RatingCount
+		ratingSum += point.RatingSum
+
+		for _, earning := range point.Earnings {
+			if _, ok := earnings[earning.Currency]; !ok {
+				currencies = append(currencies, earning.Currency)
+			}
+			earnings[earning.Currency] = RoundMoney(earnings[earning.Currency] + earning.Amount)
+		}
+	}
+
+	v.Earnings = make([]EarningModel, 0, len(currencies))
+	for _, currency := range currencies {
+		v.Earnings = append(v.Earnings, EarningModel{Currency: currency, Amount: earnings[currency]})
+	}
+
+	v.AverageRating = averageRating(ratingSum, v.RatingCount)
+}
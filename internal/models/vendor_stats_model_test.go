@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorStatsTotals(t *testing.T) {
+	stats := &VendorStatsModel{
+		Trend: []VendorStatsPointModel{
+			{ProfileViews: 3, ServiceViews: 10, Impressions: 40, CompletedJobs: 1, RatingSum: 9, RatingCount: 2, Earnings: []EarningModel{{Currency: "PHP", Amount: 1500.5}}},
+			{ServiceViews: 5, Impressions: 20, CompletedJobs: 2, RatingSum: 3, RatingCount: 1, Earnings: []EarningModel{{Currency: "PHP", Amount: 1000.25}, {Currency: "USD", Amount: 40}}},
+		},
+	}
+	stats.Calculate()
+
+	assert.Equal(t, 3, stats.ProfileViews)
+	assert.Equal(t, 15, stats.ServiceViews)
+	assert.Equal(t, 60, stats.Impressions)
+	assert.Equal(t, 3, stats.CompletedJobs)
+	assert.Equal(t, []EarningModel{{Currency: "PHP", Amount: 2500.75}, {Currency: "USD", Amount: 40}}, stats.Earnings)
+	assert.Equal(t, 4.0, *stats.AverageRating)
+}
+
+func TestInquiryConversion(t *testing.T) {
+	seconds := 1234.6
+	inquiries := &InquiryStatsModel{Inquiries: 8, Converted: 3, AverageResponseSeconds: &seconds}
+	inquiries.Calculate()
+
+	assert.Equal(t, 0.375, inquiries.ConversionRate)
+	assert.Equal(t, 1235.0, *inquiries.AverageResponseSeconds)
+}
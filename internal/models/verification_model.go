@@ -1,15 +1,30 @@
 package models
 
+type VerificationStatus string
+
+const (
+	VERIFICATION_STATUS_ALL      VerificationStatus = ""
+	VERIFICATION_STATUS_PENDING  VerificationStatus = "pending"
+	VERIFICATION_STATUS_APPROVED VerificationStatus = "approved"
+	VERIFICATION_STATUS_REJECTED VerificationStatus = "rejected"
+	VERIFICATION_STATUS_RESUBMIT VerificationStatus = "resubmit"
+)
+
 type IdentityVerificationModel struct {
 	Model
 	UpdateableModel
-	Name     string `json:"name" db:"name" validate:"required"`
-	Address  string `json:"address" db:"address" validate:"required"`
-	IdType   string `json:"idType" db:"idType" validate:"required"`
-	IdNumber string `json:"idNumber" db:"idNumber" validate:"required"`
-	FrontId  int    `json:"frontId" db:"frontId" validate:"required"`
-	BackId   int    `json:"backId" db:"backId" validate:"required"`
-	Face     int    `json:"face" db:"face" validate:"required"`
+	User       int                `json:"user" db:"user"`
+	Name       string             `json:"name" db:"name" validate:"required"`
+	Address    string             `json:"address" db:"address" validate:"required"`
+	IdType     string             `json:"idType" db:"idType" validate:"required"`
+	IdNumber   string             `json:"idNumber" db:"idNumber" validate:"required"`
+	FrontId    int                `json:"frontId" db:"frontId"`
+	BackId     int                `json:"backId" db:"backId"`
+	Face       int                `json:"face" db:"face"`
+	Status     VerificationStatus `json:"status" db:"status"`
+	Reason     string             `json:"reason" db:"reason"`
+	ReviewedBy *int               `json:"reviewedBy" db:"reviewedBy"`
+	ReviewedAt *string            `json:"reviewedAt" db:"reviewedAt"`
 }
 
 type FrontIdModel struct {
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WEBHOOK_DELIVERY_STATUS_PENDING   WebhookDeliveryStatus = "pending"
+	WEBHOOK_DELIVERY_STATUS_SUCCEEDED WebhookDeliveryStatus = "succeeded"
+	WEBHOOK_DELIVERY_STATUS_FAILED    WebhookDeliveryStatus = "failed"
+)
+
+// Domain events that partners are allowed to subscribe to
+var WebhookEvents = []EventType{
+	EVENT_TRANSACTION_CREATED,
+	EVENT_TRANSACTION_COMPLETED,
+	EVENT_TRANSACTION_CANCELLED,
+	EVENT_APPLICATION_APPROVED,
+	EVENT_VENDOR_RESTRICTED,
+}
+
+func IsWebhookEvent(eventType EventType) bool {
+	for _, event := range WebhookEvents {
+		if event == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookModel struct {
+	Model
+	UpdateableModel
+	Url       string `json:"url" db:"url"`
+	Secret    string `json:"-" db:"secret"`
+	Events    string `json:"events" db:"events"`
+	Active    bool   `json:"active" db:"active"`
+	CreatedBy int    `json:"createdBy" db:"createdBy"`
+}
+
+// Events are stored as a comma separated list
+func (w *WebhookModel) Subscribes(eventType EventType) bool {
+	for _, event := range strings.Split(w.Events, ",") {
+		if EventType(event) == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookDeliveryModel struct {
+	Model
+	WebhookId     int                   `json:"webhookId" db:"webhookId"`
+	OutboxId      int                   `json:"outboxId" db:"outboxId"`
+	EventType     EventType             `json:"eventType" db:"eventType"`
+	Payload       json.RawMessage       `json:"payload" db:"payload"`
+	Status        WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts      int                   `json:"attempts" db:"attempts"`
+	ResponseCode  *int                  `json:"responseCode" db:"responseCode"`
+	LastError     *string               `json:"lastError" db:"lastError"`
+	NextAttemptAt string                `json:"nextAttemptAt" db:"nextAttemptAt"`
+	DeliveredAt   *string               `json:"deliveredAt" db:"deliveredAt"`
+	Url           string                `json:"-" db:"url"`
+	Secret        string                `json:"-" db:"secret"`
+}
+
+type WebhookDeliveryAttemptModel struct {
+	Model
+	DeliveryId   int     `json:"deliveryId" db:"deliveryId"`
+	ResponseCode *int    `json:"responseCode" db:"responseCode"`
+	Error        *string `json:"error" db:"error"`
+	DurationMs   int     `json:"durationMs" db:"durationMs"`
+}
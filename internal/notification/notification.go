@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"encoding/json"
+	"log"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+)
+
+// Center records notifications and hands them to every configured notifier
+type Center struct {
+	db        db.Database
+	notifiers []Notifier
+}
+
+func NewCenter(db db.Database, notifiers ...Notifier) *Center {
+	return &Center{
+		db:        db,
+		notifiers: notifiers,
+	}
+}
+
+// Stores the notification and delivers it in the background, delivery
+// failures are only logged since the notification is already recorded
+func (c *Center) Send(userId int, notificationType models.NotificationType, payload any) error {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	notification := &models.NotificationModel{
+		UserId:  userId,
+		Type:    notificationType,
+		Payload: bytes,
+	}
+
+	id, err := c.db.NewNotification(notification)
+	if err != nil {
+		return err
+	}
+
+	notification.Id = id
+
+	go c.deliver(notification)
+
+	return nil
+}
+
+func (c *Center) deliver(notification *models.NotificationModel) {
+	for _, notifier := range c.notifiers {
+		if err := notifier.Notify(notification); err != nil {
+			log.Printf("error delivering notification %d: %s\n", notification.Id, err.Error())
+		}
+	}
+}
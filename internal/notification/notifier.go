@@ -0,0 +1,7 @@
+package notification
+
+import "nearbyassist/internal/models"
+
+type Notifier interface {
+	Notify(notification *models.NotificationModel) error
+}
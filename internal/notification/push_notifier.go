@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"nearbyassist/internal/config"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+	"net/http"
+	"time"
+)
+
+var pushTitles = map[models.NotificationType]string{
+	models.NOTIFICATION_TRANSACTION_NEW:       "New booking",
+	models.NOTIFICATION_TRANSACTION_COMPLETED: "Booking completed",
+	models.NOTIFICATION_APPLICATION_APPROVED:  "Application approved",
+	models.NOTIFICATION_APPLICATION_REJECTED:  "Application rejected",
+	models.NOTIFICATION_REVIEW_NEW:            "New review",
+	models.NOTIFICATION_MESSAGE_NEW:           "New message",
+}
+
+type pushMessage struct {
+	To           string          `json:"to"`
+	Notification pushContent     `json:"notification"`
+	Data         json.RawMessage `json:"data"`
+}
+
+type pushContent struct {
+	Title string `json:"title"`
+	Tag   string `json:"tag"`
+}
+
+// PushNotifier delivers notifications to registered devices through an
+// FCM style HTTP endpoint
+type PushNotifier struct {
+	db             db.Database
+	url            string
+	serverKey      string
+	requestTimeout time.Duration
+}
+
+func NewPushNotifier(conf *config.Config, db db.Database) *PushNotifier {
+	return &PushNotifier{
+		db:             db,
+		url:            conf.PushUrl,
+		serverKey:      conf.PushServerKey,
+		requestTimeout: 5 * time.Second,
+	}
+}
+
+// Every device is tried, one that fails does not keep the others from
+// receiving the notification
+func (n *PushNotifier) Notify(notification *models.NotificationModel) error {
+	tokens, err := n.db.FindDeviceTokens(notification.UserId)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	for _, token := range tokens {
+		message := pushMessage{
+			To: token.Token,
+			Notification: pushContent{
+				Title: pushTitles[notification.Type],
+				Tag:   string(notification.Type),
+			},
+			Data: notification.Payload,
+		}
+
+		if err := n.send(message); err != nil {
+			errs = append(errs, fmt.Errorf("device %d: %w", token.Id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *PushNotifier) send(message pushMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.requestTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+n.serverKey)
+
+	client := http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("push endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"encoding/json"
+	"nearbyassist/internal/config"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushNotifierSendsToEveryDevice(t *testing.T) {
+	received := make([]pushMessage, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key=secret", r.Header.Get("Authorization"))
+
+		message := pushMessage{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		received = append(received, message)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	database := dbtest.NewFake()
+	database.DeviceTokens = []models.DeviceTokenModel{
+		{Token: "device-1", Platform: models.DEVICE_PLATFORM_ANDROID},
+		{Token: "device-2", Platform: models.DEVICE_PLATFORM_IOS},
+	}
+
+	notifier := NewPushNotifier(&config.Config{PushUrl: server.URL, PushServerKey: "secret"}, database)
+	err := notifier.Notify(&models.NotificationModel{
+		UserId:  1,
+		Type:    models.NOTIFICATION_TRANSACTION_NEW,
+		Payload: json.RawMessage(`{"transactionId":1}`),
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, received, 2)
+	assert.Equal(t, "device-1", received[0].To)
+	assert.Equal(t, "New booking", received[0].Notification.Title)
+	assert.JSONEq(t, `{"transactionId":1}`, string(received[0].Data))
+}
+
+func TestPushNotifierReportsFailedDelivery(t *testing.T) {
+	received := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		message := pushMessage{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		received = append(received, message.To)
+
+		if message.To == "device-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	database := dbtest.NewFake()
+	database.DeviceTokens = []models.DeviceTokenModel{
+		{Model: models.Model{Id: 1}, Token: "device-1"},
+		{Model: models.Model{Id: 2}, Token: "device-2"},
+	}
+
+	notifier := NewPushNotifier(&config.Config{PushUrl: server.URL}, database)
+	err := notifier.Notify(&models.NotificationModel{UserId: 1, Payload: json.RawMessage(`{}`)})
+
+	assert.EqualError(t, err, "device 1: push endpoint responded with status 401")
+	assert.Equal(t, []string{"device-1", "device-2"}, received)
+}
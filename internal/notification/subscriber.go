@@ -0,0 +1,102 @@
+package notification
+
+import (
+	"encoding/json"
+	"nearbyassist/internal/events"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/utils"
+	"nearbyassist/internal/websocket"
+)
+
+// Notifies the affected users when domain events are dispatched
+func Subscribe(bus *events.Bus, center *Center, ws *websocket.Websocket) {
+	bus.Subscribe(models.EVENT_TRANSACTION_CREATED, "notify-vendor", func(event models.OutboxEventModel) error {
+		payload := models.TransactionEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return center.Send(payload.VendorId, models.NOTIFICATION_TRANSACTION_NEW, utils.Mapper{
+			"transactionId": payload.TransactionId,
+			"serviceId":     payload.ServiceId,
+			"clientId":      payload.ClientId,
+		})
+	})
+
+	bus.Subscribe(models.EVENT_TRANSACTION_COMPLETED, "notify-vendor", func(event models.OutboxEventModel) error {
+		payload := models.TransactionEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return center.Send(payload.VendorId, models.NOTIFICATION_TRANSACTION_COMPLETED, utils.Mapper{
+			"transactionId": payload.TransactionId,
+			"serviceId":     payload.ServiceId,
+		})
+	})
+
+	bus.Subscribe(models.EVENT_TRANSACTION_COMPLETED, "notify-client", func(event models.OutboxEventModel) error {
+		payload := models.TransactionEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return center.Send(payload.ClientId, models.NOTIFICATION_TRANSACTION_COMPLETED, utils.Mapper{
+			"transactionId": payload.TransactionId,
+			"serviceId":     payload.ServiceId,
+		})
+	})
+
+	// Online receivers already got the message through the socket
+	bus.Subscribe(models.EVENT_MESSAGE_SENT, "notify-receiver", func(event models.OutboxEventModel) error {
+		payload := models.MessageEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		if ws.IsOnline(payload.Receiver) {
+			return nil
+		}
+
+		return center.Send(payload.Receiver, models.NOTIFICATION_MESSAGE_NEW, utils.Mapper{
+			"messageId": payload.MessageId,
+			"sender":    payload.Sender,
+		})
+	})
+
+	bus.Subscribe(models.EVENT_REVIEW_POSTED, "notify-vendor", func(event models.OutboxEventModel) error {
+		payload := models.ReviewEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return center.Send(payload.VendorId, models.NOTIFICATION_REVIEW_NEW, utils.Mapper{
+			"reviewId":      payload.ReviewId,
+			"transactionId": payload.TransactionId,
+			"serviceId":     payload.ServiceId,
+		})
+	})
+
+	bus.Subscribe(models.EVENT_APPLICATION_APPROVED, "notify-applicant", func(event models.OutboxEventModel) error {
+		payload := models.ApplicationEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return center.Send(payload.ApplicantId, models.NOTIFICATION_APPLICATION_APPROVED, utils.Mapper{
+			"applicationId": payload.ApplicationId,
+		})
+	})
+
+	bus.Subscribe(models.EVENT_APPLICATION_REJECTED, "notify-applicant", func(event models.OutboxEventModel) error {
+		payload := models.ApplicationEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return center.Send(payload.ApplicantId, models.NOTIFICATION_APPLICATION_REJECTED, utils.Mapper{
+			"applicationId": payload.ApplicationId,
+			"reason":        payload.Reason,
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"encoding/json"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/events"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/websocket"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletedTransactionNotifiesBothParties(t *testing.T) {
+	database := dbtest.NewFake()
+	bus := events.NewBus()
+	Subscribe(bus, NewCenter(database), websocket.NewWebsocket(database))
+
+	payload, _ := json.Marshal(models.TransactionEvent{TransactionId: 5, VendorId: 2, ClientId: 3, ServiceId: 4})
+
+	assert.NoError(t, bus.Publish(models.OutboxEventModel{Id: 1, EventType: models.EVENT_TRANSACTION_COMPLETED, Payload: payload}))
+
+	assert.Len(t, database.Notifications, 2)
+	assert.ElementsMatch(t, []int{2, 3}, []int{database.Notifications[0].UserId, database.Notifications[1].UserId})
+}
+
+func TestSentMessageNotifiesOfflineReceiver(t *testing.T) {
+	database := dbtest.NewFake()
+	bus := events.NewBus()
+	Subscribe(bus, NewCenter(database), websocket.NewWebsocket(database))
+
+	payload, _ := json.Marshal(models.MessageEvent{MessageId: 9, Sender: 2, Receiver: 3})
+
+	assert.NoError(t, bus.Publish(models.OutboxEventModel{Id: 1, EventType: models.EVENT_MESSAGE_SENT, Payload: payload}))
+
+	assert.Len(t, database.Notifications, 1)
+	assert.Equal(t, 3, database.Notifications[0].UserId)
+	assert.Equal(t, models.NOTIFICATION_MESSAGE_NEW, database.Notifications[0].Type)
+}
@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/websocket"
+)
+
+const WEBSOCKET_EVENT_NOTIFICATION = "notification"
+
+type WebsocketNotifier struct {
+	ws *websocket.Websocket
+}
+
+func NewWebsocketNotifier(ws *websocket.Websocket) *WebsocketNotifier {
+	return &WebsocketNotifier{
+		ws: ws,
+	}
+}
+
+// Forwards the notification to the user when they are connected, offline
+// users pick it up from the notification list instead
+func (n *WebsocketNotifier) Notify(notification *models.NotificationModel) error {
+	if !n.ws.IsOnline(notification.UserId) {
+		return nil
+	}
+
+	n.ws.EventChan <- websocket.Event{
+		Receiver: notification.UserId,
+		Type:     WEBSOCKET_EVENT_NOTIFICATION,
+		Data:     notification,
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/webhook"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	FAKE_PROVIDER         = "fake"
+	FAKE_SIGNATURE_HEADER = "X-Fake-Signature"
+	FAKE_TIMESTAMP_HEADER = "X-Fake-Timestamp"
+
+	// Payment method that is always declined, anything else is accepted
+	FAKE_DECLINED_METHOD = "tok_declined"
+
+	// Older webhooks are rejected as replays
+	FAKE_WEBHOOK_TOLERANCE = 5 * time.Minute
+)
+
+// In-memory provider for local testing. Charges are lost on restart, so
+// reconciliation against it only makes sense within a single run
+type Fake struct {
+	mu      sync.Mutex
+	secret  string
+	charges map[string]*Charge
+	next    int
+	now     func() time.Time
+}
+
+func NewFake(secret string) *Fake {
+	return &Fake{
+		secret:  secret,
+		charges: make(map[string]*Charge),
+		now:     time.Now,
+	}
+}
+
+func (f *Fake) Name() string {
+	return FAKE_PROVIDER
+}
+
+func (f *Fake) Authorize(method string, amount float64, currency string, transactionId int) (*Charge, error) {
+	if method == FAKE_DECLINED_METHOD {
+		return nil, ErrDeclined
+	}
+
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.next++
+	charge := &Charge{
+		Reference: fmt.Sprintf("fake_ch_%d_%d", transactionId, f.next),
+		Amount:    amount,
+		Currency:  currency,
+		Status:    CHARGE_STATUS_AUTHORIZED,
+	}
+	f.charges[charge.Reference] = charge
+
+	snapshot := *charge
+	return &snapshot, nil
+}
+
+func (f *Fake) Capture(reference string, amount float64) (*Charge, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	if charge.Status != CHARGE_STATUS_AUTHORIZED {
+		return nil, ErrInvalidState
+	}
+
+	if amount <= 0 || amount > charge.Amount {
+		return nil, ErrInvalidAmount
+	}
+
+	charge.Captured = amount
+	charge.Status = CHARGE_STATUS_CAPTURED
+
+	snapshot := *charge
+	return &snapshot, nil
+}
+
+func (f *Fake) Refund(reference string, amount float64) (*Charge, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	switch charge.Status {
+	case CHARGE_STATUS_AUTHORIZED:
+		charge.Status = CHARGE_STATUS_VOIDED
+
+	case CHARGE_STATUS_CAPTURED:
+		refunded := models.RoundMoney(charge.Refunded + amount)
+		if amount <= 0 || refunded > charge.Captured {
+			return nil, ErrInvalidAmount
+		}
+
+		charge.Refunded = refunded
+		if charge.Refunded == charge.Captured {
+			charge.Status = CHARGE_STATUS_REFUNDED
+		}
+
+	default:
+		return nil, ErrInvalidState
+	}
+
+	snapshot := *charge
+	return &snapshot, nil
+}
+
+func (f *Fake) FindCharge(reference string) (*Charge, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	snapshot := *charge
+	return &snapshot, nil
+}
+
+// Uses the same signing scheme as our outgoing webhooks
+func (f *Fake) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	timestamp, err := strconv.ParseInt(header.Get(FAKE_TIMESTAMP_HEADER), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	if age := f.now().Sub(time.Unix(timestamp, 0)); age > FAKE_WEBHOOK_TOLERANCE || age < -FAKE_WEBHOOK_TOLERANCE {
+		return nil, ErrInvalidSignature
+	}
+
+	if !webhook.Verify(f.secret, timestamp, body, header.Get(FAKE_SIGNATURE_HEADER)) {
+		return nil, ErrInvalidSignature
+	}
+
+	event := &WebhookEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, err
+	}
+
+	if event.Id == "" || event.Reference == "" {
+		return nil, fmt.Errorf("webhook event is missing its id or reference")
+	}
+
+	return event, nil
+}
+
+// Produces the headers the fake would send, for simulating webhooks locally
+func (f *Fake) SignWebhook(body []byte) http.Header {
+	timestamp := f.now().Unix()
+
+	header := http.Header{}
+	header.Set(FAKE_TIMESTAMP_HEADER, strconv.FormatInt(timestamp, 10))
+	header.Set(FAKE_SIGNATURE_HEADER, webhook.Sign(f.secret, timestamp, body))
+
+	return header
+}
@@ -0,0 +1,84 @@
+package payment
+
+import (
+	"nearbyassist/internal/models"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeChargeLifecycle(t *testing.T) {
+	fake := NewFake("secret")
+
+	charge, err := fake.Authorize("tok_visa", 1500, "PHP", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, CHARGE_STATUS_AUTHORIZED, charge.Status)
+
+	charge, err = fake.Capture(charge.Reference, 1500)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500.0, charge.Captured)
+
+	charge, err = fake.Refund(charge.Reference, 500)
+	assert.NoError(t, err)
+	assert.Equal(t, CHARGE_STATUS_CAPTURED, charge.Status)
+
+	_, err = fake.Refund(charge.Reference, 1500)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	charge, err = fake.Refund(charge.Reference, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, CHARGE_STATUS_REFUNDED, charge.Status)
+	assert.Equal(t, 1500.0, charge.Refunded)
+}
+
+func TestFakeVoidsUncapturedCharge(t *testing.T) {
+	fake := NewFake("secret")
+
+	charge, _ := fake.Authorize("tok_visa", 800, "PHP", 3)
+	charge, err := fake.Refund(charge.Reference, 800)
+
+	assert.NoError(t, err)
+	assert.Equal(t, CHARGE_STATUS_VOIDED, charge.Status)
+
+	_, err = fake.Capture(charge.Reference, 800)
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestFakeDeclinesMethod(t *testing.T) {
+	fake := NewFake("secret")
+
+	_, err := fake.Authorize(FAKE_DECLINED_METHOD, 800, "PHP", 3)
+
+	assert.ErrorIs(t, err, ErrDeclined)
+}
+
+func TestFakeVerifyWebhook(t *testing.T) {
+	fake := NewFake("secret")
+	fake.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	body := []byte(`{"id":"evt_1","type":"charge.captured","reference":"fake_ch_1_1","amount":1500}`)
+	header := fake.SignWebhook(body)
+
+	event, err := fake.VerifyWebhook(header, body)
+	assert.NoError(t, err)
+	assert.Equal(t, "evt_1", event.Id)
+	assert.Equal(t, models.PAYMENT_EVENT_CAPTURED, event.Type)
+	assert.Equal(t, 1500.0, event.Amount)
+
+	_, err = fake.VerifyWebhook(header, []byte(`{"id":"evt_1","type":"charge.refunded","reference":"fake_ch_1_1"}`))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = NewFake("other").VerifyWebhook(header, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Replayed long after it was signed
+	fake.now = func() time.Time { return time.Unix(1700000000, 0).Add(FAKE_WEBHOOK_TOLERANCE + time.Second) }
+	_, err = fake.VerifyWebhook(header, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	header.Set(FAKE_TIMESTAMP_HEADER, strconv.Itoa(0))
+	_, err = fake.VerifyWebhook(header, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
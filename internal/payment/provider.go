@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"nearbyassist/internal/config"
+	"nearbyassist/internal/models"
+	"net/http"
+)
+
+type ChargeStatus string
+
+const (
+	CHARGE_STATUS_AUTHORIZED ChargeStatus = "authorized"
+	CHARGE_STATUS_CAPTURED   ChargeStatus = "captured"
+	CHARGE_STATUS_REFUNDED   ChargeStatus = "refunded"
+	CHARGE_STATUS_VOIDED     ChargeStatus = "voided"
+)
+
+var (
+	ErrDeclined         = errors.New("payment was declined")
+	ErrChargeNotFound   = errors.New("charge not found")
+	ErrInvalidAmount    = errors.New("amount exceeds what the charge allows")
+	ErrInvalidState     = errors.New("charge cannot be changed in its current state")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// The provider's view of a charge, used to reconcile the ledger
+type Charge struct {
+	Reference string       `json:"reference"`
+	Amount    float64      `json:"amount"`
+	Captured  float64      `json:"captured"`
+	Refunded  float64      `json:"refunded"`
+	Currency  string       `json:"currency"`
+	Status    ChargeStatus `json:"status"`
+}
+
+// Webhook payloads are translated to this shape by the provider, Id is the
+// provider's event id and is what makes redelivery idempotent
+type WebhookEvent struct {
+	Id        string                  `json:"id"`
+	Type      models.PaymentEventType `json:"type"`
+	Reference string                  `json:"reference"`
+	Amount    float64                 `json:"amount"`
+}
+
+type PaymentProvider interface {
+	Name() string
+	// Places a hold on the client's payment method, method is the token
+	// produced by the provider's client side SDK
+	Authorize(method string, amount float64, currency string, transactionId int) (*Charge, error)
+	Capture(reference string, amount float64) (*Charge, error)
+	// Returns captured money, or releases the hold of a charge that was
+	// never captured
+	Refund(reference string, amount float64) (*Charge, error)
+	VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+	FindCharge(reference string) (*Charge, error)
+}
+
+// A nil provider means payments are disabled. The fake approves any payment
+// method and keeps its charges in memory, so it is only used in development.
+// Webhooks are only accepted when they can be verified
+func NewProvider(conf *config.Config) (PaymentProvider, error) {
+	switch conf.PaymentProvider {
+
+	case "":
+		return nil, nil
+
+	case config.PAYMENT_PROVIDER_FAKE:
+		if conf.Environment != config.ENVIRONMENT_DEVELOPMENT {
+			log.Println("The fake payment provider is only used when GO_ENV=development, payments are disabled")
+			return nil, nil
+		}
+
+		if conf.PaymentWebhookSecret == "" {
+			return nil, errors.New("PAYMENT_WEBHOOK_SECRET is required")
+		}
+
+		return NewFake(conf.PaymentWebhookSecret), nil
+
+	default:
+		return nil, fmt.Errorf("invalid payment provider %q", conf.PaymentProvider)
+	}
+}
@@ -0,0 +1,33 @@
+package payment
+
+import (
+	"nearbyassist/internal/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(&config.Config{Environment: config.ENVIRONMENT_DEVELOPMENT, PaymentProvider: config.PAYMENT_PROVIDER_FAKE, PaymentWebhookSecret: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, FAKE_PROVIDER, provider.Name())
+}
+
+func TestNewProviderDisabled(t *testing.T) {
+	provider, err := NewProvider(&config.Config{PaymentWebhookSecret: "secret"})
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = NewProvider(&config.Config{PaymentProvider: config.PAYMENT_PROVIDER_FAKE, PaymentWebhookSecret: "secret"})
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+}
+
+func TestNewProviderRequiresConfiguration(t *testing.T) {
+	_, err := NewProvider(&config.Config{Environment: config.ENVIRONMENT_DEVELOPMENT, PaymentProvider: config.PAYMENT_PROVIDER_FAKE})
+	assert.EqualError(t, err, "PAYMENT_WEBHOOK_SECRET is required")
+
+	_, err = NewProvider(&config.Config{PaymentProvider: "stripe", PaymentWebhookSecret: "secret"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"errors"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/models"
+)
+
+type ReconciliationStatus string
+
+const (
+	RECONCILIATION_MATCHED  ReconciliationStatus = "matched"
+	RECONCILIATION_MISMATCH ReconciliationStatus = "mismatch"
+	RECONCILIATION_MISSING  ReconciliationStatus = "missing"
+)
+
+type ReconciliationEntry struct {
+	PaymentId        int                  `json:"paymentId"`
+	TransactionId    int                  `json:"transactionId"`
+	Reference        string               `json:"reference"`
+	Currency         string               `json:"currency"`
+	PaymentStatus    models.PaymentStatus `json:"paymentStatus"`
+	LedgerCaptured   float64              `json:"ledgerCaptured"`
+	LedgerRefunded   float64              `json:"ledgerRefunded"`
+	ProviderCaptured float64              `json:"providerCaptured"`
+	ProviderRefunded float64              `json:"providerRefunded"`
+	Status           ReconciliationStatus `json:"status"`
+}
+
+type ReconciliationReport struct {
+	Provider   string                      `json:"provider"`
+	Matched    int                         `json:"matched"`
+	Mismatched int                         `json:"mismatched"`
+	Missing    int                         `json:"missing"`
+	Entries    []ReconciliationEntry       `json:"entries"`
+	Balances   []models.LedgerBalanceModel `json:"balances"`
+	Unbalanced []int                       `json:"unbalancedJournals"`
+}
+
+// Compares what the ledger recorded through the provider clearing account
+// with what the provider says it captured and refunded, charge by charge
+func Reconcile(db db.Database, provider PaymentProvider) (*ReconciliationReport, error) {
+	totals, err := db.FindPaymentLedgerTotals(provider.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := db.FindLedgerBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	unbalanced, err := db.FindUnbalancedJournals()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconciliationReport{
+		Provider:   provider.Name(),
+		Entries:    make([]ReconciliationEntry, 0, len(totals)),
+		Balances:   balances,
+		Unbalanced: unbalanced,
+	}
+
+	for _, total := range totals {
+		entry := ReconciliationEntry{
+			PaymentId:      total.PaymentId,
+			TransactionId:  total.TransactionId,
+			Reference:      total.Reference,
+			Currency:       total.Currency,
+			PaymentStatus:  total.Status,
+			LedgerCaptured: total.Captured,
+			LedgerRefunded: total.Refunded,
+		}
+
+		charge, err := provider.FindCharge(total.Reference)
+		switch {
+		case errors.Is(err, ErrChargeNotFound):
+			entry.Status = RECONCILIATION_MISSING
+			report.Missing++
+
+		case err != nil:
+			return nil, err
+
+		default:
+			entry.ProviderCaptured = charge.Captured
+			entry.ProviderRefunded = charge.Refunded
+
+			if models.RoundMoney(charge.Captured) == models.RoundMoney(total.Captured) && models.RoundMoney(charge.Refunded) == models.RoundMoney(total.Refunded) {
+				entry.Status = RECONCILIATION_MATCHED
+				report.Matched++
+			} else {
+				entry.Status = RECONCILIATION_MISMATCH
+				report.Mismatched++
+			}
+		}
+
+		report.Entries = append(report.Entries, entry)
+	}
+
+	return report, nil
+}
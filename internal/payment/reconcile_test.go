@@ -0,0 +1,42 @@
+package payment
+
+import (
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	provider := NewFake("secret")
+
+	paid, _ := provider.Authorize("tok_visa", 1000, "PHP", 1)
+	provider.Capture(paid.Reference, 1000)
+
+	refunded, _ := provider.Authorize("tok_visa", 500, "PHP", 2)
+	provider.Capture(refunded.Reference, 500)
+	provider.Refund(refunded.Reference, 500)
+
+	database := dbtest.NewFake()
+	database.LedgerTotals = []models.PaymentLedgerTotalModel{
+		{PaymentId: 1, TransactionId: 1, Reference: paid.Reference, Captured: 1000},
+		// The refund went through the provider but never reached the ledger
+		{PaymentId: 2, TransactionId: 2, Reference: refunded.Reference, Captured: 500},
+		{PaymentId: 3, TransactionId: 3, Reference: "fake_ch_3_9", Captured: 200},
+	}
+
+	report, err := Reconcile(database, provider)
+
+	assert.NoError(t, err)
+	assert.Equal(t, FAKE_PROVIDER, report.Provider)
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 1, report.Mismatched)
+	assert.Equal(t, 1, report.Missing)
+
+	assert.Equal(t, RECONCILIATION_MATCHED, report.Entries[0].Status)
+	assert.Equal(t, RECONCILIATION_MISMATCH, report.Entries[1].Status)
+	assert.Equal(t, 500.0, report.Entries[1].ProviderRefunded)
+	assert.Equal(t, 0.0, report.Entries[1].LedgerRefunded)
+	assert.Equal(t, RECONCILIATION_MISSING, report.Entries[2].Status)
+}
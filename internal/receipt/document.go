@@ -0,0 +1,154 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"nearbyassist/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Prices are VAT inclusive, the tax is only broken out on the receipt
+const TAX_RATE = 0.12
+
+type LineItem struct {
+	Title     string
+	Quantity  float64
+	UnitPrice float64
+}
+
+func (l LineItem) Amount() float64 {
+	return models.RoundMoney(l.Quantity * l.UnitPrice)
+}
+
+type Document struct {
+	InvoiceNumber string
+	Revision      int
+	Reason        string
+	IssuedAt      string
+	TransactionId int
+	Vendor        string
+	Client        string
+	Service       string
+	Start         string
+	End           string
+	Currency      string
+	LineItems     []LineItem
+	Subtotal      float64
+	Tax           float64
+	Total         float64
+}
+
+// Invoice numbers are only unique per vendor, so the vendor is part of it
+func FormatInvoiceNumber(vendorId, number int) string {
+	return fmt.Sprintf("INV-%d-%06d", vendorId, number)
+}
+
+// Splits a VAT inclusive total into its net amount and tax
+func SplitTax(total float64) (float64, float64) {
+	subtotal := models.RoundMoney(total / (1 + TAX_RATE))
+	return subtotal, models.RoundMoney(total - subtotal)
+}
+
+func Render(doc *Document) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	// The core fonts are cp1252, names can contain anything
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Official Receipt", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, "Invoice No. "+doc.InvoiceNumber, "", 1, "L", false, 0, "")
+	if doc.Revision > 1 {
+		pdf.CellFormat(0, 6, fmt.Sprintf("Revision %d", doc.Revision), "", 1, "L", false, 0, "")
+		if doc.Reason != "" {
+			pdf.MultiCell(0, 6, tr("Correction: "+doc.Reason), "", "L", false)
+		}
+	}
+	pdf.CellFormat(0, 6, "Issued "+doc.IssuedAt, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Transaction #%d", doc.TransactionId), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	details := [][2]string{
+		{"Vendor", doc.Vendor},
+		{"Client", doc.Client},
+		{"Service", doc.Service},
+		{"Schedule", doc.Start + " to " + doc.End},
+	}
+
+	for _, detail := range details {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(30, 6, detail[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(detail[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	widths := []float64{85, 20, 30, 35}
+	pdf.SetFont("Helvetica", "B", 10)
+	for i, heading := range []string{"Description", "Qty", "Unit price", "Amount"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 8, heading, "B", 0, align, false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, item := range doc.LineItems {
+		pdf.CellFormat(widths[0], 7, tr(item.Title), "", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, strconv.FormatFloat(item.Quantity, 'f', -1, 64), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, formatMoney(item.UnitPrice), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, formatMoney(item.Amount()), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	totals := [][2]string{
+		{"Subtotal", formatMoney(doc.Subtotal)},
+		{fmt.Sprintf("VAT (%d%%)", int(TAX_RATE*100)), formatMoney(doc.Tax)},
+		{"Total (" + doc.Currency + ")", formatMoney(doc.Total)},
+	}
+
+	for i, total := range totals {
+		if i == len(totals)-1 {
+			pdf.SetFont("Helvetica", "B", 11)
+		}
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, total[0], "T", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, total[1], "T", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.MultiCell(0, 5, "Amounts are VAT inclusive. Issued through NearbyAssist on behalf of the vendor.", "", "L", false)
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// 12345.5 becomes 12,345.50
+func formatMoney(amount float64) string {
+	text := strconv.FormatFloat(amount, 'f', 2, 64)
+
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+
+	whole, cents, _ := strings.Cut(text, ".")
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+
+	return sign + whole + "." + cents
+}
@@ -0,0 +1,55 @@
+package receipt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTax(t *testing.T) {
+	subtotal, tax := SplitTax(1120)
+
+	assert.Equal(t, 1000.0, subtotal)
+	assert.Equal(t, 120.0, tax)
+
+	// The parts always add back up to the total
+	subtotal, tax = SplitTax(999.99)
+	assert.Equal(t, 999.99, subtotal+tax)
+}
+
+func TestFormatInvoiceNumber(t *testing.T) {
+	assert.Equal(t, "INV-12-000034", FormatInvoiceNumber(12, 34))
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "0.50", formatMoney(0.5))
+	assert.Equal(t, "999.00", formatMoney(999))
+	assert.Equal(t, "12,345.50", formatMoney(12345.5))
+	assert.Equal(t, "-1,000,000.00", formatMoney(-1000000))
+}
+
+func TestRender(t *testing.T) {
+	doc := &Document{
+		InvoiceNumber: FormatInvoiceNumber(2, 1),
+		Revision:      2,
+		Reason:        "Wrong schedule",
+		IssuedAt:      "2026-10-19",
+		TransactionId: 5,
+		Vendor:        "Peña Plumbing",
+		Client:        "Juan Dela Cruz",
+		Service:       "Pipe repair",
+		Start:         "2026-10-10",
+		End:           "2026-10-11",
+		Currency:      "PHP",
+		LineItems:     []LineItem{{Title: "Labor", Quantity: 2, UnitPrice: 560}},
+		Subtotal:      1000,
+		Tax:           120,
+		Total:         1120,
+	}
+
+	pdf, err := Render(doc)
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+}
@@ -0,0 +1,163 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/encryption"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotCompleted = errors.New("receipts are only issued for completed transactions")
+	ErrNoPrice      = errors.New("transaction has no price")
+)
+
+// Renders receipts and stores them encrypted, like every other document
+type Generator struct {
+	db      db.Database
+	storage storage.Storage
+	crypto  encryption.Encryption
+	now     func() time.Time
+}
+
+func NewGenerator(db db.Database, storage storage.Storage, crypto encryption.Encryption) *Generator {
+	return &Generator{
+		db:      db,
+		storage: storage,
+		crypto:  crypto,
+		now:     time.Now,
+	}
+}
+
+// Issues the first receipt of a transaction, or the next revision of it when
+// a reason for the correction is given
+func (g *Generator) Generate(transactionId int, reason *string) (*models.ReceiptModel, error) {
+	source, err := g.db.FindReceiptSource(transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Status != models.TRANSACTION_STATUS_DONE {
+		return nil, ErrNotCompleted
+	}
+
+	if source.Price == nil || source.Currency == nil {
+		return nil, ErrNoPrice
+	}
+
+	doc, err := g.document(source)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &models.ReceiptModel{
+		TransactionId: transactionId,
+		VendorId:      source.VendorId,
+		ClientId:      source.ClientId,
+		Subtotal:      doc.Subtotal,
+		Tax:           doc.Tax,
+		Total:         doc.Total,
+		Currency:      doc.Currency,
+		Reason:        reason,
+	}
+
+	// The number is reserved and committed first so the invoice sequence is
+	// not locked while the document is rendered and uploaded
+	if err := g.db.ReserveReceipt(receipt); err != nil {
+		return nil, err
+	}
+
+	doc.InvoiceNumber = FormatInvoiceNumber(receipt.VendorId, receipt.InvoiceNumber)
+	doc.Revision = receipt.Revision
+
+	bytes, err := Render(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := g.crypto.EncryptFile(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("%s.pdf", uuid.New().String())
+	if receipt.Path, err = g.storage.SaveReceipt(encrypted, filename); err != nil {
+		return nil, err
+	}
+
+	if err := g.db.StoreReceipt(receipt.Id, receipt.Path); err != nil {
+		if err := g.storage.DeleteFile(receipt.Path); err != nil {
+			log.Printf("Failed to delete %s: %s\n", receipt.Path, err.Error())
+		}
+
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+func (g *Generator) document(source *models.ReceiptSourceModel) (*Document, error) {
+	vendor, err := g.crypto.DecryptString(source.Vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := g.crypto.DecryptString(source.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := g.lineItems(source)
+	if err != nil {
+		return nil, err
+	}
+
+	total := models.RoundMoney(*source.Price)
+	subtotal, tax := SplitTax(total)
+
+	return &Document{
+		IssuedAt:      g.now().Format(time.DateOnly),
+		TransactionId: source.TransactionId,
+		Vendor:        vendor,
+		Client:        client,
+		Service:       source.Service,
+		Start:         source.Start,
+		End:           source.End,
+		Currency:      *source.Currency,
+		LineItems:     items,
+		Subtotal:      subtotal,
+		Tax:           tax,
+		Total:         total,
+	}, nil
+}
+
+// Quoted bookings list what was agreed in chat, the rest are a single line
+func (g *Generator) lineItems(source *models.ReceiptSourceModel) ([]LineItem, error) {
+	if source.QuoteId == nil {
+		return []LineItem{{Title: source.Service, Quantity: 1, UnitPrice: *source.Price}}, nil
+	}
+
+	quote, err := g.db.FindQuoteById(*source.QuoteId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]LineItem, 0)
+	for _, version := range quote.Versions {
+		if version.Version != quote.Version {
+			continue
+		}
+
+		for _, item := range version.LineItems {
+			items = append(items, LineItem{Title: item.Title, Quantity: item.Quantity, UnitPrice: item.UnitPrice})
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,112 @@
+package receipt
+
+import (
+	"bytes"
+	"errors"
+	"nearbyassist/internal/config"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/encryption"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStorage struct {
+	storage.DummyStorage
+	saved   []byte
+	deleted []string
+}
+
+func (s *memoryStorage) SaveReceipt(file []byte, filename string) (string, error) {
+	s.saved = file
+	return "store/receipts/" + filename, nil
+}
+
+func (s *memoryStorage) DeleteFile(path string) error {
+	s.deleted = append(s.deleted, path)
+	return nil
+}
+
+func newTestGenerator(fake *dbtest.Fake) (*Generator, *memoryStorage, encryption.Encryption) {
+	crypto := encryption.NewAes(&config.Config{EncryptionKey: "0123456789abcdef0123456789abcdef"})
+
+	vendor, _ := crypto.EncryptString("Peña Plumbing")
+	client, _ := crypto.EncryptString("Juan Dela Cruz")
+	fake.ReceiptSource.Vendor = vendor
+	fake.ReceiptSource.Client = client
+
+	store := &memoryStorage{}
+	generator := NewGenerator(fake, store, crypto)
+	generator.now = func() time.Time { return time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC) }
+
+	return generator, store, crypto
+}
+
+func TestGenerateFromQuote(t *testing.T) {
+	price, currency, quoteId := 3000.0, "PHP", 4
+	fake := dbtest.NewFake()
+	fake.InvoiceNumber = 7
+	fake.ReceiptSource = &models.ReceiptSourceModel{
+		TransactionId: 5, VendorId: 2, ClientId: 3, Service: "Tile work",
+		Status: models.TRANSACTION_STATUS_DONE, Price: &price, Currency: &currency, QuoteId: &quoteId,
+	}
+	fake.Quote = &models.QuoteModel{Version: 2, Versions: []models.QuoteVersionModel{
+		{Version: 1, LineItems: []models.QuoteLineItemModel{{Title: "Old", Quantity: 1, UnitPrice: 9000}}},
+		{Version: 2, LineItems: []models.QuoteLineItemModel{{Title: "Tiles", Quantity: 10, UnitPrice: 150}, {Title: "Labor", Quantity: 1, UnitPrice: 1500}}},
+	}}
+
+	generator, store, crypto := newTestGenerator(fake)
+
+	receipt, err := generator.Generate(5, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, receipt.InvoiceNumber)
+	assert.Equal(t, 3000.0, receipt.Total)
+	assert.Equal(t, 2678.57, receipt.Subtotal)
+	assert.Equal(t, 321.43, receipt.Tax)
+	assert.Contains(t, receipt.Path, "store/receipts/")
+	assert.Equal(t, receipt.Path, fake.StoredReceipt[receipt.Id])
+
+	// Stored encrypted, like the other documents
+	assert.False(t, bytes.HasPrefix(store.saved, []byte("%PDF")))
+	decrypted, err := crypto.DecryptFile(store.saved)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(decrypted, []byte("%PDF")))
+
+	items, err := generator.lineItems(fake.ReceiptSource)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestGenerateOngoingTransaction(t *testing.T) {
+	price, currency := 3000.0, "PHP"
+	fake := dbtest.NewFake()
+	fake.ReceiptSource = &models.ReceiptSourceModel{Status: models.TRANSACTION_STATUS_ONGOING, Price: &price, Currency: &currency}
+
+	generator, _, _ := newTestGenerator(fake)
+
+	_, err := generator.Generate(5, nil)
+
+	assert.ErrorIs(t, err, ErrNotCompleted)
+}
+
+func TestGenerateRemovesFileWhenNotStored(t *testing.T) {
+	price, currency := 3000.0, "PHP"
+	fake := dbtest.NewFake()
+	fake.StoreErr = errors.New("receipt is already stored")
+	fake.ReceiptSource = &models.ReceiptSourceModel{
+		TransactionId: 5, VendorId: 2, ClientId: 3, Service: "Tile work",
+		Status: models.TRANSACTION_STATUS_DONE, Price: &price, Currency: &currency,
+	}
+
+	generator, store, _ := newTestGenerator(fake)
+
+	_, err := generator.Generate(5, nil)
+
+	assert.EqualError(t, err, "receipt is already stored")
+	assert.Len(t, store.deleted, 1)
+	assert.Contains(t, store.deleted[0], "store/receipts/")
+}
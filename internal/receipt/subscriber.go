@@ -0,0 +1,36 @@
+package receipt
+
+import (
+	"encoding/json"
+	"errors"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/events"
+	"nearbyassist/internal/models"
+)
+
+// Issues the first receipt once a transaction is completed. Transactions that
+// already have one are skipped so a redispatched event is harmless
+func Subscribe(bus *events.Bus, db db.Database, generator *Generator) {
+	bus.Subscribe(models.EVENT_TRANSACTION_COMPLETED, "receipts", func(event models.OutboxEventModel) error {
+		payload := models.TransactionEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		receipts, err := db.FindReceipts(payload.TransactionId)
+		if err != nil {
+			return err
+		}
+
+		if len(receipts) > 0 {
+			return nil
+		}
+
+		// Bookings made before prices were recorded have nothing to show
+		if _, err := generator.Generate(payload.TransactionId, nil); err != nil && !errors.Is(err, ErrNoPrice) {
+			return err
+		}
+
+		return nil
+	})
+}
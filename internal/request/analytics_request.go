@@ -0,0 +1,13 @@
+package request
+
+import "nearbyassist/internal/models"
+
+// Dates are inclusive and formatted as YYYY-MM-DD
+type AnalyticsFilter struct {
+	From   string                 `query:"from"`
+	To     string                 `query:"to"`
+	Bucket models.AnalyticsBucket `query:"bucket"`
+	Split  models.AnalyticsSplit  `query:"split"`
+	// Narrows a split down to one tag id or area
+	Key string `query:"key"`
+}
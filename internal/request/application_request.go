@@ -7,3 +7,9 @@ type NewApplication struct {
 	Job         string `json:"job" db:"job" validate:"required"`
 	models.GeoSpatialModel
 }
+
+type ApplicationReview struct {
+	Id         int    `json:"id" db:"id"`
+	Reason     string `json:"reason" db:"reason"`
+	ReviewedBy int    `json:"reviewedBy" db:"reviewedBy"`
+}
@@ -1,12 +1,54 @@
 package request
 
+import "nearbyassist/internal/models"
+
 type NewComplaint struct {
-	Code    int    `json:"code" db:"code" validate:"required"`
-	Title   string `json:"title" db:"title" validate:"required"`
-	Content string `json:"content" db:"content" validate:"required"`
+	ComplainantId int    `json:"complainantId" db:"complainantId" validate:"required"`
+	VendorId      int    `json:"vendorId" db:"vendorId" validate:"required"`
+	TransactionId int    `json:"transactionId" db:"transactionId" validate:"required"`
+	Title         string `json:"title" db:"title" validate:"required"`
+	Content       string `json:"content" db:"content" validate:"required"`
 }
 
 type SystemComplaint struct {
-	Title  string `json:"title" db:"title" validate:"required"`
-	Detail string `json:"detail" db:"detail" validate:"required"`
+	ReporterId int                      `json:"reporterId" db:"reporterId" validate:"required"`
+	Title      string                   `json:"title" db:"title" validate:"required"`
+	Detail     string                   `json:"detail" db:"detail" validate:"required"`
+	Priority   models.ComplaintPriority `json:"priority" db:"priority"`
+}
+
+type SystemComplaintFilter struct {
+	Status     models.SystemComplaintStatus `query:"status"`
+	Priority   models.ComplaintPriority     `query:"priority"`
+	AssignedTo int                          `query:"assignedTo"`
+}
+
+type TriageSystemComplaint struct {
+	Id         int                          `json:"id" db:"id"`
+	Status     models.SystemComplaintStatus `json:"status" db:"status" validate:"required"`
+	Priority   models.ComplaintPriority     `json:"priority" db:"priority" validate:"required"`
+	AssignedTo *int                         `json:"assignedTo" db:"assignedTo"`
+}
+
+type ComplaintReply struct {
+	ParentId *int   `json:"parentId" db:"parentId"`
+	Content  string `json:"content" db:"content" validate:"required"`
+}
+
+type AssignComplaint struct {
+	StaffId int `json:"staffId" db:"staffId" validate:"required"`
+}
+
+type ComplaintComment struct {
+	Comment string `json:"comment" db:"comment" validate:"required"`
+}
+
+type ResolveComplaint struct {
+	Id              int                          `json:"id" db:"id"`
+	VendorId        int                          `json:"vendorId" db:"vendorId"`
+	Status          models.VendorComplaintStatus `json:"status" db:"status"`
+	Resolution      models.ComplaintResolution   `json:"resolution" db:"resolution"`
+	Note            string                       `json:"note" db:"note" validate:"required"`
+	RestrictionDays int                          `json:"restrictionDays" db:"restrictionDays" validate:"min=0"`
+	AdminId         int                          `json:"adminId" db:"adminId"`
 }
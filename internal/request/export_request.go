@@ -0,0 +1,13 @@
+package request
+
+import "nearbyassist/internal/models"
+
+// Only the filters of the exported list are used, they match the query
+// parameters of its list endpoint
+type ExportFilter struct {
+	List       models.ExportList
+	Format     models.ExportFormat      `query:"format"`
+	Status     string                   `query:"status"`
+	Priority   models.ComplaintPriority `query:"priority"`
+	AssignedTo int                      `query:"assignedTo"`
+}
@@ -0,0 +1,9 @@
+package request
+
+import "nearbyassist/internal/models"
+
+type DeviceToken struct {
+	UserId   int                   `json:"userId" db:"userId"`
+	Token    string                `json:"token" db:"token" validate:"required"`
+	Platform models.DevicePlatform `json:"platform" db:"platform" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type NewPayment struct {
+	// Token from the payment provider's client side SDK
+	Method string `json:"method" validate:"required"`
+}
@@ -0,0 +1,53 @@
+package request
+
+import "nearbyassist/internal/models"
+
+// Frame read from the chat websocket. Plain messages only set the receiver
+// and content, quote frames carry the quote or the quote being answered
+type ChatFrame struct {
+	Type     models.MessageType `json:"type"`
+	Sender   int                `json:"sender"`
+	Receiver int                `json:"receiver"`
+	Content  string             `json:"content"`
+	Quote    *NewQuote          `json:"quote"`
+	QuoteId  int                `json:"quoteId"`
+	Version  int                `json:"version"`
+}
+
+// Sending a quote with an existing QuoteId revises it
+type NewQuote struct {
+	QuoteId    int             `json:"quoteId"`
+	VendorId   int             `json:"-"`
+	ClientId   int             `json:"-"`
+	ServiceId  int             `json:"serviceId" validate:"required"`
+	Start      string          `json:"start" validate:"required"`
+	End        string          `json:"end" validate:"required"`
+	ValidUntil string          `json:"validUntil" validate:"required"`
+	Currency   string          `json:"currency" validate:"required,len=3,alpha"`
+	Note       string          `json:"note" validate:"max=255"`
+	LineItems  []QuoteLineItem `json:"lineItems" validate:"required,min=1,max=50,dive"`
+}
+
+type QuoteLineItem struct {
+	Title     string  `json:"title" validate:"required,max=120"`
+	Quantity  float64 `json:"quantity" validate:"gt=0"`
+	UnitPrice float64 `json:"unitPrice" validate:"gte=0"`
+}
+
+// The total is always computed here, never taken from the client
+func (q *NewQuote) Total() float64 {
+	total := 0.0
+	for _, item := range q.LineItems {
+		total += item.Quantity * item.UnitPrice
+	}
+
+	return total
+}
+
+// Version must match the latest revision so a client never accepts a price
+// they have not seen
+type QuoteResponse struct {
+	QuoteId  int `validate:"required"`
+	Version  int `validate:"required"`
+	ClientId int `validate:"required"`
+}
@@ -3,18 +3,37 @@ package request
 import "nearbyassist/internal/models"
 
 type NewService struct {
-	VendorId    int      `json:"vendorId" db:"vendorId" validate:"required"`
-	Description string   `json:"description" db:"description" validate:"required"`
-	Rate        string   `json:"rate" db:"rate" validate:"required"`
-	Tags        []string `json:"tags" db:"tags" validate:"required"`
+	VendorId            int                        `json:"vendorId" db:"vendorId" validate:"required"`
+	Title               string                     `json:"title" db:"title" validate:"required,max=120"`
+	Description         string                     `json:"description" db:"description" validate:"required"`
+	Rate                float64                    `json:"rate" db:"rate" validate:"required,gt=0"`
+	PricingUnit         models.PricingUnit         `json:"pricingUnit" db:"pricingUnit" validate:"required,oneof=hour visit fixed"`
+	MinimumCharge       float64                    `json:"minimumCharge" db:"minimumCharge" validate:"gte=0"`
+	Currency            string                     `json:"currency" db:"currency" validate:"required,len=3,alpha"`
+	AddOns              []ServiceAddOn             `json:"addOns" db:"-" validate:"max=20,dive"`
+	CancellationPolicy  models.CancellationPolicy  `json:"cancellationPolicy" db:"cancellationPolicy" validate:"omitempty,oneof=flexible moderate strict custom"`
+	CancellationWindows models.CancellationWindows `json:"cancellationWindows" db:"cancellationWindows" validate:"max=5,dive"`
+	Tags                []string                   `json:"tags" db:"tags" validate:"required"`
 	models.GeoSpatialModel
 }
 
 type UpdateService struct {
-	Id          int      `json:"id" db:"id"`
-	VendorId    int      `json:"vendorId" db:"vendorId" validate:"required"`
-	Description string   `json:"description" db:"description" validate:"required"`
-	Rate        string   `json:"rate" db:"rate" validate:"required"`
-	Tags        []string `json:"tags" db:"tags" validate:"required"`
+	Id                  int                        `json:"id" db:"id"`
+	VendorId            int                        `json:"vendorId" db:"vendorId" validate:"required"`
+	Title               string                     `json:"title" db:"title" validate:"required,max=120"`
+	Description         string                     `json:"description" db:"description" validate:"required"`
+	Rate                float64                    `json:"rate" db:"rate" validate:"required,gt=0"`
+	PricingUnit         models.PricingUnit         `json:"pricingUnit" db:"pricingUnit" validate:"required,oneof=hour visit fixed"`
+	MinimumCharge       float64                    `json:"minimumCharge" db:"minimumCharge" validate:"gte=0"`
+	Currency            string                     `json:"currency" db:"currency" validate:"required,len=3,alpha"`
+	AddOns              []ServiceAddOn             `json:"addOns" db:"-" validate:"max=20,dive"`
+	CancellationPolicy  models.CancellationPolicy  `json:"cancellationPolicy" db:"cancellationPolicy" validate:"omitempty,oneof=flexible moderate strict custom"`
+	CancellationWindows models.CancellationWindows `json:"cancellationWindows" db:"cancellationWindows" validate:"max=5,dive"`
+	Tags                []string                   `json:"tags" db:"tags" validate:"required"`
 	models.GeoSpatialModel
 }
+
+type ServiceAddOn struct {
+	Title string  `json:"title" validate:"required,max=120"`
+	Price float64 `json:"price" validate:"gte=0"`
+}
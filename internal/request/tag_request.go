@@ -0,0 +1,38 @@
+package request
+
+type NewTag struct {
+	Title      string `json:"title" db:"title" validate:"required,max=255"`
+	CategoryId *int   `json:"categoryId" db:"categoryId"`
+}
+
+type UpdateTag struct {
+	Id         int    `json:"id" db:"id"`
+	Title      string `json:"title" db:"title" validate:"required,max=255"`
+	CategoryId *int   `json:"categoryId" db:"categoryId"`
+}
+
+type NewTagCategory struct {
+	Title    string `json:"title" db:"title" validate:"required,max=255"`
+	ParentId *int   `json:"parentId" db:"parentId"`
+}
+
+type UpdateTagCategory struct {
+	Id       int    `json:"id" db:"id"`
+	Title    string `json:"title" db:"title" validate:"required,max=255"`
+	ParentId *int   `json:"parentId" db:"parentId"`
+}
+
+type NewTagSynonym struct {
+	TagId int    `json:"tagId" db:"tagId"`
+	Title string `json:"title" db:"title" validate:"required,max=255"`
+}
+
+type MergeTag struct {
+	SourceId int `json:"sourceId"`
+	TargetId int `json:"targetId" validate:"required"`
+}
+
+type RetireTag struct {
+	Id            int  `json:"id"`
+	ReplacementId *int `json:"replacementId"`
+}
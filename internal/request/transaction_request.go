@@ -1,9 +1,24 @@
 package request
 
+import "nearbyassist/internal/models"
+
 type NewTransaction struct {
 	VendorId  int    `json:"vendorId" db:"vendorId" validate:"required"`
 	ClientId  int    `json:"clientId" db:"clientId" validate:"required"`
 	ServiceId int    `json:"serviceId" db:"serviceId" validate:"required"`
 	Start     string `json:"start" db:"start" validate:"required"`
 	End       string `json:"end" db:"end" validate:"required"`
+	// Taken from the service when the booking is made
+	Price               float64                    `json:"-" db:"price"`
+	Currency            string                     `json:"-" db:"currency"`
+	CancellationPolicy  models.CancellationPolicy  `json:"-" db:"cancellationPolicy"`
+	CancellationWindows models.CancellationWindows `json:"-" db:"cancellationWindows"`
+}
+
+type ReviseReceipt struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type CancelTransaction struct {
+	Reason string `json:"reason" validate:"max=255"`
 }
@@ -0,0 +1,31 @@
+package request
+
+import "nearbyassist/internal/models"
+
+type RestrictVendor struct {
+	VendorId     int    `json:"vendorId" db:"vendorId"`
+	Reason       string `json:"reason" db:"reason" validate:"required"`
+	DurationDays int    `json:"durationDays" db:"durationDays" validate:"min=0"`
+	IssuedBy     int    `json:"issuedBy" db:"issuedBy"`
+	ComplaintId  *int   `json:"complaintId" db:"complaintId"`
+}
+
+type NewAppeal struct {
+	RestrictionId int    `json:"restrictionId" db:"restrictionId" validate:"required"`
+	VendorId      int    `json:"vendorId" db:"vendorId"`
+	Content       string `json:"content" db:"content" validate:"required"`
+}
+
+type ReviewAppeal struct {
+	Id         int                 `json:"id" db:"id"`
+	Status     models.AppealStatus `json:"status" db:"status"`
+	Note       string              `json:"note" db:"note" validate:"required"`
+	ReviewedBy int                 `json:"reviewedBy" db:"reviewedBy"`
+}
+
+// Dates are inclusive and formatted as YYYY-MM-DD
+type VendorStatsFilter struct {
+	From   string                 `query:"from"`
+	To     string                 `query:"to"`
+	Bucket models.AnalyticsBucket `query:"bucket"`
+}
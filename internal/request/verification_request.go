@@ -0,0 +1,10 @@
+package request
+
+import "nearbyassist/internal/models"
+
+type VerificationDecision struct {
+	Id         int                       `json:"id" db:"id"`
+	Status     models.VerificationStatus `json:"status" db:"status"`
+	Reason     string                    `json:"reason" db:"reason"`
+	ReviewedBy int                       `json:"reviewedBy" db:"reviewedBy"`
+}
@@ -0,0 +1,16 @@
+package request
+
+import "nearbyassist/internal/models"
+
+type NewWebhook struct {
+	Url    string             `json:"url" validate:"required,url"`
+	Secret string             `json:"secret" validate:"omitempty,min=16"`
+	Events []models.EventType `json:"events" validate:"required,min=1"`
+}
+
+type UpdateWebhook struct {
+	Url    string             `json:"url" validate:"required,url"`
+	Secret string             `json:"secret" validate:"omitempty,min=16"`
+	Events []models.EventType `json:"events" validate:"required,min=1"`
+	Active bool               `json:"active"`
+}
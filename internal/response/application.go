@@ -5,6 +5,15 @@ import "nearbyassist/internal/models"
 type Application struct {
 	Id          int                      `json:"id" db:"id"`
 	ApplicantId int                      `json:"applicantId" db:"applicantId"`
+	Job         string                   `json:"job" db:"job"`
 	Status      models.ApplicationStatus `json:"status" db:"status"`
 	CreatedAt   string                   `json:"createdAt" db:"createdAt"`
 }
+
+type Applicant struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	ImageUrl string `json:"imageUrl"`
+	Verified bool   `json:"verified"`
+}
@@ -0,0 +1,14 @@
+package response
+
+type MapCluster struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Count     int     `json:"count"`
+}
+
+type MapService struct {
+	Id        int     `json:"id"`
+	Vendor    string  `json:"vendor"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
@@ -1,10 +1,19 @@
 package response
 
 type SearchResult struct {
-	Id             int     `json:"id"`
-	Suggestability float32 `json:"suggestability"`
-	Rank           int     `json:"rank"`
-	Vendor         string  `json:"vendor"`
-	Latitude       float64 `json:"latitude"`
-	Longitude      float64 `json:"longitude"`
+	Id             int      `json:"id"`
+	Suggestability float32  `json:"suggestability"`
+	Rank           int      `json:"rank"`
+	Vendor         string   `json:"vendor"`
+	Title          string   `json:"title"`
+	Rate           float64  `json:"rate"`
+	PricingUnit    string   `json:"pricingUnit"`
+	Currency       string   `json:"currency"`
+	Rating         float64  `json:"rating"`
+	Verified       bool     `json:"verified"`
+	Latitude       float64  `json:"latitude"`
+	Longitude      float64  `json:"longitude"`
+	Distance       *float64 `json:"distance"`
+	Duration       *float64 `json:"duration"`
+	Approximate    bool     `json:"approximate"`
 }
@@ -3,10 +3,17 @@ package response
 import "nearbyassist/internal/models"
 
 type ServiceDetails struct {
-	ServiceId   int      `json:"serviceId" db:"serviceId"`
-	Description string   `json:"description" db:"description"`
-	Tags        []string `json:"tags" db:"tags"`
-	Rate        string   `json:"rate" db:"rate"`
+	ServiceId           int                        `json:"serviceId" db:"serviceId"`
+	Title               string                     `json:"title" db:"title"`
+	Description         string                     `json:"description" db:"description"`
+	Tags                []string                   `json:"tags" db:"tags"`
+	Rate                float64                    `json:"rate" db:"rate"`
+	PricingUnit         models.PricingUnit         `json:"pricingUnit" db:"pricingUnit"`
+	MinimumCharge       float64                    `json:"minimumCharge" db:"minimumCharge"`
+	Currency            string                     `json:"currency" db:"currency"`
+	AddOns              []models.ServiceAddOnModel `json:"addOns" db:"-"`
+	CancellationPolicy  models.CancellationPolicy  `json:"cancellationPolicy" db:"cancellationPolicy"`
+	CancellationWindows models.CancellationWindows `json:"cancellationWindows" db:"cancellationWindows"`
 	models.GeoSpatialModel
 }
 
@@ -16,6 +23,7 @@ type ServiceVendorDetails struct {
 	ImageUrl string `json:"imageUrl" db:"imageUrl"`
 	Rating   string `json:"rating" db:"rating"`
 	Job      string `json:"job" db:"job"`
+	Verified bool   `json:"verified" db:"verified"`
 }
 
 type ServiceImages struct {
@@ -1,6 +1,18 @@
-package response 
+package response
+
+import "nearbyassist/internal/models"
 
 type SystemComplaint struct {
-    Id    int    `json:"id"`
-    Title string `json:"title"`
+	Id         int                          `json:"id" db:"id"`
+	ReporterId *int                         `json:"reporterId" db:"reporterId"`
+	Title      string                       `json:"title" db:"title"`
+	Status     models.SystemComplaintStatus `json:"status" db:"status"`
+	Priority   models.ComplaintPriority     `json:"priority" db:"priority"`
+	AssignedTo *int                         `json:"assignedTo" db:"assignedTo"`
+	CreatedAt  string                       `json:"createdAt" db:"createdAt"`
+}
+
+type ComplaintStatusCount struct {
+	Status models.SystemComplaintStatus `json:"status" db:"status"`
+	Count  int                          `json:"count" db:"count"`
 }
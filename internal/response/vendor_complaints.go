@@ -0,0 +1,14 @@
+package response
+
+import "nearbyassist/internal/models"
+
+type VendorComplaint struct {
+	Id            int                          `json:"id" db:"id"`
+	ComplainantId *int                         `json:"complainantId" db:"complainantId"`
+	VendorId      int                          `json:"vendorId" db:"vendorId"`
+	TransactionId *int                         `json:"transactionId" db:"transactionId"`
+	Title         string                       `json:"title" db:"title"`
+	Status        models.VendorComplaintStatus `json:"status" db:"status"`
+	AssignedTo    *int                         `json:"assignedTo" db:"assignedTo"`
+	CreatedAt     string                       `json:"createdAt" db:"createdAt"`
+}
@@ -1,7 +1,18 @@
 package response
 
+import "nearbyassist/internal/models"
+
 type AllVerification struct {
-	Id        int    `json:"id" db:"id"`
-	User      int    `json:"user" db:"user"`
-	CreatedAt string `json:"createdAt" db:"createdAt"`
+	Id        int                       `json:"id" db:"id"`
+	User      int                       `json:"user" db:"user"`
+	Status    models.VerificationStatus `json:"status" db:"status"`
+	CreatedAt string                    `json:"createdAt" db:"createdAt"`
+}
+
+type VerificationStatus struct {
+	Id         int                       `json:"id" db:"id"`
+	Status     models.VerificationStatus `json:"status" db:"status"`
+	Reason     string                    `json:"reason" db:"reason"`
+	ReviewedAt *string                   `json:"reviewedAt" db:"reviewedAt"`
+	CreatedAt  string                    `json:"createdAt" db:"createdAt"`
 }
@@ -3,13 +3,31 @@ package routes
 import (
 	"nearbyassist/internal/handlers"
 	"nearbyassist/internal/middleware"
+	"nearbyassist/internal/models"
 	"nearbyassist/internal/server"
 
 	"github.com/labstack/echo/v4"
 )
 
 func handleAdminRoutes(r *echo.Group, s *server.Server) {
-	r.Use(middleware.CheckRole(s.Auth))
+	// Staff can export too, with the encrypted columns redacted. The group is
+	// created before the admin check is added so it keeps its own check
+	exports := r.Group("/exports", middleware.CheckRole(s.Auth, models.ADMIN_ROLE_ADMIN, models.ADMIN_ROLE_STAFF))
+	{
+		handler := handlers.NewExportHandler(s)
+		adminOnly := middleware.CheckRole(s.Auth, models.ADMIN_ROLE_ADMIN)
+
+		exports.GET("/audit", handler.HandleGetExportAudits, adminOnly)
+		exports.GET("/users", handler.HandleExportUsers)
+		exports.GET("/vendors", handler.HandleExportVendors)
+		exports.GET("/applications", handler.HandleExportApplications)
+		exports.GET("/transactions", handler.HandleExportTransactions)
+		exports.GET("/complaints/system", handler.HandleExportSystemComplaints)
+		exports.GET("/complaints/vendor", handler.HandleExportVendorComplaints)
+		exports.GET("/verifications", handler.HandleExportVerifications)
+	}
+
+	r.Use(middleware.CheckRole(s.Auth, models.ADMIN_ROLE_ADMIN))
 
 	management := r.Group("/management")
 	{
@@ -34,6 +52,15 @@ func handleAdminRoutes(r *echo.Group, s *server.Server) {
 		vendor.GET("/count", handler.HandleCount)
 		vendor.PUT("/restrict/:vendorId", handler.HandleRestrict)
 		vendor.PUT("/unrestrict/:vendorId", handler.HandleUnrestrict)
+		vendor.GET("/restrictions/:vendorId", handler.HandleGetRestrictions)
+
+		appeals := vendor.Group("/appeals")
+		{
+			appeals.GET("", handler.HandleGetAppeals)
+			appeals.GET("/:appealId", handler.HandleGetAppeal)
+			appeals.PUT("/accept/:appealId", handler.HandleAcceptAppeal)
+			appeals.PUT("/reject/:appealId", handler.HandleRejectAppeal)
+		}
 	}
 
 	application := r.Group("/application")
@@ -42,6 +69,7 @@ func handleAdminRoutes(r *echo.Group, s *server.Server) {
 
 		application.GET("", handler.HandleGetApplications)
 		application.GET("/count", handler.HandleCount)
+		application.GET("/:applicationId", handler.HandleGetApplication)
 		application.PUT("/approve/:applicationId", handler.HandleApprove)
 		application.PUT("/reject/:applicationId", handler.HandleReject)
 	}
@@ -61,6 +89,14 @@ func handleAdminRoutes(r *echo.Group, s *server.Server) {
 		transaction.GET("/:transactionId", handler.HandleGetTransaction)
 	}
 
+	if s.Payment != nil {
+		payments := r.Group("/payments")
+		handler := handlers.NewPaymentHandler(s)
+
+		payments.GET("/balances", handler.HandleGetBalances)
+		payments.GET("/reconciliation", handler.HandleReconcile)
+	}
+
 	complaint := r.Group("/complaints")
 	{
 		handler := handlers.NewComplaintHandler(s)
@@ -70,6 +106,19 @@ func handleAdminRoutes(r *echo.Group, s *server.Server) {
 			system.GET("", handler.HandleGetSystemComplaint)
 			system.GET("/:complaintId", handler.HandleGetSystemComplaintById)
 			system.GET("/count", handler.HandleSystemComplaintCount)
+			system.PUT("/triage/:complaintId", handler.HandleTriageSystemComplaint)
+			system.POST("/reply/:complaintId", handler.HandleStaffSystemComplaintReply)
+		}
+
+		vendor := complaint.Group("/vendor")
+		{
+			vendor.GET("", handler.HandleGetVendorComplaints)
+			vendor.GET("/count", handler.HandleVendorComplaintCount)
+			vendor.GET("/:complaintId", handler.HandleGetVendorComplaintById)
+			vendor.PUT("/assign/:complaintId", handler.HandleAssignVendorComplaint)
+			vendor.POST("/comment/:complaintId", handler.HandleCommentVendorComplaint)
+			vendor.PUT("/resolve/:complaintId", handler.HandleResolveVendorComplaint)
+			vendor.PUT("/dismiss/:complaintId", handler.HandleDismissVendorComplaint)
 		}
 	}
 
@@ -81,6 +130,52 @@ func handleAdminRoutes(r *echo.Group, s *server.Server) {
 		{
 			identity.GET("", handler.HandleGetAllIdentityVerification)
 			identity.GET("/:verificationId", handler.HandleGetIdentityVerification)
+			identity.PUT("/approve/:verificationId", handler.HandleApproveIdentityVerification)
+			identity.PUT("/reject/:verificationId", handler.HandleRejectIdentityVerification)
+			identity.PUT("/resubmit/:verificationId", handler.HandleRequestIdentityResubmission)
+		}
+	}
+
+	tags := r.Group("/tags")
+	{
+		handler := handlers.NewTagHandler(s)
+
+		tags.GET("", handler.HandleGetTaxonomy)
+		tags.POST("", handler.HandleNewTag)
+		tags.PUT("/:tagId", handler.HandleUpdateTag)
+		tags.PUT("/merge/:tagId", handler.HandleMergeTag)
+		tags.PUT("/retire/:tagId", handler.HandleRetireTag)
+		tags.POST("/synonyms/:tagId", handler.HandleNewSynonym)
+		tags.DELETE("/synonyms/:synonymId", handler.HandleDeleteSynonym)
+
+		categories := tags.Group("/categories")
+		{
+			categories.GET("", handler.HandleGetCategories)
+			categories.POST("", handler.HandleNewCategory)
+			categories.PUT("/:categoryId", handler.HandleUpdateCategory)
+			categories.DELETE("/:categoryId", handler.HandleDeleteCategory)
 		}
 	}
+
+	analytics := r.Group("/analytics")
+	{
+		handler := handlers.NewAnalyticsHandler(s)
+
+		analytics.GET("", handler.HandleGetAnalytics)
+		analytics.POST("/refresh", handler.HandleRefreshAnalytics)
+	}
+
+	webhooks := r.Group("/webhooks")
+	{
+		handler := handlers.NewWebhookHandler(s)
+
+		webhooks.GET("", handler.HandleGetWebhooks)
+		webhooks.POST("", handler.HandleNewWebhook)
+		webhooks.GET("/:webhookId", handler.HandleGetWebhook)
+		webhooks.PUT("/:webhookId", handler.HandleUpdateWebhook)
+		webhooks.DELETE("/:webhookId", handler.HandleDeleteWebhook)
+		webhooks.GET("/deliveries/:webhookId", handler.HandleGetDeliveries)
+		webhooks.GET("/delivery/:deliveryId", handler.HandleGetDelivery)
+		webhooks.PUT("/redeliver/:deliveryId", handler.HandleRedeliver)
+	}
 }
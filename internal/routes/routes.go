@@ -47,6 +47,10 @@ func RegisterRoutes(s *server.Server) {
 			{
 				handler := handlers.NewVendorHandler(s)
 				vendor.GET("", handler.HandleBaseRoute)
+				vendor.GET("/restriction", handler.HandleGetOwnRestriction)
+				vendor.GET("/stats", handler.HandleGetOwnStats)
+				vendor.GET("/appeal", handler.HandleGetOwnAppeals)
+				vendor.POST("/appeal", handler.HandleNewAppeal)
 				vendor.GET("/:vendorId", handler.HandleGetVendor)
 			}
 
@@ -54,6 +58,7 @@ func RegisterRoutes(s *server.Server) {
 			{
 				handler := handlers.NewTagHandler(s)
 				tags.GET("", handler.HandleGetTags)
+				tags.GET("/autocomplete", handler.HandleAutocomplete)
 			}
 
 			service := public.Group("/services")
@@ -62,6 +67,7 @@ func RegisterRoutes(s *server.Server) {
 				service.GET("", handler.HandleGetServices)
 				service.POST("", handler.HandleRegisterService)
 				service.GET("/search", handler.HandleSearchService)
+				service.GET("/map", handler.HandleServiceMap)
 				service.GET("/:serviceId", handler.HandleGetDetails)
 				service.PUT("/:serviceId", handler.HandleUpdateService)
 				service.DELETE("/:serviceId", handler.HandleDeleteService)
@@ -73,7 +79,12 @@ func RegisterRoutes(s *server.Server) {
 			{
 				handler := handlers.NewComplaintHandler(s)
 				complaint.POST("/system", handler.HandleSystemComplaint)
+				complaint.GET("/system", handler.HandleGetOwnSystemComplaints)
+				complaint.GET("/system/:complaintId", handler.HandleGetOwnSystemComplaint)
+				complaint.POST("/system/reply/:complaintId", handler.HandleReporterSystemComplaintReply)
 				complaint.POST("/vendor", handler.HandleVendorComplaint)
+				complaint.GET("/vendor", handler.HandleGetOwnVendorComplaints)
+				complaint.GET("/vendor/:complaintId", handler.HandleGetOwnVendorComplaint)
 			}
 
 			transaction := public.Group("/transactions")
@@ -82,6 +93,12 @@ func RegisterRoutes(s *server.Server) {
 				transaction.GET("", handler.HandleGetTransactions)
 				transaction.POST("", handler.HandleNewTransaction)
 				transaction.POST("/complete/:transactionId", handler.HandleCompleteTransaction)
+				transaction.POST("/cancel/:transactionId", handler.HandleCancelTransaction)
+				transaction.GET("/cancel/:transactionId", handler.HandleGetCancellation)
+				transaction.GET("/reliability/:userId", handler.HandleGetReliability)
+				transaction.GET("/receipts/:transactionId", handler.HandleGetReceipts)
+				transaction.GET("/receipts/:transactionId/download", handler.HandleDownloadReceipt)
+				transaction.POST("/receipts/:transactionId", handler.HandleReviseReceipt)
 				// TODO: maybepublic.factor this to be basev1.ute that takes in the following
 				// userId = can be a client or vendor ID
 				// Filter = view transactions as client or vendor
@@ -90,10 +107,19 @@ func RegisterRoutes(s *server.Server) {
 				transaction.GET("/history", handler.HandleHistory)
 			}
 
+			// Payments are disabled when no provider is configured
+			if s.Payment != nil {
+				payment := public.Group("/payments")
+				handler := handlers.NewPaymentHandler(s)
+				payment.POST("/:transactionId", handler.HandlePay)
+				payment.GET("/:transactionId", handler.HandleGetPayment)
+			}
+
 			application := public.Group("/application")
 			{
 				handler := handlers.NewApplicationHandler(s)
 				application.POST("", handler.HandleNewApplication)
+				application.GET("", handler.HandleGetOwnApplications)
 			}
 
 			review := public.Group("/reviews")
@@ -120,12 +146,24 @@ func RegisterRoutes(s *server.Server) {
 				chat.GET("/messages/:otherUserId", handler.HandleGetMessages)
 				chat.GET("/ws", handler.HandleWebsocket)
 				chat.GET("/conversations", handler.HandleGetConversations)
+				chat.GET("/quote/:quoteId", handler.HandleGetQuote)
+			}
+
+			notification := public.Group("/notifications")
+			{
+				handler := handlers.NewNotificationHandler(s)
+				notification.GET("", handler.HandleGetNotifications)
+				notification.PUT("/read", handler.HandleMarkAllRead)
+				notification.PUT("/read/:notificationId", handler.HandleMarkRead)
+				notification.POST("/devices", handler.HandleRegisterDevice)
+				notification.DELETE("/devices", handler.HandleUnregisterDevice)
 			}
 
 			verification := public.Group("/verification")
 			{
 				handler := handlers.NewVerificationHandler(s)
 				verification.POST("/identity", handler.HandleVerifyIdentity)
+				verification.GET("/identity", handler.HandleGetIdentityVerificationStatus)
 			}
 		}
 	}
@@ -141,6 +179,14 @@ func RegisterRoutes(s *server.Server) {
 		ws.GET("/ws", handler.HandleWebsocket)
 	}
 
+	// Provider callbacks carry a signature instead of an access token
+	if s.Payment != nil {
+		webhooks := s.Echo.Group("/webhooks")
+		handler := handlers.NewPaymentHandler(s)
+
+		webhooks.POST("/payments", handler.HandlePaymentWebhook)
+	}
+
 	file := s.Echo.Group("/resource")
 	{
 		handler := handlers.NewFileServerHandler(s)
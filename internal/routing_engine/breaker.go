@@ -0,0 +1,123 @@
+package routing_engine
+
+import (
+	"errors"
+	"log"
+	"nearbyassist/internal/models"
+	"sync"
+	"time"
+)
+
+const (
+	BREAKER_FAILURE_THRESHOLD = 3
+	BREAKER_COOLDOWN          = time.Second * 30
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// Breaker sends requests to the primary engine and switches to the fallback
+// after consecutive outages. Once the cooldown passes a single request is
+// let through to check whether the primary engine has recovered.
+type Breaker struct {
+	primary   Engine
+	fallback  Engine
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func NewBreaker(primary, fallback Engine, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		primary:   primary,
+		fallback:  fallback,
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     breakerClosed,
+		now:       time.Now,
+	}
+}
+
+func (b *Breaker) FindRoute(origin, destination *models.Location, options RouteOptions) (*RouteResult, error) {
+	if !b.allow() {
+		return b.fallback.FindRoute(origin, destination, options)
+	}
+
+	result, err := b.primary.FindRoute(origin, destination, options)
+
+	// Only outages count as failures, a missing route is a valid answer
+	if err != nil && errors.Is(err, ErrUnavailable) {
+		b.recordFailure()
+		log.Printf("routing engine unavailable, using fallback: %s\n", err.Error())
+		return b.fallback.FindRoute(origin, destination, options)
+	}
+
+	b.recordSuccess()
+	return result, err
+}
+
+func (b *Breaker) FindTable(origin *models.Location, destinations []models.Location, profile Profile) (*TableResult, error) {
+	if !b.allow() {
+		return b.fallback.FindTable(origin, destinations, profile)
+	}
+
+	result, err := b.primary.FindTable(origin, destinations, profile)
+
+	if err != nil && errors.Is(err, ErrUnavailable) {
+		b.recordFailure()
+		log.Printf("routing engine unavailable, using fallback: %s\n", err.Error())
+		return b.fallback.FindTable(origin, destinations, profile)
+	}
+
+	b.recordSuccess()
+	return result, err
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// A trial request is already in flight
+		return false
+	}
+
+	return true
+}
+
+func (b *Breaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *Breaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+}
@@ -0,0 +1,62 @@
+package routing_engine
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakerOpensAfterOutages(t *testing.T) {
+	primary := &countingEngine{err: ErrUnavailable}
+	fallback := &countingEngine{result: &RouteResult{Approximate: true}}
+	breaker := NewBreaker(primary, fallback, 2, time.Minute)
+
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	origin := models.NewLocationWithData(7.1, 125.6)
+	destination := models.NewLocationWithData(7.2, 125.7)
+
+	for i := 0; i < 4; i++ {
+		result, err := breaker.FindRoute(origin, destination, RouteOptions{})
+		assert.NoError(t, err)
+		assert.True(t, result.Approximate)
+	}
+
+	// Two failures open the breaker, the rest go straight to the fallback
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, 4, fallback.calls)
+
+	// After the cooldown a trial request closes the breaker again
+	primary.err = nil
+	primary.result = &RouteResult{}
+	now = now.Add(time.Minute * 2)
+
+	result, err := breaker.FindRoute(origin, destination, RouteOptions{})
+	assert.NoError(t, err)
+	assert.False(t, result.Approximate)
+	assert.Equal(t, 3, primary.calls)
+
+	breaker.FindRoute(origin, destination, RouteOptions{})
+	assert.Equal(t, 4, primary.calls)
+}
+
+func TestBreakerIgnoresMissingRoutes(t *testing.T) {
+	primary := &countingEngine{err: ErrNoRoute}
+	fallback := &countingEngine{result: &RouteResult{}}
+	breaker := NewBreaker(primary, fallback, 1, time.Minute)
+
+	origin := models.NewLocationWithData(7.1, 125.6)
+	destination := models.NewLocationWithData(7.2, 125.7)
+
+	_, err := breaker.FindRoute(origin, destination, RouteOptions{})
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	_, err = breaker.FindRoute(origin, destination, RouteOptions{})
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, 0, fallback.calls)
+}
@@ -0,0 +1,117 @@
+package routing_engine
+
+import (
+	"container/list"
+	"fmt"
+	"nearbyassist/internal/models"
+	"sync"
+	"time"
+)
+
+const (
+	ROUTE_CACHE_TTL         = time.Minute * 10
+	ROUTE_CACHE_MAX_ENTRIES = 1000
+)
+
+type cacheEntry struct {
+	key       string
+	result    *RouteResult
+	expiresAt time.Time
+}
+
+// Cache keeps recent routes in memory with a TTL and evicts the least
+// recently used route once it is full
+type Cache struct {
+	engine     Engine
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+func NewCache(engine Engine, ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		engine:     engine,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+func (c *Cache) FindRoute(origin, destination *models.Location, options RouteOptions) (*RouteResult, error) {
+	if options.Profile == "" {
+		options.Profile = PROFILE_DRIVING
+	}
+
+	key := cacheKey(origin, destination, options)
+	if result, ok := c.get(key); ok {
+		return result, nil
+	}
+
+	result, err := c.engine.FindRoute(origin, destination, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Estimates are not cached so the real route is used once the engine is back
+	if !result.Approximate {
+		c.set(key, result)
+	}
+
+	return result, nil
+}
+
+// Tables depend on the search results so they are not cached
+func (c *Cache) FindTable(origin *models.Location, destinations []models.Location, profile Profile) (*TableResult, error) {
+	return c.engine.FindTable(origin, destinations, profile)
+}
+
+// Coordinates are rounded to 4 decimal places, about 11 meters
+func cacheKey(origin, destination *models.Location, options RouteOptions) string {
+	return fmt.Sprintf("%s|%t|%t|%.4f,%.4f|%.4f,%.4f", options.Profile, options.Alternatives, options.Steps,
+		origin.Latitude, origin.Longitude, destination.Latitude, destination.Longitude)
+}
+
+func (c *Cache) get(key string) (*RouteResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.result, true
+}
+
+func (c *Cache) set(key string, result *RouteResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = &cacheEntry{key: key, result: result, expiresAt: c.now().Add(c.ttl)}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result, expiresAt: c.now().Add(c.ttl)})
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,99 @@
+package routing_engine
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingEngine struct {
+	calls  int
+	result *RouteResult
+	err    error
+}
+
+func (e *countingEngine) FindRoute(origin, destination *models.Location, options RouteOptions) (*RouteResult, error) {
+	e.calls++
+	return e.result, e.err
+}
+
+func (e *countingEngine) FindTable(origin *models.Location, destinations []models.Location, profile Profile) (*TableResult, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return &TableResult{Profile: profile, Entries: make([]TableEntry, len(destinations))}, nil
+}
+
+func TestCacheReusesRoundedRoutes(t *testing.T) {
+	engine := &countingEngine{result: &RouteResult{Route: Route{Distance: 100}}}
+	cache := NewCache(engine, time.Minute, 10)
+
+	_, err := cache.FindRoute(models.NewLocationWithData(7.10001, 125.6), models.NewLocationWithData(7.2, 125.7), RouteOptions{})
+	assert.NoError(t, err)
+
+	result, err := cache.FindRoute(models.NewLocationWithData(7.10002, 125.6), models.NewLocationWithData(7.2, 125.7), RouteOptions{Profile: PROFILE_DRIVING})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, engine.calls)
+	assert.Equal(t, 100.0, result.Route.Distance)
+
+	_, err = cache.FindRoute(models.NewLocationWithData(7.1, 125.6), models.NewLocationWithData(7.2, 125.7), RouteOptions{Profile: PROFILE_WALKING})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, engine.calls)
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	engine := &countingEngine{result: &RouteResult{}}
+	cache := NewCache(engine, time.Minute, 10)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	origin := models.NewLocationWithData(7.1, 125.6)
+	destination := models.NewLocationWithData(7.2, 125.7)
+
+	cache.FindRoute(origin, destination, RouteOptions{})
+	now = now.Add(time.Minute * 2)
+	cache.FindRoute(origin, destination, RouteOptions{})
+
+	assert.Equal(t, 2, engine.calls)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	engine := &countingEngine{result: &RouteResult{}}
+	cache := NewCache(engine, time.Minute, 2)
+
+	first := models.NewLocationWithData(7.1, 125.6)
+	second := models.NewLocationWithData(7.2, 125.6)
+	third := models.NewLocationWithData(7.3, 125.6)
+	destination := models.NewLocationWithData(7.0, 125.5)
+
+	cache.FindRoute(first, destination, RouteOptions{})
+	cache.FindRoute(second, destination, RouteOptions{})
+	cache.FindRoute(first, destination, RouteOptions{})
+	cache.FindRoute(third, destination, RouteOptions{})
+	assert.Equal(t, 3, engine.calls)
+
+	// second was the least recently used and got evicted
+	cache.FindRoute(first, destination, RouteOptions{})
+	assert.Equal(t, 3, engine.calls)
+	cache.FindRoute(second, destination, RouteOptions{})
+	assert.Equal(t, 4, engine.calls)
+}
+
+func TestCacheSkipsApproximateRoutes(t *testing.T) {
+	engine := &countingEngine{result: &RouteResult{Approximate: true}}
+	cache := NewCache(engine, time.Minute, 10)
+
+	origin := models.NewLocationWithData(7.1, 125.6)
+	destination := models.NewLocationWithData(7.2, 125.7)
+
+	cache.FindRoute(origin, destination, RouteOptions{})
+	cache.FindRoute(origin, destination, RouteOptions{})
+
+	assert.Equal(t, 2, engine.calls)
+}
@@ -0,0 +1,122 @@
+package routing_engine
+
+import (
+	"math"
+	"nearbyassist/internal/models"
+	"strings"
+)
+
+const EARTH_RADIUS_METERS = 6371000
+
+// Average speeds in meters per second used to estimate durations
+var fallbackSpeeds = map[Profile]float64{
+	PROFILE_DRIVING: 30 * 1000 / 3600.0,
+	PROFILE_WALKING: 5 * 1000 / 3600.0,
+	PROFILE_CYCLING: 15 * 1000 / 3600.0,
+}
+
+// Fallback estimates routes without a routing engine. The route is a straight
+// line so results are always marked as approximate.
+type Fallback struct{}
+
+func NewFallback() *Fallback {
+	return &Fallback{}
+}
+
+func (f *Fallback) FindRoute(origin, destination *models.Location, options RouteOptions) (*RouteResult, error) {
+	if options.Profile == "" {
+		options.Profile = PROFILE_DRIVING
+	}
+
+	speed, ok := fallbackSpeeds[options.Profile]
+	if !ok {
+		return nil, ErrInvalidQuery
+	}
+
+	distance := Haversine(origin, destination)
+
+	return &RouteResult{
+		Profile:     options.Profile,
+		Approximate: true,
+		Route: Route{
+			Distance: distance,
+			Duration: distance / speed,
+			Polyline: EncodePolyline([]models.Location{*origin, *destination}),
+		},
+		Alternatives: make([]Route, 0),
+	}, nil
+}
+
+func (f *Fallback) FindTable(origin *models.Location, destinations []models.Location, profile Profile) (*TableResult, error) {
+	if profile == "" {
+		profile = PROFILE_DRIVING
+	}
+
+	speed, ok := fallbackSpeeds[profile]
+	if !ok {
+		return nil, ErrInvalidQuery
+	}
+
+	result := &TableResult{
+		Profile:     profile,
+		Approximate: true,
+		Entries:     make([]TableEntry, 0, len(destinations)),
+	}
+
+	for _, destination := range destinations {
+		distance := Haversine(origin, &destination)
+		result.Entries = append(result.Entries, TableEntry{
+			Distance:  distance,
+			Duration:  distance / speed,
+			Reachable: true,
+		})
+	}
+
+	return result, nil
+}
+
+// Great-circle distance in meters
+func Haversine(origin, destination *models.Location) float64 {
+	lat1 := origin.Latitude * math.Pi / 180
+	lat2 := destination.Latitude * math.Pi / 180
+	deltaLat := (destination.Latitude - origin.Latitude) * math.Pi / 180
+	deltaLong := (destination.Longitude - origin.Longitude) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLong/2)*math.Sin(deltaLong/2)
+
+	return EARTH_RADIUS_METERS * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// Encodes points with the polyline algorithm at precision 5, the same format
+// OSRM returns
+func EncodePolyline(points []models.Location) PolylineCode {
+	var builder strings.Builder
+
+	prevLat, prevLong := 0, 0
+	for _, point := range points {
+		lat := int(math.Round(point.Latitude * 1e5))
+		long := int(math.Round(point.Longitude * 1e5))
+
+		encodePolylineValue(&builder, lat-prevLat)
+		encodePolylineValue(&builder, long-prevLong)
+
+		prevLat, prevLong = lat, long
+	}
+
+	return PolylineCode(builder.String())
+}
+
+func encodePolylineValue(builder *strings.Builder, value int) {
+	shifted := value << 1
+	if value < 0 {
+		shifted = ^shifted
+	}
+
+	for shifted >= 0x20 {
+		builder.WriteByte(byte((0x20 | (shifted & 0x1f)) + 63))
+		shifted >>= 5
+	}
+
+	builder.WriteByte(byte(shifted + 63))
+}
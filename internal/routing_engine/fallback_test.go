@@ -0,0 +1,51 @@
+package routing_engine
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePolyline(t *testing.T) {
+	points := []models.Location{
+		{Latitude: 38.5, Longitude: -120.2},
+		{Latitude: 40.7, Longitude: -120.95},
+		{Latitude: 43.252, Longitude: -126.453},
+	}
+
+	assert.Equal(t, PolylineCode("_p~iF~ps|U_ulLnnqC_mqNvxq`@"), EncodePolyline(points))
+}
+
+func TestHaversine(t *testing.T) {
+	// One degree along a meridian
+	origin := models.NewLocationWithData(7, 125.6)
+	destination := models.NewLocationWithData(8, 125.6)
+
+	assert.InDelta(t, 111195, Haversine(origin, destination), 1)
+}
+
+func TestFallbackFindRoute(t *testing.T) {
+	origin := models.NewLocationWithData(7.0731, 125.6128)
+	destination := models.NewLocationWithData(7.1907, 125.4553)
+
+	result, err := NewFallback().FindRoute(origin, destination, RouteOptions{Profile: PROFILE_WALKING})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Approximate)
+	assert.Equal(t, result.Route.Distance/(5*1000/3600.0), result.Route.Duration)
+	assert.Equal(t, EncodePolyline([]models.Location{*origin, *destination}), result.Route.Polyline)
+}
+
+func TestFallbackFindTable(t *testing.T) {
+	origin := models.NewLocationWithData(7, 125.6)
+	destinations := []models.Location{*models.NewLocationWithData(8, 125.6)}
+
+	result, err := NewFallback().FindTable(origin, destinations, PROFILE_DRIVING)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Approximate)
+	assert.Len(t, result.Entries, 1)
+	assert.InDelta(t, 111195, result.Entries[0].Distance, 1)
+	assert.True(t, result.Entries[0].Reachable)
+}
@@ -3,67 +3,284 @@ package routing_engine
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"nearbyassist/internal/config"
 	"nearbyassist/internal/models"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
+type osrmManeuver struct {
+	Type     string     `json:"type"`
+	Modifier string     `json:"modifier"`
+	Location [2]float64 `json:"location"`
+}
+
+type osrmStep struct {
+	Geometry string       `json:"geometry"`
+	Name     string       `json:"name"`
+	Duration float64      `json:"duration"`
+	Distance float64      `json:"distance"`
+	Maneuver osrmManeuver `json:"maneuver"`
+}
+
+type osrmLeg struct {
+	Steps []osrmStep `json:"steps"`
+}
+
 type osrmRoute struct {
-	Geometry string  `json:"geometry"`
-	Duration float32 `json:"duration"`
-	Distance float32 `json:"distance"`
+	Geometry string    `json:"geometry"`
+	Duration float64   `json:"duration"`
+	Distance float64   `json:"distance"`
+	Legs     []osrmLeg `json:"legs"`
 }
 
 type osrmResponse struct {
-	Code   string      `json:"code"`
-	Routes []osrmRoute `json:"routes"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Routes  []osrmRoute `json:"routes"`
+}
+
+type osrmTableResponse struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Durations [][]*float64 `json:"durations"`
+	Distances [][]*float64 `json:"distances"`
 }
 
 type OSRM struct {
 	engineUrl      string
 	requestTimeout time.Duration
+	client         *http.Client
 }
 
 func NewOSRM(conf *config.Config) *OSRM {
 	return &OSRM{
 		engineUrl:      conf.RouteEngineUrl,
 		requestTimeout: 5 * time.Second,
+		client:         &http.Client{},
 	}
 }
 
-func (e *OSRM) constructUrl(origin, destination models.Location) string {
-	return e.engineUrl + "/route/v1/driving/" + origin.StringReverseOrder() + ";" + destination.StringReverseOrder()
+func (e *OSRM) constructUrl(origin, destination models.Location, options RouteOptions) string {
+	query := url.Values{}
+	query.Set("overview", "full")
+	query.Set("alternatives", fmt.Sprint(options.Alternatives))
+	query.Set("steps", fmt.Sprint(options.Steps))
+
+	return e.engineUrl + "/route/v1/" + string(options.Profile) + "/" + origin.StringReverseOrder() + ";" + destination.StringReverseOrder() + "?" + query.Encode()
 }
 
-func (e *OSRM) FindRoute(origin, destination *models.Location) (PolylineCode, error) {
+func (e *OSRM) FindRoute(origin, destination *models.Location, options RouteOptions) (*RouteResult, error) {
+	if options.Profile == "" {
+		options.Profile = PROFILE_DRIVING
+	}
+
+	if !options.Profile.IsValid() {
+		return nil, fmt.Errorf("%w: unsupported profile %s", ErrInvalidQuery, options.Profile)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), e.requestTimeout)
 	defer cancel()
 
-	url := e.constructUrl(*origin, *destination)
+	url := e.constructUrl(*origin, *destination, options)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	client := http.Client{}
-	resp, err := client.Do(req)
+	resp, err := e.client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, err.Error())
 	}
 	defer resp.Body.Close()
 
 	bytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	// OSRM reports failures in the body, non JSON responses come from a
+	// proxy or a crashed engine
 	data := new(osrmResponse)
 	if err := json.Unmarshal(bytes, &data); err != nil {
-		return "", err
+		return nil, fmt.Errorf("%w: unexpected response with status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	if err := osrmError(data.Code, data.Message); err != nil {
+		return nil, err
+	}
+
+	if len(data.Routes) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	result := &RouteResult{
+		Profile:      options.Profile,
+		Route:        toRoute(data.Routes[0]),
+		Alternatives: make([]Route, 0),
+	}
+
+	for _, route := range data.Routes[1:] {
+		result.Alternatives = append(result.Alternatives, toRoute(route))
+	}
+
+	return result, nil
+}
+
+// Uses the table service with the origin as the only source
+func (e *OSRM) FindTable(origin *models.Location, destinations []models.Location, profile Profile) (*TableResult, error) {
+	if profile == "" {
+		profile = PROFILE_DRIVING
+	}
+
+	if !profile.IsValid() {
+		return nil, fmt.Errorf("%w: unsupported profile %s", ErrInvalidQuery, profile)
+	}
+
+	result := &TableResult{
+		Profile: profile,
+		Entries: make([]TableEntry, len(destinations)),
+	}
+
+	if len(destinations) == 0 {
+		return result, nil
+	}
+
+	coordinates := make([]string, 0, len(destinations)+1)
+	coordinates = append(coordinates, origin.StringReverseOrder())
+	for _, destination := range destinations {
+		coordinates = append(coordinates, destination.StringReverseOrder())
+	}
+
+	query := url.Values{}
+	query.Set("sources", "0")
+	query.Set("annotations", "duration,distance")
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.requestTimeout)
+	defer cancel()
+
+	url := e.engineUrl + "/table/v1/" + string(profile) + "/" + strings.Join(coordinates, ";") + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(osrmTableResponse)
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, fmt.Errorf("%w: unexpected response with status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	if err := osrmError(data.Code, data.Message); err != nil {
+		return nil, err
+	}
+
+	if len(data.Durations) == 0 || len(data.Durations[0]) != len(coordinates) {
+		return nil, fmt.Errorf("%w: malformed table response", ErrUnavailable)
+	}
+
+	// The first column is the origin itself
+	for i := range destinations {
+		duration := data.Durations[0][i+1]
+		if duration == nil {
+			continue
+		}
+
+		entry := TableEntry{Duration: *duration, Reachable: true}
+		if len(data.Distances) > 0 && len(data.Distances[0]) == len(coordinates) && data.Distances[0][i+1] != nil {
+			entry.Distance = *data.Distances[0][i+1]
+		}
+
+		result.Entries[i] = entry
+	}
+
+	return result, nil
+}
+
+// Maps OSRM response codes to the engine errors
+func osrmError(code, message string) error {
+	switch code {
+	case "Ok":
+		return nil
+	case "NoRoute":
+		return ErrNoRoute
+	case "NoSegment":
+		return fmt.Errorf("%w: %s", ErrNoSegment, message)
+	case "TooBig":
+		return fmt.Errorf("%w: %s", ErrTooBig, message)
+	case "NoTable":
+		return ErrNoRoute
+	case "InvalidUrl", "InvalidService", "InvalidVersion", "InvalidOptions", "InvalidQuery", "InvalidValue":
+		return fmt.Errorf("%w: %s", ErrInvalidQuery, message)
+	}
+
+	return fmt.Errorf("%w: %s %s", ErrUnavailable, code, message)
+}
+
+func toRoute(route osrmRoute) Route {
+	result := Route{
+		Distance: route.Distance,
+		Duration: route.Duration,
+		Polyline: PolylineCode(route.Geometry),
+	}
+
+	for _, leg := range route.Legs {
+		for _, step := range leg.Steps {
+			result.Steps = append(result.Steps, Step{
+				Instruction: instruction(step),
+				Name:        step.Name,
+				Distance:    step.Distance,
+				Duration:    step.Duration,
+				Polyline:    PolylineCode(step.Geometry),
+				Maneuver: Maneuver{
+					Type:     step.Maneuver.Type,
+					Modifier: step.Maneuver.Modifier,
+					Location: step.Maneuver.Location,
+				},
+			})
+		}
+	}
+
+	return result
+}
+
+// Builds a short readable instruction from the maneuver, ex: Turn left onto Rizal Street
+func instruction(step osrmStep) string {
+	var text string
+
+	switch step.Maneuver.Type {
+	case "depart":
+		text = "Head out"
+	case "arrive":
+		return "Arrive at your destination"
+	case "turn", "end of road", "fork", "merge", "on ramp", "off ramp":
+		text = "Turn " + step.Maneuver.Modifier
+	case "roundabout", "rotary":
+		text = "Enter the roundabout"
+	default:
+		text = "Continue " + step.Maneuver.Modifier
+	}
+
+	text = strings.TrimSpace(text)
+	if step.Name != "" {
+		text += " onto " + step.Name
 	}
 
-	return PolylineCode(data.Routes[0].Geometry), nil
+	return text
 }
@@ -0,0 +1,164 @@
+package routing_engine
+
+import (
+	"errors"
+	"nearbyassist/internal/config"
+	"nearbyassist/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const osrmOkResponse = `{
+    "code": "Ok",
+    "routes": [
+        {
+            "geometry": "main_polyline",
+            "distance": 1520.4,
+            "duration": 240.7,
+            "legs": [
+                {
+                    "steps": [
+                        {"geometry": "a", "name": "Rizal Street", "distance": 1000, "duration": 150, "maneuver": {"type": "depart", "location": [125.6, 7.1]}},
+                        {"geometry": "b", "name": "Mabini Street", "distance": 520.4, "duration": 90.7, "maneuver": {"type": "turn", "modifier": "left", "location": [125.61, 7.11]}},
+                        {"geometry": "c", "name": "", "distance": 0, "duration": 0, "maneuver": {"type": "arrive", "location": [125.62, 7.12]}}
+                    ]
+                }
+            ]
+        },
+        {"geometry": "alternative_polyline", "distance": 1800, "duration": 300, "legs": []}
+    ]
+}`
+
+func newTestOSRM(handler http.HandlerFunc) (*OSRM, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	return NewOSRM(&config.Config{RouteEngineUrl: srv.URL}), srv
+}
+
+func TestFindRoute(t *testing.T) {
+	engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/route/v1/walking/125.6,7.1;125.62,7.12", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("steps"))
+		assert.Equal(t, "true", r.URL.Query().Get("alternatives"))
+
+		w.Write([]byte(osrmOkResponse))
+	})
+	defer srv.Close()
+
+	origin := models.NewLocationWithData(7.1, 125.6)
+	destination := models.NewLocationWithData(7.12, 125.62)
+
+	result, err := engine.FindRoute(origin, destination, RouteOptions{Profile: PROFILE_WALKING, Alternatives: true, Steps: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, PolylineCode("main_polyline"), result.Route.Polyline)
+	assert.Equal(t, 1520.4, result.Route.Distance)
+	assert.Equal(t, 240.7, result.Route.Duration)
+	assert.Len(t, result.Route.Steps, 3)
+	assert.Equal(t, "Head out onto Rizal Street", result.Route.Steps[0].Instruction)
+	assert.Equal(t, "Turn left onto Mabini Street", result.Route.Steps[1].Instruction)
+	assert.Equal(t, "Arrive at your destination", result.Route.Steps[2].Instruction)
+	assert.Len(t, result.Alternatives, 1)
+	assert.Equal(t, PolylineCode("alternative_polyline"), result.Alternatives[0].Polyline)
+}
+
+func TestFindRouteDefaultsToDriving(t *testing.T) {
+	engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/route/v1/driving/125.6,7.1;125.62,7.12", r.URL.Path)
+		w.Write([]byte(osrmOkResponse))
+	})
+	defer srv.Close()
+
+	result, err := engine.FindRoute(models.NewLocationWithData(7.1, 125.6), models.NewLocationWithData(7.12, 125.62), RouteOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, PROFILE_DRIVING, result.Profile)
+}
+
+func TestFindRouteErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"no route", http.StatusOK, `{"code": "NoRoute", "message": "Impossible route"}`, ErrNoRoute},
+		{"empty routes", http.StatusOK, `{"code": "Ok", "routes": []}`, ErrNoRoute},
+		{"no segment", http.StatusBadRequest, `{"code": "NoSegment", "message": "Could not find a matching segment"}`, ErrNoSegment},
+		{"invalid value", http.StatusBadRequest, `{"code": "InvalidValue", "message": "Invalid coordinate"}`, ErrInvalidQuery},
+		{"too big", http.StatusBadRequest, `{"code": "TooBig", "message": "Too many coordinates"}`, ErrTooBig},
+		{"not json", http.StatusBadGateway, `<html>bad gateway</html>`, ErrUnavailable},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			})
+			defer srv.Close()
+
+			_, err := engine.FindRoute(models.NewLocationWithData(7.1, 125.6), models.NewLocationWithData(7.12, 125.62), RouteOptions{})
+
+			assert.True(t, errors.Is(err, tc.expected), err)
+		})
+	}
+}
+
+func TestFindRouteInvalidProfile(t *testing.T) {
+	engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("engine should not be called")
+	})
+	defer srv.Close()
+
+	_, err := engine.FindRoute(models.NewLocationWithData(7.1, 125.6), models.NewLocationWithData(7.12, 125.62), RouteOptions{Profile: "flying"})
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestFindRouteUnavailable(t *testing.T) {
+	engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {})
+	srv.Close()
+
+	_, err := engine.FindRoute(models.NewLocationWithData(7.1, 125.6), models.NewLocationWithData(7.12, 125.62), RouteOptions{})
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestFindTable(t *testing.T) {
+	engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/table/v1/cycling/125.6,7.1;125.61,7.11;125.62,7.12", r.URL.Path)
+		assert.Equal(t, "0", r.URL.Query().Get("sources"))
+		assert.Equal(t, "duration,distance", r.URL.Query().Get("annotations"))
+
+		w.Write([]byte(`{"code": "Ok", "durations": [[0, 180.5, null]], "distances": [[0, 1200.2, null]]}`))
+	})
+	defer srv.Close()
+
+	destinations := []models.Location{
+		*models.NewLocationWithData(7.11, 125.61),
+		*models.NewLocationWithData(7.12, 125.62),
+	}
+
+	result, err := engine.FindTable(models.NewLocationWithData(7.1, 125.6), destinations, PROFILE_CYCLING)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Approximate)
+	assert.Equal(t, []TableEntry{
+		{Distance: 1200.2, Duration: 180.5, Reachable: true},
+		{},
+	}, result.Entries)
+}
+
+func TestFindTableMalformed(t *testing.T) {
+	engine, srv := newTestOSRM(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code": "Ok", "durations": [[0]]}`))
+	})
+	defer srv.Close()
+
+	_, err := engine.FindTable(models.NewLocationWithData(7.1, 125.6), []models.Location{*models.NewLocationWithData(7.11, 125.61)}, PROFILE_DRIVING)
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
@@ -1,9 +1,90 @@
 package routing_engine
 
-import "nearbyassist/internal/models"
+import (
+	"errors"
+	"nearbyassist/internal/models"
+)
 
 type PolylineCode string
 
+type Profile string
+
+const (
+	PROFILE_DRIVING Profile = "driving"
+	PROFILE_WALKING Profile = "walking"
+	PROFILE_CYCLING Profile = "cycling"
+)
+
+func (p Profile) IsValid() bool {
+	switch p {
+	case PROFILE_DRIVING, PROFILE_WALKING, PROFILE_CYCLING:
+		return true
+	}
+
+	return false
+}
+
+var (
+	ErrNoRoute      = errors.New("no route found between the given locations")
+	ErrNoSegment    = errors.New("location could not be matched to a road")
+	ErrInvalidQuery = errors.New("invalid route query")
+	ErrTooBig       = errors.New("route request is too large")
+	ErrUnavailable  = errors.New("routing engine is unavailable")
+)
+
+type RouteOptions struct {
+	Profile      Profile
+	Alternatives bool
+	Steps        bool
+}
+
+type Maneuver struct {
+	Type     string     `json:"type"`
+	Modifier string     `json:"modifier,omitempty"`
+	Location [2]float64 `json:"location"`
+}
+
+type Step struct {
+	Instruction string       `json:"instruction"`
+	Name        string       `json:"name"`
+	Distance    float64      `json:"distance"`
+	Duration    float64      `json:"duration"`
+	Polyline    PolylineCode `json:"polyline"`
+	Maneuver    Maneuver     `json:"maneuver"`
+}
+
+// Distance is in meters and duration in seconds
+type Route struct {
+	Distance float64      `json:"distance"`
+	Duration float64      `json:"duration"`
+	Polyline PolylineCode `json:"polyline"`
+	Steps    []Step       `json:"steps,omitempty"`
+}
+
+// Approximate is set when the route is an estimate and not road based
+type RouteResult struct {
+	Profile      Profile `json:"profile"`
+	Approximate  bool    `json:"approximate"`
+	Route        Route   `json:"route"`
+	Alternatives []Route `json:"alternatives"`
+}
+
+// Travel distance and duration from an origin to a single destination,
+// Reachable is false when the engine found no route to it
+type TableEntry struct {
+	Distance  float64 `json:"distance"`
+	Duration  float64 `json:"duration"`
+	Reachable bool    `json:"reachable"`
+}
+
+// Entries are in the same order as the destinations
+type TableResult struct {
+	Profile     Profile      `json:"profile"`
+	Approximate bool         `json:"approximate"`
+	Entries     []TableEntry `json:"entries"`
+}
+
 type Engine interface {
-	FindRoute(origin, destination *models.Location) (PolylineCode, error)
+	FindRoute(origin, destination *models.Location, options RouteOptions) (*RouteResult, error)
+	FindTable(origin *models.Location, destinations []models.Location, profile Profile) (*TableResult, error)
 }
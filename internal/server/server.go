@@ -1,11 +1,16 @@
 package server
 
 import (
+	"nearbyassist/internal/activity"
 	"nearbyassist/internal/authenticator"
+	"nearbyassist/internal/autocomplete"
 	"nearbyassist/internal/config"
 	"nearbyassist/internal/db"
 	"nearbyassist/internal/encryption"
 	"nearbyassist/internal/hash"
+	"nearbyassist/internal/notification"
+	"nearbyassist/internal/payment"
+	"nearbyassist/internal/receipt"
 	"nearbyassist/internal/routing_engine"
 	"nearbyassist/internal/storage"
 	"nearbyassist/internal/suggestion_engine"
@@ -23,14 +28,19 @@ type Server struct {
 	Storage          storage.Storage
 	RouteEngine      routing_engine.Engine
 	SuggestionEngine suggestion_engine.Engine
+	Autocomplete     *autocomplete.Index
 	Encrypt          encryption.Encryption
 	Hash             hash.Hash
 	Auth             authenticator.Authenticator
+	Notification     *notification.Center
+	Payment          payment.PaymentProvider
+	Receipts         *receipt.Generator
+	Activity         *activity.Recorder
 	Port             string
 	AllowedOrigins   []string
 }
 
-func NewServer(conf *config.Config, ws *websocket.Websocket, db db.Database, storage storage.Storage, auth authenticator.Authenticator, router routing_engine.Engine, courtier suggestion_engine.Engine, crypto encryption.Encryption, hash hash.Hash) *Server {
+func NewServer(conf *config.Config, ws *websocket.Websocket, db db.Database, storage storage.Storage, auth authenticator.Authenticator, router routing_engine.Engine, courtier suggestion_engine.Engine, suggestions *autocomplete.Index, crypto encryption.Encryption, hash hash.Hash, notifications *notification.Center, payments payment.PaymentProvider, receipts *receipt.Generator, recorder *activity.Recorder) *Server {
 	NewServer := &Server{
 		Echo:             echo.New(),
 		Websocket:        ws,
@@ -38,9 +48,14 @@ func NewServer(conf *config.Config, ws *websocket.Websocket, db db.Database, sto
 		Storage:          storage,
 		RouteEngine:      router,
 		SuggestionEngine: courtier,
+		Autocomplete:     suggestions,
 		Encrypt:          crypto,
 		Hash:             hash,
 		Auth:             auth,
+		Notification:     notifications,
+		Payment:          payments,
+		Receipts:         receipts,
+		Activity:         recorder,
 		Port:             conf.Port,
 		AllowedOrigins:   conf.AllowedOrigins,
 	}
@@ -15,6 +15,8 @@ type DiskStorage struct {
 	FrontIdLocation          string
 	BackIdLocation           string
 	FaceLocation             string
+	VendorComplaintLocation  string
+	ReceiptLocation          string
 	storagePermission        os.FileMode
 }
 
@@ -23,6 +25,8 @@ func newDiskStorage(conf *config.Config) *DiskStorage {
 		ApplicationProofLocation: conf.ApplicationProofLocation,
 		ServicePhotoLocation:     conf.ServicePhotoLocation,
 		SystemComplaintLocation:  conf.SystemComplaintLocation,
+		VendorComplaintLocation:  conf.VendorComplaintLocation,
+		ReceiptLocation:          conf.ReceiptLocation,
 		storagePermission:        0777,
 	}
 }
@@ -52,6 +56,14 @@ func (s *DiskStorage) Initialize() error {
 		return err
 	}
 
+	if err := os.MkdirAll(s.VendorComplaintLocation, s.storagePermission); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.ReceiptLocation, s.storagePermission); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -176,3 +188,53 @@ func (s *DiskStorage) SaveFace(file []byte, filename string) (string, error) {
 	url := s.FaceLocation + "/" + filename
 	return url, nil
 }
+
+func (s *DiskStorage) SaveVendorComplaint(file []byte, filename string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	storageDir := s.VendorComplaintLocation
+	path := filepath.Join(storageDir, filename)
+
+	if err := s.SaveFile(path, file); err != nil {
+		return "", err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", context.DeadlineExceeded
+	}
+
+	url := s.VendorComplaintLocation + "/" + filename
+	return url, nil
+}
+
+func (s *DiskStorage) SaveReceipt(file []byte, filename string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	storageDir := s.ReceiptLocation
+	path := filepath.Join(storageDir, filename)
+
+	if err := s.SaveFile(path, file); err != nil {
+		return "", err
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", context.DeadlineExceeded
+	}
+
+	url := s.ReceiptLocation + "/" + filename
+	return url, nil
+}
+
+// Reads back a file saved by one of the save functions, path is the url
+// they returned
+func (s *DiskStorage) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// Removes a file saved by one of the save functions, used to clean up after
+// the record it belongs to could not be stored
+func (s *DiskStorage) DeleteFile(path string) error {
+	return os.Remove(path)
+}
@@ -38,3 +38,19 @@ func (s *DummyStorage) SaveBackId(file []byte, filename string) (string, error)
 func (s *DummyStorage) SaveFace(file []byte, filename string) (string, error) {
 	return "", nil
 }
+
+func (s *DummyStorage) SaveVendorComplaint(file []byte, filename string) (string, error) {
+	return "", nil
+}
+
+func (s *DummyStorage) SaveReceipt(file []byte, filename string) (string, error) {
+	return "", nil
+}
+
+func (s *DummyStorage) ReadFile(path string) ([]byte, error) {
+	return nil, nil
+}
+
+func (s *DummyStorage) DeleteFile(path string) error {
+	return nil
+}
@@ -12,6 +12,10 @@ type Storage interface {
 	SaveFrontId(file []byte, filename string) (string, error)
 	SaveBackId(file []byte, filename string) (string, error)
 	SaveFace(file []byte, filename string) (string, error)
+	SaveVendorComplaint(file []byte, filename string) (string, error)
+	SaveReceipt(file []byte, filename string) (string, error)
+	ReadFile(path string) ([]byte, error)
+	DeleteFile(path string) error
 }
 
 func NewStorage(conf *config.Config) Storage {
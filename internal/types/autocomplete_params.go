@@ -0,0 +1,11 @@
+package types
+
+// Location is optional, without it every service counts as nearby
+type AutocompleteParams struct {
+	Query       string
+	Latitude    float64
+	Longitude   float64
+	HasLocation bool
+	Radius      float64
+	Limit       int
+}
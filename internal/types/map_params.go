@@ -0,0 +1,11 @@
+package types
+
+// Viewport of the map, tags are optional
+type MapParams struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+	Zoom         int
+	Query        []string
+}
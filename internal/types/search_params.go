@@ -5,4 +5,16 @@ type SearchParams struct {
 	Longitude float64
 	Radius    float64
 	Query     []string
+
+	// Travel options, durations are in seconds
+	Profile     string
+	SortBy      string
+	MaxDuration float64
+
+	// Filters, zero values are ignored. AvailableOn is a YYYY-MM-DD date
+	MinRate      float64
+	MaxRate      float64
+	MinRating    float64
+	VerifiedOnly bool
+	AvailableOn  string
 }
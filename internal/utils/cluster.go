@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"math"
+	"nearbyassist/internal/models"
+	"nearbyassist/internal/response"
+	"sort"
+)
+
+const (
+	// Services are no longer clustered from this zoom level onwards
+	CLUSTER_MAX_ZOOM = 16
+	// Width of a grid cell on screen
+	CLUSTER_CELL_PIXELS = 64
+)
+
+// Groups services into a grid sized for the zoom level, cells with a single
+// service are returned as the service itself
+func ClusterServices(services []*models.ServiceSearchResult, zoom int) ([]response.MapCluster, []*models.ServiceSearchResult) {
+	clusters := make([]response.MapCluster, 0)
+
+	if zoom >= CLUSTER_MAX_ZOOM {
+		return clusters, services
+	}
+
+	// A 256 pixel web mercator tile spans 360 degrees at zoom 0
+	cellSize := 360 / (256 * math.Pow(2, float64(zoom))) * CLUSTER_CELL_PIXELS
+
+	type cell struct{ row, col int }
+	cells := make(map[cell][]*models.ServiceSearchResult)
+	order := make([]cell, 0)
+
+	for _, service := range services {
+		key := cell{
+			row: int(math.Floor(service.Latitude / cellSize)),
+			col: int(math.Floor(service.Longitude / cellSize)),
+		}
+
+		if _, ok := cells[key]; !ok {
+			order = append(order, key)
+		}
+
+		cells[key] = append(cells[key], service)
+	}
+
+	singles := make([]*models.ServiceSearchResult, 0)
+	for _, key := range order {
+		members := cells[key]
+		if len(members) == 1 {
+			singles = append(singles, members[0])
+			continue
+		}
+
+		latitude, longitude := 0.0, 0.0
+		for _, member := range members {
+			latitude += member.Latitude
+			longitude += member.Longitude
+		}
+
+		clusters = append(clusters, response.MapCluster{
+			Latitude:  latitude / float64(len(members)),
+			Longitude: longitude / float64(len(members)),
+			Count:     len(members),
+		})
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Count > clusters[j].Count
+	})
+
+	return clusters, singles
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"nearbyassist/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newClusterService(id int, latitude, longitude float64) *models.ServiceSearchResult {
+	service := &models.ServiceSearchResult{}
+	service.Id = id
+	service.Latitude = latitude
+	service.Longitude = longitude
+
+	return service
+}
+
+func TestClusterServices(t *testing.T) {
+	services := []*models.ServiceSearchResult{
+		newClusterService(1, 7.0701, 125.6101),
+		newClusterService(2, 7.0703, 125.6103),
+		newClusterService(3, 7.0705, 125.6105),
+		newClusterService(4, 7.5000, 126.0000),
+	}
+
+	clusters, singles := ClusterServices(services, 10)
+
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, 3, clusters[0].Count)
+	assert.InDelta(t, 7.0703, clusters[0].Latitude, 1e-9)
+	assert.InDelta(t, 125.6103, clusters[0].Longitude, 1e-9)
+	assert.Len(t, singles, 1)
+	assert.Equal(t, 4, singles[0].Id)
+}
+
+func TestClusterServicesAtHighZoom(t *testing.T) {
+	services := []*models.ServiceSearchResult{
+		newClusterService(1, 7.0701, 125.6101),
+		newClusterService(2, 7.0703, 125.6103),
+	}
+
+	clusters, singles := ClusterServices(services, CLUSTER_MAX_ZOOM)
+
+	assert.Empty(t, clusters)
+	assert.Len(t, singles, 2)
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"errors"
+	"nearbyassist/internal/types"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	AUTOCOMPLETE_DEFAULT_RADIUS = 5000
+	AUTOCOMPLETE_DEFAULT_LIMIT  = 10
+	AUTOCOMPLETE_MAX_LIMIT      = 25
+)
+
+// Takes q, an optional lat and long pair, radius and limit
+func GetAutocompleteParams(c echo.Context) (*types.AutocompleteParams, error) {
+	query := strings.TrimSpace(strings.ReplaceAll(c.QueryParam("q"), "_", " "))
+	if query == "" {
+		return nil, errors.New("missing params")
+	}
+
+	params := &types.AutocompleteParams{
+		Query:  query,
+		Radius: AUTOCOMPLETE_DEFAULT_RADIUS,
+		Limit:  AUTOCOMPLETE_DEFAULT_LIMIT,
+	}
+
+	latitude := c.QueryParam("lat")
+	longitude := c.QueryParam("long")
+	if latitude != "" || longitude != "" {
+		lat, err := strconv.ParseFloat(latitude, 64)
+		if err != nil {
+			return nil, errors.New("lat and long must both be numbers")
+		}
+
+		long, err := strconv.ParseFloat(longitude, 64)
+		if err != nil {
+			return nil, errors.New("lat and long must both be numbers")
+		}
+
+		params.Latitude = lat
+		params.Longitude = long
+		params.HasLocation = true
+	}
+
+	if radius := c.QueryParam("radius"); radius != "" {
+		rad, err := strconv.ParseFloat(radius, 64)
+		if err != nil || rad <= 0 {
+			return nil, errors.New("radius must be a positive number")
+		}
+
+		params.Radius = rad
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > AUTOCOMPLETE_MAX_LIMIT {
+			return nil, errors.New("limit must be between 1 and 25")
+		}
+
+		params.Limit = value
+	}
+
+	return params, nil
+}
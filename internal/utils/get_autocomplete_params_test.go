@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAutocompleteParams(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/public/tags/autocomplete?q=aircon_re&lat=7.07&long=125.61&limit=5", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	params, err := GetAutocompleteParams(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "aircon re", params.Query)
+	assert.True(t, params.HasLocation)
+	assert.Equal(t, 125.61, params.Longitude)
+	assert.Equal(t, float64(AUTOCOMPLETE_DEFAULT_RADIUS), params.Radius)
+	assert.Equal(t, 5, params.Limit)
+}
+
+func TestGetAutocompleteParamsInvalid(t *testing.T) {
+	queries := []string{
+		"q=",
+		"q=plu&lat=7.07",
+		"q=plu&limit=100",
+		"q=plu&radius=-1",
+	}
+
+	e := echo.New()
+	for _, query := range queries {
+		req := httptest.NewRequest(http.MethodGet, "/v1/public/tags/autocomplete?"+query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		_, err := GetAutocompleteParams(c)
+		assert.Error(t, err, query)
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"nearbyassist/internal/types"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Takes bbox=minLat,minLong,maxLat,maxLong, zoom and an optional q=tag1,tag2
+func GetMapParams(c echo.Context) (*types.MapParams, error) {
+	bbox := strings.Split(c.QueryParam("bbox"), ",")
+	if len(bbox) != 4 {
+		return nil, errors.New("bbox must be in the form minLat,minLong,maxLat,maxLong")
+	}
+
+	coords := make([]float64, 4)
+	for i, value := range bbox {
+		coord, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("bbox coordinates must be numbers")
+		}
+
+		coords[i] = coord
+	}
+
+	if coords[0] > coords[2] || coords[1] > coords[3] {
+		return nil, errors.New("bbox minimum must be lower than the maximum")
+	}
+
+	if coords[0] < -90 || coords[2] > 90 || coords[1] < -180 || coords[3] > 180 {
+		return nil, errors.New("bbox is out of range")
+	}
+
+	zoom, err := strconv.Atoi(c.QueryParam("zoom"))
+	if err != nil || zoom < 0 || zoom > 22 {
+		return nil, errors.New("zoom must be a number between 0 and 22")
+	}
+
+	params := types.MapParams{
+		MinLatitude:  coords[0],
+		MinLongitude: coords[1],
+		MaxLatitude:  coords[2],
+		MaxLongitude: coords[3],
+		Zoom:         zoom,
+	}
+
+	if query := c.QueryParam("q"); query != "" {
+		params.Query = parseTags(query)
+	}
+
+	return &params, nil
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMapParams(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/public/services/map?bbox=7.0,125.5,7.2,125.7&zoom=12&q=plumber,aircon_repair", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	params, err := GetMapParams(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, params.MinLatitude)
+	assert.Equal(t, 125.7, params.MaxLongitude)
+	assert.Equal(t, 12, params.Zoom)
+	assert.Equal(t, []string{"plumber", "aircon repair"}, params.Query)
+}
+
+func TestGetMapParamsInvalid(t *testing.T) {
+	queries := []string{
+		"bbox=7.0,125.5,7.2&zoom=12",
+		"bbox=7.2,125.5,7.0,125.7&zoom=12",
+		"bbox=7.0,125.5,7.2,125.7&zoom=30",
+		"bbox=7.0,125.5,7.2,125.7",
+	}
+
+	e := echo.New()
+	for _, query := range queries {
+		req := httptest.NewRequest(http.MethodGet, "/v1/public/services/map?"+query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		_, err := GetMapParams(c)
+
+		assert.Error(t, err, query)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"nearbyassist/internal/types"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -38,15 +39,86 @@ func GetSearchParams(c echo.Context) (*types.SearchParams, error) {
 		return nil, err
 	}
 
-	queryNoUnderscore := strings.ReplaceAll(query, "_", " ")
-	tags := strings.Split(queryNoUnderscore, ",")
+	maxDuration := 0.0
+	if param := c.QueryParam("maxDuration"); param != "" {
+		if maxDuration, err = strconv.ParseFloat(param, 64); err != nil || maxDuration < 0 {
+			return nil, errors.New("maxDuration must be a positive number of seconds")
+		}
+	}
+
+	sortBy := c.QueryParam("sort")
+	switch sortBy {
+	case "":
+		sortBy = SORT_RELEVANCE
+	case "suggestability":
+		sortBy = SORT_RELEVANCE
+	case SORT_RELEVANCE, SORT_DISTANCE, SORT_DURATION, SORT_RATE, SORT_RATING:
+	default:
+		return nil, errors.New("sort must be one of relevance, distance, duration, rate or rating")
+	}
+
+	filters := make(map[string]float64)
+	for _, name := range []string{"minRate", "maxRate", "minRating"} {
+		param := c.QueryParam(name)
+		if param == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(param, 64)
+		if err != nil || value < 0 {
+			return nil, errors.New(name + " must be a positive number")
+		}
+
+		filters[name] = value
+	}
+
+	if filters["maxRate"] > 0 && filters["minRate"] > filters["maxRate"] {
+		return nil, errors.New("minRate must not be greater than maxRate")
+	}
+
+	if filters["minRating"] > 5 {
+		return nil, errors.New("minRating must be between 0 and 5")
+	}
+
+	availableOn := c.QueryParam("availableOn")
+	if availableOn != "" {
+		if _, err := time.Parse(time.DateOnly, availableOn); err != nil {
+			return nil, errors.New("availableOn must be a date in the form YYYY-MM-DD")
+		}
+	}
+
+	tags := parseTags(query)
 
 	params := types.SearchParams{
 		Latitude:  lat,
 		Longitude: long,
 		Radius:    rad,
 		Query:     tags,
+
+		Profile:     c.QueryParam("profile"),
+		SortBy:      sortBy,
+		MaxDuration: maxDuration,
+
+		MinRate:      filters["minRate"],
+		MaxRate:      filters["maxRate"],
+		MinRating:    filters["minRating"],
+		VerifiedOnly: c.QueryParam("verified") == "true",
+		AvailableOn:  availableOn,
 	}
 
 	return &params, nil
 }
+
+// Tags are comma separated with underscores in place of spaces
+func parseTags(query string) []string {
+	queryNoUnderscore := strings.ReplaceAll(query, "_", " ")
+
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(queryNoUnderscore, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
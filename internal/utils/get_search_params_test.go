@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSearchParamsFilters(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/public/services/search?lat=7.07&long=125.61&q=plumber&minRate=100&maxRate=500&minRating=4&verified=true&availableOn=2026-10-20&sort=rate", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	params, err := GetSearchParams(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500.0, params.Radius)
+	assert.Equal(t, 100.0, params.MinRate)
+	assert.Equal(t, 500.0, params.MaxRate)
+	assert.Equal(t, 4.0, params.MinRating)
+	assert.True(t, params.VerifiedOnly)
+	assert.Equal(t, "2026-10-20", params.AvailableOn)
+	assert.Equal(t, SORT_RATE, params.SortBy)
+}
+
+func TestGetSearchParamsInvalidFilters(t *testing.T) {
+	queries := []string{
+		"minRate=abc",
+		"minRate=600&maxRate=500",
+		"minRating=6",
+		"availableOn=20-10-2026",
+		"sort=cheapest",
+	}
+
+	e := echo.New()
+	for _, query := range queries {
+		req := httptest.NewRequest(http.MethodGet, "/v1/public/services/search?lat=7.07&long=125.61&q=plumber&"+query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		_, err := GetSearchParams(c)
+
+		assert.Error(t, err, query)
+	}
+}
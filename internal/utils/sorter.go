@@ -1,16 +1,50 @@
 package utils
 
-import "nearbyassist/internal/response"
-
-// Sort the services based on Suggestability in descending order
-func BubbleSort(elements []response.SearchResult) []response.SearchResult {
-	for i := 0; i < len(elements); i++ {
-		for j := 0; j < len(elements)-1; j++ {
-			if elements[j].Suggestability < elements[j+1].Suggestability {
-				elements[j], elements[j+1] = elements[j+1], elements[j]
-			}
-		}
+import (
+	"nearbyassist/internal/response"
+	"sort"
+)
+
+const (
+	SORT_RELEVANCE = "relevance"
+	SORT_DISTANCE  = "distance"
+	SORT_DURATION  = "duration"
+	SORT_RATE      = "rate"
+	SORT_RATING    = "rating"
+)
+
+// Sorts search results in place. Relevance and rating are highest first,
+// the other keys are lowest first. Results missing the value are placed last
+// and ties keep their relevance order.
+func SortSearchResults(results []response.SearchResult, sortBy string) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Suggestability > results[j].Suggestability
+	})
+
+	var less func(a, b response.SearchResult) bool
+
+	switch sortBy {
+	case SORT_DISTANCE:
+		less = func(a, b response.SearchResult) bool { return lessOptional(a.Distance, b.Distance) }
+	case SORT_DURATION:
+		less = func(a, b response.SearchResult) bool { return lessOptional(a.Duration, b.Duration) }
+	case SORT_RATE:
+		less = func(a, b response.SearchResult) bool { return a.Rate < b.Rate }
+	case SORT_RATING:
+		less = func(a, b response.SearchResult) bool { return a.Rating > b.Rating }
+	default:
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return less(results[i], results[j])
+	})
+}
+
+func lessOptional(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a != nil
 	}
 
-	return elements
+	return *a < *b
 }
@@ -2,56 +2,49 @@ package utils
 
 import (
 	"nearbyassist/internal/response"
-	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
-type testPair struct {
-	name     string
-	input    []response.SearchResult
-	expected []response.SearchResult
+func float(value float64) *float64 {
+	return &value
 }
 
-func TestBubbleSort(t *testing.T) {
-	tests := []testPair{
-		{
-			name: "test 1",
-			input: []response.SearchResult{
-				{Suggestability: 0.5},
-				{Suggestability: 0.4},
-				{Suggestability: 0.3},
-				{Suggestability: 0.2},
-				{Suggestability: 0.1},
-			},
-			expected: []response.SearchResult{
-                {Suggestability: 0.5},
-                {Suggestability: 0.4},
-                {Suggestability: 0.3},
-				{Suggestability: 0.2},
-                {Suggestability: 0.1},
-			},
-		},
-		{
-			name: "test 2",
-			input: []response.SearchResult{
-				{Suggestability: 0.02},
-				{Suggestability: 0.001},
-				{Suggestability: 0.03},
-			},
-			expected: []response.SearchResult{
-                {Suggestability: 0.03},
-                {Suggestability: 0.02},
-				{Suggestability: 0.001},
-			},
-		},
+func ids(results []response.SearchResult) []int {
+	ids := make([]int, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.Id)
 	}
 
-	for _, test := range tests {
-		result := BubbleSort(test.input)
+	return ids
+}
 
-		if reflect.DeepEqual(result, test.expected) == false {
-            t.Logf("Test: %v\n", test.name)
-			t.Errorf("\nExpected: %v \nGot: %v\n", test.expected, result)
+func TestSortSearchResults(t *testing.T) {
+	input := func() []response.SearchResult {
+		return []response.SearchResult{
+			{Id: 1, Suggestability: 0.2, Rate: 500, Rating: 4.5, Distance: float(300), Duration: float(60)},
+			{Id: 2, Suggestability: 0.9, Rate: 250, Rating: 3.0, Distance: float(900), Duration: nil},
+			{Id: 3, Suggestability: 0.5, Rate: 250, Rating: 5.0, Distance: nil, Duration: float(30)},
 		}
 	}
+
+	tests := []struct {
+		sortBy   string
+		expected []int
+	}{
+		{SORT_RELEVANCE, []int{2, 3, 1}},
+		{"", []int{2, 3, 1}},
+		{SORT_DISTANCE, []int{1, 2, 3}},
+		{SORT_DURATION, []int{3, 1, 2}},
+		{SORT_RATE, []int{2, 3, 1}},
+		{SORT_RATING, []int{3, 1, 2}},
+	}
+
+	for _, test := range tests {
+		results := input()
+		SortSearchResults(results, test.sortBy)
+
+		assert.Equal(t, test.expected, ids(results), test.sortBy)
+	}
 }
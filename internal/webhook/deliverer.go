@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/encryption"
+	"nearbyassist/internal/events"
+	"nearbyassist/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DELIVERY_INTERVAL     = time.Second * 5
+	DELIVERY_BATCH_SIZE   = 20
+	DELIVERY_MAX_ATTEMPTS = 8
+	DELIVERY_TIMEOUT      = time.Second * 10
+
+	// Claimed deliveries are hidden from other deliverers this long, enough
+	// for a whole batch to time out
+	DELIVERY_LEASE = DELIVERY_TIMEOUT * DELIVERY_BATCH_SIZE * 2
+)
+
+// Body sent to partners
+type envelope struct {
+	Id        int              `json:"id"`
+	Event     models.EventType `json:"event"`
+	CreatedAt string           `json:"createdAt"`
+	Data      json.RawMessage  `json:"data"`
+}
+
+// Deliverer posts queued webhook deliveries to partner endpoints
+type Deliverer struct {
+	db       db.Database
+	crypto   encryption.Encryption
+	client   *http.Client
+	interval time.Duration
+}
+
+func NewDeliverer(db db.Database, crypto encryption.Encryption) *Deliverer {
+	return &Deliverer{
+		db:       db,
+		crypto:   crypto,
+		client:   &http.Client{Timeout: DELIVERY_TIMEOUT},
+		interval: DELIVERY_INTERVAL,
+	}
+}
+
+func (d *Deliverer) Run() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverPending(); err != nil {
+			log.Printf("error delivering webhooks: %s\n", err.Error())
+		}
+
+		<-ticker.C
+	}
+}
+
+// Attempts one batch of deliveries and returns how many succeeded. Every
+// attempt is recorded, failures are retried with an exponential backoff.
+func (d *Deliverer) DeliverPending() (int, error) {
+	pending, err := d.db.FindPendingWebhookDeliveries(DELIVERY_BATCH_SIZE, DELIVERY_LEASE)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range pending {
+		start := time.Now()
+		code, err := d.deliver(delivery)
+
+		attempt := &models.WebhookDeliveryAttemptModel{
+			DeliveryId: delivery.Id,
+			DurationMs: int(time.Since(start).Milliseconds()),
+		}
+
+		if code != 0 {
+			attempt.ResponseCode = &code
+		}
+
+		if err != nil {
+			reason := err.Error()
+			attempt.Error = &reason
+
+			if err := d.db.MarkWebhookDeliveryFailed(attempt, events.Backoff(delivery.Attempts), DELIVERY_MAX_ATTEMPTS); err != nil {
+				return delivered, err
+			}
+
+			continue
+		}
+
+		if err := d.db.MarkWebhookDeliverySucceeded(attempt); err != nil {
+			return delivered, err
+		}
+
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// Returns the response code, or 0 when no response was received
+func (d *Deliverer) deliver(delivery models.WebhookDeliveryModel) (int, error) {
+	secret, err := d.crypto.DecryptString(delivery.Secret)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := json.Marshal(envelope{
+		Id:        delivery.Id,
+		Event:     delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DELIVERY_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EVENT_HEADER, string(delivery.EventType))
+	req.Header.Set(DELIVERY_HEADER, strconv.Itoa(delivery.Id))
+	req.Header.Set(TIMESTAMP_HEADER, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SIGNATURE_HEADER, Sign(secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"nearbyassist/internal/db/dbtest"
+	"nearbyassist/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type plainCrypto struct{}
+
+func (plainCrypto) EncryptString(text string) (string, error) { return text, nil }
+func (plainCrypto) DecryptString(text string) (string, error) { return text, nil }
+func (plainCrypto) EncryptFile(source []byte) ([]byte, error) { return source, nil }
+func (plainCrypto) DecryptFile(source []byte) ([]byte, error) { return source, nil }
+
+func TestDeliverPendingSignsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TIMESTAMP_HEADER), 10, 64)
+
+		assert.True(t, Verify("partner-secret-123", timestamp, body, r.Header.Get(SIGNATURE_HEADER)))
+		assert.Equal(t, string(models.EVENT_TRANSACTION_CREATED), r.Header.Get(EVENT_HEADER))
+		assert.Equal(t, "7", r.Header.Get(DELIVERY_HEADER))
+
+		payload := envelope{}
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.JSONEq(t, `{"transactionId":3}`, string(payload.Data))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	fake := dbtest.NewFake()
+	fake.WebhookDeliveries = []models.WebhookDeliveryModel{{
+		Model:     models.Model{Id: 7},
+		EventType: models.EVENT_TRANSACTION_CREATED,
+		Payload:   json.RawMessage(`{"transactionId":3}`),
+		Url:       srv.URL,
+		Secret:    "partner-secret-123",
+	}}
+
+	delivered, err := NewDeliverer(fake, plainCrypto{}).DeliverPending()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Len(t, fake.SucceededAttempts, 1)
+	assert.Equal(t, http.StatusNoContent, *fake.SucceededAttempts[0].ResponseCode)
+}
+
+func TestDeliverPendingRecordsFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	fake := dbtest.NewFake()
+	fake.WebhookDeliveries = []models.WebhookDeliveryModel{{
+		Model:     models.Model{Id: 1},
+		EventType: models.EVENT_VENDOR_RESTRICTED,
+		Payload:   json.RawMessage(`{}`),
+		Url:       srv.URL,
+		Secret:    "partner-secret-123",
+	}}
+
+	delivered, err := NewDeliverer(fake, plainCrypto{}).DeliverPending()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Len(t, fake.FailedAttempts, 1)
+	assert.Equal(t, http.StatusBadGateway, *fake.FailedAttempts[0].ResponseCode)
+	assert.Contains(t, *fake.FailedAttempts[0].Error, "502")
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	SIGNATURE_HEADER = "X-NearbyAssist-Signature"
+	TIMESTAMP_HEADER = "X-NearbyAssist-Timestamp"
+	EVENT_HEADER     = "X-NearbyAssist-Event"
+	DELIVERY_HEADER  = "X-NearbyAssist-Delivery"
+)
+
+// Signs "<timestamp>.<body>" with HMAC-SHA256. Including the timestamp lets
+// partners reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Checks a signature in constant time, partners can use the same scheme
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"nearbyassist/internal/db"
+	"nearbyassist/internal/events"
+	"nearbyassist/internal/models"
+)
+
+// Queues a delivery for every active webhook subscribed to the event. The
+// deliveries are unique per webhook and event so redispatching is harmless.
+func Subscribe(bus *events.Bus, db db.Database) {
+	for _, eventType := range models.WebhookEvents {
+		bus.Subscribe(eventType, "webhooks", func(event models.OutboxEventModel) error {
+			webhooks, err := db.FindActiveWebhooks()
+			if err != nil {
+				return err
+			}
+
+			for _, webhook := range webhooks {
+				if !webhook.Subscribes(event.EventType) {
+					continue
+				}
+
+				delivery := &models.WebhookDeliveryModel{
+					WebhookId: webhook.Id,
+					OutboxId:  event.Id,
+					EventType: event.EventType,
+					Payload:   event.Payload,
+				}
+
+				if err := db.NewWebhookDelivery(delivery); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+}
@@ -5,15 +5,29 @@ import (
 	"nearbyassist/internal/db"
 	"nearbyassist/internal/models"
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 )
 
+const EVENT_CHAT_ERROR = "chat_error"
+
+// Event is a non-chat frame pushed to a single connected user
+type Event struct {
+	Receiver int    `json:"-"`
+	Type     string `json:"type"`
+	Data     any    `json:"data"`
+}
+
 type Websocket struct {
+	// Guarded by mu, the chat handlers write it while the notifiers and the
+	// forwarder read it
 	Clients       map[int]*websocket.Conn
+	mu            sync.RWMutex
 	MessageChan   chan models.MessageModel
 	BroadcastChan chan models.MessageModel
+	EventChan     chan Event
 	DB            db.Database
 }
 
@@ -22,6 +36,7 @@ func NewWebsocket(db db.Database) *Websocket {
 		Clients:       make(map[int]*websocket.Conn),
 		MessageChan:   make(chan models.MessageModel),
 		BroadcastChan: make(chan models.MessageModel),
+		EventChan:     make(chan Event, 64),
 		DB:            db,
 	}
 }
@@ -43,9 +58,22 @@ func (w *Websocket) SaveMessages() {
 
 func (w *Websocket) ForwardMessages() {
 	for {
-		message := <-w.BroadcastChan
+		var message models.MessageModel
 
-		if socket, ok := w.Clients[message.Sender]; ok {
+		// Events are written from this goroutine as well since a connection
+		// only supports one concurrent writer
+		select {
+		case event := <-w.EventChan:
+			if socket, ok := w.client(event.Receiver); ok {
+				if err := socket.WriteJSON(event); err != nil {
+					fmt.Printf("error sending event to recipient: %s\n", err.Error())
+				}
+			}
+			continue
+		case message = <-w.BroadcastChan:
+		}
+
+		if socket, ok := w.client(message.Sender); ok {
 			err := socket.WriteJSON(message)
 			if err != nil {
 				fmt.Printf("error sending message to sender: %s\n", err.Error())
@@ -55,7 +83,7 @@ func (w *Websocket) ForwardMessages() {
 			continue
 		}
 
-		if socket, ok := w.Clients[message.Receiver]; ok {
+		if socket, ok := w.client(message.Receiver); ok {
 			err := socket.WriteJSON(message)
 			if err != nil {
 				fmt.Printf("error sending message to recipient: %s\n", err.Error())
@@ -68,6 +96,44 @@ func (w *Websocket) ForwardMessages() {
 	}
 }
 
+// Reports a rejected chat frame back to the user who sent it
+func (w *Websocket) SendError(userId int, err error) {
+	w.EventChan <- Event{
+		Receiver: userId,
+		Type:     EVENT_CHAT_ERROR,
+		Data: map[string]string{
+			"error": err.Error(),
+		},
+	}
+}
+
+func (w *Websocket) IsOnline(userId int) bool {
+	_, ok := w.client(userId)
+	return ok
+}
+
+func (w *Websocket) AddClient(userId int, conn *websocket.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.Clients[userId] = conn
+}
+
+func (w *Websocket) RemoveClient(userId int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.Clients, userId)
+}
+
+func (w *Websocket) client(userId int) (*websocket.Conn, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	conn, ok := w.Clients[userId]
+	return conn, ok
+}
+
 func (w *Websocket) Upgrade(c echo.Context) (*websocket.Conn, error) {
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {